@@ -0,0 +1,613 @@
+package logger
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// NewAllowlist wraps backing so that only fields whose keys are in allowed are logged. Fields with other keys are
+// dropped, their number is logged under the key "dropped_fields". Methods that add several fields, like AddErr or
+// AddQuery, are dropped completely unless all of their keys are allowed. Fields of the backing logger itself are not
+// filtered. The retention class is exempt as well: it is logged under "retention" whether set with Retention or
+// DefaultRetention, even if "retention" is not in allowed.
+func NewAllowlist(backing Logger, allowed []string) Logger {
+	set := make(map[string]bool, len(allowed))
+	for _, k := range allowed {
+		set[k] = true
+	}
+	return newAllowlist(backing, set)
+}
+
+// errKeys returns the keys AddErr of l uses, see ErrorKeys
+func errKeys(l Logger) (key, stackKey string) {
+	c := configOf(l)
+	if c == nil {
+		return "err", "err_stack"
+	}
+	return c.errKey, c.errStackKey
+}
+
+// aLog wraps a Logger so that its entries only log allowed fields. See NewAllowlist.
+type aLog struct {
+	wrapLog
+	allowed map[string]bool
+}
+
+var _ Logger = (*aLog)(nil)
+
+// newAllowlist returns a Logger whose entries only log the allowed fields to l
+func newAllowlist(l Logger, allowed map[string]bool) Logger {
+	a := &aLog{allowed: allowed}
+	a.wrapLog = wrapLog{l, a}
+	return a
+}
+
+// with returns a logger with the same allowed keys that wraps l
+func (a *aLog) with(l Logger) Logger {
+	return newAllowlist(l, a.allowed)
+}
+
+// entry returns a new entry that drops the fields of e that aren't allowed
+func (a *aLog) entry(e Entry, lvl Level) Entry {
+	ae := &aEntry{allowed: a.allowed, l: a.l}
+	ae.wrapEntry = wrapEntry{e: e, lvl: lvl, self: ae}
+	return ae
+}
+
+// WithField returns a new Logger that always logs the specified field
+func (a *aLog) WithField(key, value string) Logger {
+	if !a.allowed[key] {
+		return a
+	}
+	return a.with(a.l.WithField(key, value))
+}
+
+// WithAny returns a new Logger that always logs the specified field. Unlike WithField, the value can be of any type
+func (a *aLog) WithAny(key string, value interface{}) Logger {
+	if !a.allowed[key] {
+		return a
+	}
+	return a.with(a.l.WithAny(key, value))
+}
+
+// aEntry drops fields that are not allowed and counts them
+type aEntry struct {
+	wrapEntry
+	allowed map[string]bool
+	dropped int
+	// l is the backing logger, it determines the keys of AddErr
+	l Logger
+}
+
+var _ Entry = (*aEntry)(nil)
+
+// allow reports whether all keys are allowed. Otherwise, all keys are counted as dropped.
+func (a *aEntry) allow(keys ...string) bool {
+	for _, k := range keys {
+		if !a.allowed[k] {
+			a.dropped += len(keys)
+			return false
+		}
+	}
+	return true
+}
+
+// Flush writes the entry as a single log statement. Optionally, a message can be added which will
+// be included in the final log entry
+func (a *aEntry) Flush(msg string) {
+	if a.dropped > 0 {
+		a.e = a.e.AddInt("dropped_fields", a.dropped)
+	}
+	a.e.Flush(msg)
+}
+
+// AddFields adds a range of fields to the log statement
+func (a *aEntry) AddFields(fs map[string]interface{}) Entry {
+	res := make(map[string]interface{}, len(fs))
+	for k, v := range fs {
+		if a.allow(k) {
+			res[k] = v
+		}
+	}
+	a.e = a.e.AddFields(res)
+	return a
+}
+
+// AddErr adds an error to the log statement. The error will have the key "err". An error stack will be included
+// under the key "err_stack". Use the option ErrorKeys to change the keys.
+func (a *aEntry) AddErr(err error) Entry {
+	if a.allow(errKeys(a.l)) {
+		a.e = a.e.AddErr(err)
+	}
+	return a
+}
+
+// AddError adds an error to the log statement. An error stack will be included under the key "${key}_stack"
+func (a *aEntry) AddError(key string, val error) Entry {
+	if a.allow(key, key+"_stack") {
+		a.e = a.e.AddError(key, val)
+	}
+	return a
+}
+
+// AddBool adds a bool value to the log statement.
+func (a *aEntry) AddBool(key string, val bool) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddBool(key, val)
+	}
+	return a
+}
+
+// AddInt adds an integer value to the log statement.
+func (a *aEntry) AddInt(key string, val int) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddInt(key, val)
+	}
+	return a
+}
+
+// AddStr adds a string value to the log statement.
+func (a *aEntry) AddStr(key string, val string) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddStr(key, val)
+	}
+	return a
+}
+
+// AddTime adds a time value to the log statement.
+func (a *aEntry) AddTime(key string, val time.Time) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddTime(key, val)
+	}
+	return a
+}
+
+// AddDur adds a duration value to the log statement.
+func (a *aEntry) AddDur(key string, val time.Duration) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddDur(key, val)
+	}
+	return a
+}
+
+// AddAny adds any value to the log statement.
+func (a *aEntry) AddAny(key string, val interface{}) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddAny(key, val)
+	}
+	return a
+}
+
+// AddJSONRaw adds pre-serialized JSON to the log statement without encoding it again. If raw is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (a *aEntry) AddJSONRaw(key string, raw []byte) Entry {
+	k := key
+	if !json.Valid(raw) {
+		k = key + "_invalid"
+	}
+	if a.allow(k) {
+		a.e = a.e.AddJSONRaw(key, raw)
+	}
+	return a
+}
+
+// AddCount adds a count to the log statement. The number is stored under key, a human readable form like "3 items"
+// is stored under the key "${key}_human"
+func (a *aEntry) AddCount(key string, n int, singular, plural string) Entry {
+	if a.allow(key, key+"_human") {
+		a.e = a.e.AddCount(key, n, singular, plural)
+	}
+	return a
+}
+
+// AddQuery adds a database query to the log statement. The statement, its arguments as JSON array, the number of
+// rows and the duration are stored under the keys "db.sql", "db.args", "db.rows" and "db.duration"
+func (a *aEntry) AddQuery(sql string, args []interface{}, rows int, dur time.Duration) Entry {
+	if a.allow("db.sql", "db.args", "db.rows", "db.duration") {
+		a.e = a.e.AddQuery(sql, args, rows, dur)
+	}
+	return a
+}
+
+// AddTraceparent adds the trace id and span id of the W3C traceparent header in headers under the keys
+// "${key}_trace_id" and "${key}_span_id". A missing or malformed header adds nothing.
+func (a *aEntry) AddTraceparent(key string, headers http.Header) Entry {
+	if a.allow(key+"_trace_id", key+"_span_id") {
+		a.e = a.e.AddTraceparent(key, headers)
+	}
+	return a
+}
+
+// AddMetric adds a metric to the log statement. Name, value and tags are stored as a nested object under the key
+// "metric", so that log collectors can route the statement to a metrics sink.
+func (a *aEntry) AddMetric(name string, value float64, tags map[string]string) Entry {
+	if a.allow("metric") {
+		a.e = a.e.AddMetric(name, value, tags)
+	}
+	return a
+}
+
+// AddHex adds a byte slice to the log statement, encoded as hex string.
+func (a *aEntry) AddHex(key string, val []byte) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddHex(key, val)
+	}
+	return a
+}
+
+// AddBase64 adds a byte slice to the log statement, encoded as base64 string with standard encoding.
+func (a *aEntry) AddBase64(key string, val []byte) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddBase64(key, val)
+	}
+	return a
+}
+
+// AddElapsed adds the duration since the specified time to the log statement.
+func (a *aEntry) AddElapsed(key string, since time.Time) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddElapsed(key, since)
+	}
+	return a
+}
+
+// AddDurHuman adds a duration to the log statement as human readable string like "1.2s" or "3m04s". Use AddDur for
+// values that should be queryable.
+func (a *aEntry) AddDurHuman(key string, d time.Duration) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddDurHuman(key, d)
+	}
+	return a
+}
+
+// AddErrN adds an error to the log statement like AddErr, but the error stack under the key "err_stack" is
+// truncated to the first maxFrames frames. The error message is kept intact.
+func (a *aEntry) AddErrN(err error, maxFrames int) Entry {
+	if a.allow(errKeys(a.l)) {
+		a.e = a.e.AddErrN(err, maxFrames)
+	}
+	return a
+}
+
+// AddSensitive adds the hex encoded SHA-256 hash of val under the key "${key}_hash" to the log statement. The raw
+// value is never logged. Use the options SensitiveSalt and SensitiveHashLen to configure the hash.
+func (a *aEntry) AddSensitive(key string, val string) Entry {
+	if a.allow(key + "_hash") {
+		a.e = a.e.AddSensitive(key, val)
+	}
+	return a
+}
+
+// AddBytesLen adds the length of a byte slice to the log statement instead of its content. A nil slice has length 0.
+func (a *aEntry) AddBytesLen(key string, val []byte) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddBytesLen(key, val)
+	}
+	return a
+}
+
+// AddStrLen adds the number of runes of a string to the log statement instead of the string itself.
+func (a *aEntry) AddStrLen(key string, val string) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddStrLen(key, val)
+	}
+	return a
+}
+
+// AddJSONString adds a JSON encoded string to the log statement without encoding it again. If s is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (a *aEntry) AddJSONString(key, s string) Entry {
+	k := key
+	if !json.Valid([]byte(s)) {
+		k = key + "_invalid"
+	}
+	if a.allow(k) {
+		a.e = a.e.AddJSONString(key, s)
+	}
+	return a
+}
+
+// AddRetry adds the state of a retry loop to the log statement. The attempt, the maximum number of attempts and the
+// backoff before the next attempt are stored under the keys "retry.attempt", "retry.max" and "retry.backoff"
+func (a *aEntry) AddRetry(attempt, max int, backoff time.Duration) Entry {
+	if a.allow("retry.attempt", "retry.max", "retry.backoff") {
+		a.e = a.e.AddRetry(attempt, max, backoff)
+	}
+	return a
+}
+
+// AddDeadline adds the time remaining until the deadline of ctx to the log statement. It is negative if the deadline
+// has passed. If ctx has no deadline, nothing is added.
+func (a *aEntry) AddDeadline(key string, ctx context.Context) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddDeadline(key, ctx)
+	}
+	return a
+}
+
+// AddRequest adds the method, path, remote address, user agent and host of r to the log statement under the keys
+// "http.method", "http.path", "http.remote_addr", "http.user_agent" and "http.host". Other headers are never logged.
+func (a *aEntry) AddRequest(r *http.Request) Entry {
+	if a.allow("http.method", "http.path", "http.remote_addr", "http.user_agent", "http.host") {
+		a.e = a.e.AddRequest(r)
+	}
+	return a
+}
+
+// AddResponse adds the status code, the size of the body and the duration of an HTTP response to the log statement
+// under the keys "http.status", "http.bytes" and "http.duration". The class of the status like "5xx" is stored under
+// the key "http.status_class".
+func (a *aEntry) AddResponse(status, bytes int, dur time.Duration) Entry {
+	if a.allow("http.status", "http.status_class", "http.bytes", "http.duration") {
+		a.e = a.e.AddResponse(status, bytes, dur)
+	}
+	return a
+}
+
+// AddSlice adds a slice or an array of any element type to the log statement as JSON array. Other values, or slices
+// that can't be encoded as JSON, are added like with AddAny.
+func (a *aEntry) AddSlice(key string, vals interface{}) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddSlice(key, vals)
+	}
+	return a
+}
+
+// AddBoolPtr adds the value of a bool pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (a *aEntry) AddBoolPtr(key string, val *bool) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddBoolPtr(key, val)
+	}
+	return a
+}
+
+// AddIntPtr adds the value of an integer pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (a *aEntry) AddIntPtr(key string, val *int) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddIntPtr(key, val)
+	}
+	return a
+}
+
+// AddStrPtr adds the value of a string pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (a *aEntry) AddStrPtr(key string, val *string) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddStrPtr(key, val)
+	}
+	return a
+}
+
+// AddCaller adds the location that called it as "file:line" under the key "caller" and the calling function under
+// the key "caller_func" to the log statement. Unlike the option ReportCaller, it only applies to this entry.
+func (a *aEntry) AddCaller() Entry {
+	if a.allow("caller", "caller_func") {
+		a.e = a.e.AddCaller()
+	}
+	return a
+}
+
+// AddCallerSkip adds the caller like AddCaller, but skips n additional frames, e.g. in helpers that wrap an Entry.
+func (a *aEntry) AddCallerSkip(n int) Entry {
+	if a.allow("caller", "caller_func") {
+		a.e = a.e.AddCallerSkip(n)
+	}
+	return a
+}
+
+// AddIntThreshold adds a value to the log statement like AddInt and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (a *aEntry) AddIntThreshold(key string, val, threshold int, flagKey string) Entry {
+	if a.allow(key, flagKey) {
+		a.e = a.e.AddIntThreshold(key, val, threshold, flagKey)
+	}
+	return a
+}
+
+// AddDurThreshold adds a value to the log statement like AddDur and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (a *aEntry) AddDurThreshold(key string, val, threshold time.Duration, flagKey string) Entry {
+	if a.allow(key, flagKey) {
+		a.e = a.e.AddDurThreshold(key, val, threshold, flagKey)
+	}
+	return a
+}
+
+// AddMoney adds an amount of money in minor units of an ISO 4217 currency, e.g. cents for "USD", to the log statement.
+// The amount and the currency are stored under the keys "${key}.amount_minor" and "${key}.currency", the formatted
+// amount like "$12.99" under the key "${key}.display". Unknown currencies, including an empty code, are formatted
+// with two decimal places and flagged with true under the key "${key}.currency_unknown".
+func (a *aEntry) AddMoney(key string, minorUnits int64, currency string) Entry {
+	if a.allow(key+".amount_minor", key+".currency", key+".display", key+".currency_unknown") {
+		a.e = a.e.AddMoney(key, minorUnits, currency)
+	}
+	return a
+}
+
+// AddErrChain adds the message of an error under key, the number of errors it wraps under the key "${key}_depth" and
+// the type names of the errors in its chain, starting with err, under the key "${key}_types" to the log statement.
+// Both the standard library's wrapping and juju/errors are followed. A nil error adds nothing.
+func (a *aEntry) AddErrChain(key string, err error) Entry {
+	if a.allow(key, key+"_depth", key+"_types") {
+		a.e = a.e.AddErrChain(key, err)
+	}
+	return a
+}
+
+// AddInterval adds a time window to the log statement. Start and end are stored in UTC under the keys "${key}.start"
+// and "${key}.end", the duration between them under the key "${key}.duration". If end is before start, the duration
+// is negative.
+func (a *aEntry) AddInterval(key string, start, end time.Time) Entry {
+	if a.allow(key+".start", key+".end", key+".duration") {
+		a.e = a.e.AddInterval(key, start, end)
+	}
+	return a
+}
+
+// AddValidationErrors adds the results of a failed validation to the log statement as nested object that maps each
+// invalid field to its error message, e.g. {"email": "must not be empty"}. A nil or empty map adds nothing.
+func (a *aEntry) AddValidationErrors(key string, errs map[string]string) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddValidationErrors(key, errs)
+	}
+	return a
+}
+
+// AddGeo adds coordinates to the log statement as nested object with the keys "lat" and "lon", which Elasticsearch
+// maps as geo_point. If the latitude isn't within [-90, 90] or the longitude isn't within [-180, 180], the
+// coordinates are still added and "${key}_invalid" is set to true.
+func (a *aEntry) AddGeo(key string, lat, lon float64) Entry {
+	if !a.allow(key) {
+		return a
+	}
+	if !(geoPoint{lat, lon}).valid() && !a.allowed[key+"_invalid"] {
+		// only the flag is dropped, the coordinates are added like AddGeo does
+		a.dropped++
+		a.e = a.e.AddAny(key, map[string]interface{}{"lat": lat, "lon": lon})
+		return a
+	}
+	a.e = a.e.AddGeo(key, lat, lon)
+	return a
+}
+
+// AddDiff adds the old and the new value of a change to the log statement as nested object with the keys "old" and
+// "new". If both values are equal according to reflect.DeepEqual, nothing is added.
+func (a *aEntry) AddDiff(key string, oldVal, newVal interface{}) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddDiff(key, oldVal, newVal)
+	}
+	return a
+}
+
+// AddRuntime adds diagnostics of the Go runtime to the log statement: the Go version, the operating system and the
+// architecture under the keys "go.version", "go.os" and "go.arch", the number of goroutines under the key
+// "go.goroutines" and the allocated heap in MiB under the key "go.heap_mb". Reading the heap stops the world, so use
+// it for rare entries like crash reports, not in hot paths.
+func (a *aEntry) AddRuntime() Entry {
+	if a.allow("go.version", "go.os", "go.arch", "go.goroutines", "go.heap_mb") {
+		a.e = a.e.AddRuntime()
+	}
+	return a
+}
+
+// AddForm adds the values of the listed form fields to the log statement under the keys "form.${name}". Multiple
+// values of a field are joined with commas. Fields that aren't listed are never added, e.g. passwords, and listed
+// fields missing from values are skipped.
+func (a *aEntry) AddForm(values url.Values, keys ...string) Entry {
+	allowed := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if a.allow("form." + k) {
+			allowed = append(allowed, k)
+		}
+	}
+	if len(allowed) > 0 {
+		a.e = a.e.AddForm(values, allowed...)
+	}
+	return a
+}
+
+// AddBackoff computes the wait before the next attempt of a retry loop and adds it to the log statement together with
+// the attempt under the keys "backoff.wait" and "backoff.attempt". The wait doubles with each attempt, starting at
+// base for attempt 1, and is capped at max. Half of it is random jitter, so that clients don't retry in lockstep.
+// The wait is returned so that the caller can sleep on it.
+func (a *aEntry) AddBackoff(attempt int, base, max time.Duration) (Entry, time.Duration) {
+	if !a.allow("backoff.attempt", "backoff.wait") {
+		return a, backoff(attempt, base, max)
+	}
+	e, d := a.e.AddBackoff(attempt, base, max)
+	a.e = e
+	return a, d
+}
+
+// AddLabels adds a set of labels like environment, team or service to the log statement as nested object under the
+// key "labels". Label names that don't match the Prometheus label name syntax [a-zA-Z_][a-zA-Z0-9_]* are still added
+// and listed under the key "labels_invalid". Labels of a logger created by WithLabels are merged with labels, with
+// labels taking precedence. A nil or empty map adds nothing.
+func (a *aEntry) AddLabels(labels map[string]string) Entry {
+	if a.allow("labels", "labels_invalid") {
+		a.e = a.e.AddLabels(labels)
+	}
+	return a
+}
+
+// AddPercent adds a fraction like a success ratio to the log statement under key and the same value as percentage
+// string with one decimal under "${key}_pct", e.g. 0.873 as "87.3%", so that dashboards don't mix fractions and
+// percentages. A fraction outside [0, 1] is clamped and "${key}_invalid" is set to true, NaN is clamped to 0.
+func (a *aEntry) AddPercent(key string, fraction float64) Entry {
+	if a.allow(key, key+"_pct", key+"_invalid") {
+		a.e = a.e.AddPercent(key, fraction)
+	}
+	return a
+}
+
+// AddNullString adds the value of a sql.NullString to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (a *aEntry) AddNullString(key string, val sql.NullString) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddNullString(key, val)
+	}
+	return a
+}
+
+// AddNullInt64 adds the value of a sql.NullInt64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (a *aEntry) AddNullInt64(key string, val sql.NullInt64) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddNullInt64(key, val)
+	}
+	return a
+}
+
+// AddNullFloat64 adds the value of a sql.NullFloat64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (a *aEntry) AddNullFloat64(key string, val sql.NullFloat64) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddNullFloat64(key, val)
+	}
+	return a
+}
+
+// AddNullBool adds the value of a sql.NullBool to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (a *aEntry) AddNullBool(key string, val sql.NullBool) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddNullBool(key, val)
+	}
+	return a
+}
+
+// AddKV adds alternating keys and values to the log statement, e.g. AddKV("user", id, "ok", true), like AddAny for
+// each pair. It trades the type safety of the typed methods for brevity. Keys that aren't strings are formatted with
+// fmt.Sprint. If the number of arguments is odd, the last key is added under "kv_warning" as missing its value.
+func (a *aEntry) AddKV(pairs ...interface{}) Entry {
+	return addKV(a, pairs)
+}
+
+// AddBigRat adds an arbitrary-precision rational number to the log statement as string, so that exact values like
+// amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default. nil
+// is added as "<nil>".
+func (a *aEntry) AddBigRat(key string, val *big.Rat) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddBigRat(key, val)
+	}
+	return a
+}
+
+// AddBigFloat adds an arbitrary-precision floating-point number to the log statement as string, so that exact values
+// like amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default.
+// nil is added as "<nil>".
+func (a *aEntry) AddBigFloat(key string, val *big.Float) Entry {
+	if a.allow(key) {
+		a.e = a.e.AddBigFloat(key, val)
+	}
+	return a
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAllowlist(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := NewAllowlist(New(&sb, DebugLevel, impl), []string{"request_id", "status", "err", "err_stack"})
+		l = l.WithField("request_id", "req1").WithField("email", "a@b.c")
+		l.Info().
+			AddInt("status", 200).
+			AddStr("password", "secret").
+			AddFields(map[string]interface{}{"status": 200, "token": "secret"}).
+			AddErr(errors.New("failed")).
+			AddError("other", errors.New("secret")).
+			Flush("")
+		s := sb.String()
+		assert.Contains(t, s, "req1", "Allowed logger field should be logged")
+		assert.Contains(t, s, "200", "Allowed field should be logged")
+		assert.Contains(t, s, "failed", "Allowed error should be logged")
+		assert.NotContains(t, s, "a@b.c", "Logger field should be dropped")
+		assert.NotContains(t, s, "secret", "Fields should be dropped")
+		assert.Contains(t, s, "dropped_fields", "Dropped fields should be counted")
+	}
+}
+
+func TestNewAllowlist_Count(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 2)
+	l = NewAllowlist(l, []string{"status"})
+	l.Info().AddInt("status", 200).Flush("")
+	e := <-ch
+	assert.Equal(t, 200, e.Fields["status"], "Allowed field should be logged")
+	assert.NotContains(t, e.Fields, "dropped_fields", "Entries without dropped fields should not count them")
+
+	l.Info().AddStr("password", "secret").AddQuery("SELECT 1", nil, 1, 0).AddJSONRaw("status", []byte("{no json")).Flush("")
+	e = <-ch
+	assert.Equal(t, map[string]interface{}{"dropped_fields": 6}, e.Fields, "All keys of dropped calls should be counted")
+}
+
+func TestNewAllowlist_AddPercent(t *testing.T) {
+	c, ch := NewChannel(DebugLevel, 2)
+	l := NewAllowlist(c, []string{"ratio"})
+	l.Info().AddPercent("ratio", 1.5).Flush("")
+	e := <-ch
+	assert.NotContains(t, e.Fields, "ratio_pct", "Percentage should be dropped")
+	assert.NotContains(t, e.Fields, "ratio_invalid", "Invalid flag should be dropped")
+	assert.Equal(t, 3, e.Fields["dropped_fields"], "All keys of AddPercent should be counted")
+
+	l = NewAllowlist(c, []string{"ratio", "ratio_pct", "ratio_invalid"})
+	l.Info().AddPercent("ratio", 0.5).Flush("")
+	e = <-ch
+	assert.Equal(t, "50.0%", e.Fields["ratio_pct"], "Allowed percentage should be logged")
+}
+
+func TestNewAllowlist_AddMoney(t *testing.T) {
+	c, ch := NewChannel(DebugLevel, 2)
+	l := NewAllowlist(c, []string{"price.amount_minor", "price.currency", "price.display"})
+	l.Info().AddMoney("price", 1299, "XXX").Flush("")
+	e := <-ch
+	assert.NotContains(t, e.Fields, "price.currency_unknown", "Unknown currency flag should be dropped")
+	assert.Equal(t, 4, e.Fields["dropped_fields"], "All keys of AddMoney should be counted")
+
+	l = NewAllowlist(c, []string{"price.amount_minor", "price.currency", "price.display", "price.currency_unknown"})
+	l.Info().AddMoney("price", 1299, "XXX").Flush("")
+	e = <-ch
+	assert.Equal(t, true, e.Fields["price.currency_unknown"], "Allowed flag should be logged")
+}
+
+func TestNewAllowlist_AddLabels(t *testing.T) {
+	c, ch := NewChannel(DebugLevel, 2)
+	l := NewAllowlist(c, []string{"labels"})
+	l.Info().AddLabels(map[string]string{"team-name": "core"}).Flush("")
+	e := <-ch
+	assert.NotContains(t, e.Fields, "labels_invalid", "Invalid labels should be dropped")
+	assert.Equal(t, 2, e.Fields["dropped_fields"], "All keys of AddLabels should be counted")
+
+	l.WithLabels(map[string]string{"team-name": "core"}).Info().Flush("")
+	e = <-ch
+	assert.NotContains(t, e.Fields, "labels_invalid", "Invalid labels of WithLabels should be dropped")
+
+	l = NewAllowlist(c, []string{"labels", "labels_invalid"})
+	l.WithLabels(map[string]string{"team-name": "core"}).Info().Flush("")
+	e = <-ch
+	assert.Contains(t, e.Fields, "labels_invalid", "Allowed invalid labels should be logged")
+}
+
+func TestNewAllowlist_AddGeo(t *testing.T) {
+	c, ch := NewChannel(DebugLevel, 2)
+	l := NewAllowlist(c, []string{"geo"})
+	l.Info().AddGeo("geo", 52.52, 13.405).Flush("")
+	e := <-ch
+	assert.Contains(t, e.Fields, "geo", "Valid coordinates should be logged without the invalid flag on the allowlist")
+	assert.NotContains(t, e.Fields, "dropped_fields", "Nothing should be dropped for valid coordinates")
+
+	l.Info().AddGeo("geo", 91, 0).Flush("")
+	e = <-ch
+	assert.Contains(t, e.Fields, "geo", "Invalid coordinates should be logged")
+	assert.NotContains(t, e.Fields, "geo_invalid", "Invalid flag should be dropped")
+	assert.Equal(t, 1, e.Fields["dropped_fields"], "Dropped flag should be counted")
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// auditWriter adds a sequence number and optionally a hash chain to each entry written to it. The backends write each
+// entry with a single call to Write.
+type auditWriter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	seq   uint64
+	chain bool
+	// prev is the hash of the previous entry
+	prev string
+	buf  []byte
+	// prefix is added to the keys of JSON entries, "_" for GELF, whose additional fields need it
+	prefix string
+}
+
+func newAuditWriter(w io.Writer, c *config, impl Implementation) *auditWriter {
+	a := &auditWriter{w: w, seq: c.auditStart, chain: c.auditChain}
+	if impl == GelfBackend {
+		a.prefix = "_"
+	}
+	return a
+}
+
+// Write adds the fields "seq" and, with a hash chain, "prev_hash" and "hash" to the entry in p and writes it to the
+// underlying writer. JSON objects get the fields as last members, with the prefix "_" for GELF, other entries like
+// the ones of logrus' text formatter get them appended as key=value pairs. The hash is the hex encoded SHA-256 hash
+// of the entry including "seq" and "prev_hash", up to but excluding the hash field itself.
+func (a *auditWriter) Write(p []byte) (int, error) {
+	line := bytes.TrimRight(p, "\r\n")
+	eol := p[len(line):]
+	obj := len(line) > 0 && line[len(line)-1] == '}'
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b := a.buf[:0]
+	if obj {
+		body := bytes.TrimRight(line[:len(line)-1], " ")
+		b = append(b, body...)
+		if len(body) > 0 && body[len(body)-1] != '{' {
+			b = append(b, ',')
+		}
+		b = a.appendKey(b, "seq")
+		b = strconv.AppendUint(b, a.seq, 10)
+		if a.chain {
+			b = append(b, ',')
+			b = a.appendKey(b, "prev_hash")
+			b = strconv.AppendQuote(b, a.prev)
+		}
+	} else {
+		b = append(b, line...)
+		b = append(b, " seq="...)
+		b = strconv.AppendUint(b, a.seq, 10)
+		if a.chain {
+			b = append(b, " prev_hash="...)
+			b = append(b, a.prev...)
+		}
+	}
+	var hash string
+	if a.chain {
+		sum := sha256.Sum256(b)
+		hash = hex.EncodeToString(sum[:])
+		if obj {
+			b = append(b, ',')
+			b = a.appendKey(b, "hash")
+			b = strconv.AppendQuote(b, hash)
+		} else {
+			b = append(b, " hash="...)
+			b = append(b, hash...)
+		}
+	}
+	if obj {
+		b = append(b, '}')
+	}
+	b = append(b, eol...)
+	a.buf = b
+	// advance even if the write fails, so that the lost entry shows up as a gap
+	a.seq++
+	a.prev = hash
+	if _, err := a.w.Write(b); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// appendKey appends key with the prefix of a as member name of a JSON object to b
+func (a *auditWriter) appendKey(b []byte, key string) []byte {
+	b = append(b, '"')
+	b = append(b, a.prefix...)
+	b = append(b, key...)
+	return append(b, `":`...)
+}
+
+// Sync syncs the underlying writer if it supports it
+func (a *auditWriter) Sync() error {
+	return syncWriter(a.w)
+}
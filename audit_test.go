@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditMode(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl, AuditMode())
+		l.Info().Flush("first")
+		l.WithField("key", "val").Info().Flush("second")
+		lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+		if !assert.Len(t, lines, 2, "Each entry should be written") {
+			continue
+		}
+		if impl == LogrusBackend {
+			assert.True(t, strings.HasSuffix(lines[0], " seq=1"), "Text entries should end with the sequence")
+			assert.True(t, strings.HasSuffix(lines[1], " seq=2"), "Derived loggers should share the sequence")
+			continue
+		}
+		for i, line := range lines {
+			var m map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(line), &m), "Entry should stay valid JSON")
+			key := "seq"
+			if impl == GelfBackend {
+				key = "_seq"
+			}
+			assert.Equal(t, float64(i+1), m[key], "Sequence should be incremented per entry")
+		}
+	}
+}
+
+func TestAuditStartSeq(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend, AuditStartSeq(42))
+	l.Info().Flush("")
+	l.Debug().Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"seq":42}`, "Sequence should start at the configured number")
+	assert.Contains(t, s, `"seq":43}`, "Sequence should be incremented")
+}
+
+func TestAuditHashChain(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend, AuditHashChain())
+	for i := 0; i < 3; i++ {
+		l.Info().AddInt("i", i).Flush("message")
+	}
+	prev := ""
+	for i, line := range strings.Split(strings.TrimSpace(sb.String()), "\n") {
+		var m map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &m), "Entry should stay valid JSON")
+		assert.Equal(t, float64(i+1), m["seq"], "Sequence should be incremented per entry")
+		assert.Equal(t, prev, m["prev_hash"], "Entry should reference the hash of the previous entry")
+		hashed := line[:strings.Index(line, `,"hash":`)]
+		sum := sha256.Sum256([]byte(hashed))
+		assert.Equal(t, hex.EncodeToString(sum[:]), m["hash"], "Hash should cover the entry up to the hash field")
+		prev = m["hash"].(string)
+	}
+}
+
+func TestAuditHashChain_Gelf(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, GelfBackend, AuditHashChain())
+	l.WithField("a", "b").Info().Flush("hi")
+	l.Info().Flush("again")
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	prev := ""
+	for i, line := range lines {
+		var m map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &m), "Entry should stay valid JSON")
+		for k := range m {
+			if k != "version" && k != "host" && k != "short_message" && k != "timestamp" && k != "level" {
+				assert.True(t, strings.HasPrefix(k, "_"), "Additional field %q should have the prefix _", k)
+			}
+		}
+		assert.Equal(t, float64(i+1), m["_seq"], "Sequence should be incremented per entry")
+		assert.Equal(t, prev, m["_prev_hash"], "Entry should reference the hash of the previous entry")
+		hashed := line[:strings.Index(line, `,"_hash":`)]
+		sum := sha256.Sum256([]byte(hashed))
+		assert.Equal(t, hex.EncodeToString(sum[:]), m["_hash"], "Hash should cover the entry up to the hash field")
+		prev, _ = m["_hash"].(string)
+	}
+}
+
+func TestAuditWriter_Text(t *testing.T) {
+	var sb strings.Builder
+	w := newAuditWriter(&sb, &config{auditStart: 1, auditChain: true}, LogrusBackend)
+	_, err := w.Write([]byte("level=info msg=first\n"))
+	assert.NoError(t, err)
+	sum := sha256.Sum256([]byte("level=info msg=first seq=1 prev_hash="))
+	hash := hex.EncodeToString(sum[:])
+	assert.Equal(t, "level=info msg=first seq=1 prev_hash= hash="+hash+"\n", sb.String(),
+		"Fields should be appended to text entries")
+	_, _ = w.Write([]byte("{}\n"))
+	assert.Contains(t, sb.String(), `{"seq":2,"prev_hash":"`+hash+`","hash":"`, "Empty objects should stay valid JSON")
+}
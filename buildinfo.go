@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// BuildRevision and BuildTime are the revision and the time of the build that WithBuildInfo logs, e.g. set with
+// -ldflags "-X github.com/leononame/logger.BuildRevision=$(git rev-parse HEAD)". If they are empty, the VCS
+// information Go embeds in binaries since Go 1.18 is used.
+var (
+	BuildRevision string
+	BuildTime     string
+)
+
+var (
+	buildOnce   sync.Once
+	buildFields map[string]string
+)
+
+// buildInfo returns the fields "build.revision" and "build.time" that are known, read once. Without a revision set
+// with ldflags or embedded by Go, the version of the main module is used as revision, unless it's "(devel)" like for
+// go run.
+func buildInfo() map[string]string {
+	buildOnce.Do(func() {
+		rev, t := BuildRevision, BuildTime
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			vcsRev, vcsTime := vcsInfo(bi)
+			if rev == "" {
+				rev = vcsRev
+			}
+			if t == "" {
+				t = vcsTime
+			}
+			if rev == "" && bi.Main.Version != "(devel)" {
+				rev = bi.Main.Version
+			}
+		}
+		buildFields = make(map[string]string, 2)
+		if rev != "" {
+			buildFields["build.revision"] = rev
+		}
+		if t != "" {
+			buildFields["build.time"] = t
+		}
+	})
+	return buildFields
+}
+
+// withBuildInfo returns l with the fields of buildInfo
+func withBuildInfo(l Logger) Logger {
+	fs := buildInfo()
+	for _, k := range []string{"build.revision", "build.time"} {
+		if v, ok := fs[k]; ok {
+			l = l.WithField(k, v)
+		}
+	}
+	return l
+}
@@ -0,0 +1,11 @@
+//go:build !go1.18
+// +build !go1.18
+
+package logger
+
+import "runtime/debug"
+
+// vcsInfo returns nothing, Go embeds VCS settings only since Go 1.18
+func vcsInfo(*debug.BuildInfo) (rev, t string) {
+	return "", ""
+}
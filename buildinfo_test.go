@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// resetBuildInfo makes buildInfo read the build information again
+func resetBuildInfo() {
+	buildOnce = sync.Once{}
+	buildFields = nil
+}
+
+func TestWithBuildInfo(t *testing.T) {
+	BuildRevision, BuildTime = "abc123", "2019-03-01T12:00:00Z"
+	resetBuildInfo()
+	defer func() {
+		BuildRevision, BuildTime = "", ""
+		resetBuildInfo()
+	}()
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl, WithBuildInfo())
+		l.Info().Flush("")
+		l.WithField("key", "val").Info().Flush("")
+		s := sb.String()
+		assert.Equal(t, 2, strings.Count(s, "abc123"), "Each entry should contain the revision")
+		assert.Equal(t, 2, strings.Count(s, "2019-03-01T12:00:00Z"), "Each entry should contain the build time")
+	}
+
+	l, ch := NewChannel(DebugLevel, 1, WithBuildInfo())
+	l.Info().Flush("")
+	e := <-ch
+	assert.Equal(t, "abc123", e.Fields["build.revision"])
+	assert.Equal(t, "2019-03-01T12:00:00Z", e.Fields["build.time"])
+}
+
+func TestWithBuildInfo_Unknown(t *testing.T) {
+	resetBuildInfo()
+	defer resetBuildInfo()
+	// test binaries have neither VCS information nor a module version
+	l, ch := NewChannel(DebugLevel, 1, WithBuildInfo())
+	l.Info().Flush("")
+	e := <-ch
+	assert.NotContains(t, e.Fields, "build.revision", "An unknown revision should be omitted")
+	assert.NotContains(t, e.Fields, "build.time", "An unknown build time should be omitted")
+}
@@ -0,0 +1,26 @@
+//go:build go1.18
+// +build go1.18
+
+package logger
+
+import "runtime/debug"
+
+// vcsInfo returns the revision and the commit time of the VCS settings Go embeds since Go 1.18. A revision with
+// uncommitted changes gets the suffix "-dirty".
+func vcsInfo(bi *debug.BuildInfo) (rev, t string) {
+	modified := false
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			rev = s.Value
+		case "vcs.time":
+			t = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if rev != "" && modified {
+		rev += "-dirty"
+	}
+	return rev, t
+}
@@ -0,0 +1,31 @@
+//go:build go1.18
+// +build go1.18
+
+package logger
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVcsInfo(t *testing.T) {
+	bi := &debug.BuildInfo{Settings: []debug.BuildSetting{
+		{Key: "vcs", Value: "git"},
+		{Key: "vcs.revision", Value: "abc123"},
+		{Key: "vcs.time", Value: "2019-03-01T12:00:00Z"},
+		{Key: "vcs.modified", Value: "false"},
+	}}
+	rev, tm := vcsInfo(bi)
+	assert.Equal(t, "abc123", rev, "The revision should be read from the settings")
+	assert.Equal(t, "2019-03-01T12:00:00Z", tm, "The commit time should be read from the settings")
+
+	bi.Settings[3].Value = "true"
+	rev, _ = vcsInfo(bi)
+	assert.Equal(t, "abc123-dirty", rev, "Uncommitted changes should be marked")
+
+	rev, tm = vcsInfo(&debug.BuildInfo{})
+	assert.Empty(t, rev, "Without settings, the revision should be unknown")
+	assert.Empty(t, tm, "Without settings, the time should be unknown")
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// burst tracks the entries of a logger with the option BurstSummary by level and message
+type burst struct {
+	mu     sync.Mutex
+	window time.Duration
+	active map[burstKey]*burstState
+}
+
+type burstKey struct {
+	lvl Level
+	msg string
+}
+
+type burstState struct {
+	first time.Time
+	count int
+	timer *time.Timer
+}
+
+func newBurst(window time.Duration) *burst {
+	return &burst{window: window, active: make(map[burstKey]*burstState)}
+}
+
+// suppress reports whether an entry with level lvl and message msg is part of an active burst and shouldn't be
+// written. Otherwise, a burst is started that ends when no entry with the same level and message has been flushed
+// for the window. Then, l logs a summary if entries were suppressed. Entries at fatal and panic level are never
+// suppressed.
+func (b *burst) suppress(l Logger, lvl Level, msg string) bool {
+	if lvl == FatalLevel || lvl == PanicLevel {
+		return false
+	}
+	k := burstKey{lvl, msg}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if s, ok := b.active[k]; ok {
+		s.count++
+		s.timer.Reset(b.window)
+		return true
+	}
+	s := &burstState{first: time.Now()}
+	s.timer = time.AfterFunc(b.window, func() { b.end(l, k, s) })
+	b.active[k] = s
+	return false
+}
+
+// end removes the burst of k and logs its summary
+func (b *burst) end(l Logger, k burstKey, s *burstState) {
+	b.mu.Lock()
+	if b.active[k] != s {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.active, k)
+	n, d := s.count, time.Since(s.first)
+	b.mu.Unlock()
+	if n == 0 {
+		return
+	}
+	l.Level(k.lvl).
+		AddStr("burst_message", k.msg).
+		AddInt("burst_count", n).
+		Flush(fmt.Sprintf("...and %d more in the last %s", n, d.Round(time.Millisecond)))
+}
+
+// bLog wraps a Logger so that repeated entries are summarized. See BurstSummary.
+type bLog struct {
+	wrapLog
+	b *burst
+	// cfg is the config of the wrapped logger, entries below its level aren't tracked. It's nil for loggers without a
+	// config, like multi loggers.
+	cfg *config
+}
+
+var _ Logger = (*bLog)(nil)
+
+// newBurstSummary returns a Logger that summarizes bursts of identical entries of l
+func newBurstSummary(l Logger, b *burst) Logger {
+	bl := &bLog{b: b, cfg: configOf(l)}
+	bl.wrapLog = wrapLog{l, bl}
+	return bl
+}
+
+// with returns a logger that shares the bursts and wraps l
+func (b *bLog) with(l Logger) Logger {
+	return newBurstSummary(l, b.b)
+}
+
+// entry returns a new entry that is suppressed on Flush during a burst
+func (b *bLog) entry(e Entry, lvl Level) Entry {
+	be := &bEntry{l: b.l, b: b.b, cfg: b.cfg}
+	be.wrapEntry = wrapEntry{e: e, lvl: lvl, self: be}
+	return be
+}
+
+// bEntry remembers its level and suppresses Flush during a burst
+type bEntry struct {
+	wrapEntry
+	// l is the wrapped logger that logs the summary of a burst
+	l   Logger
+	b   *burst
+	cfg *config
+}
+
+var _ Entry = (*bEntry)(nil)
+
+// Flush writes the entry as a single log statement. Optionally, a message can be added which will
+// be included in the final log entry
+func (b *bEntry) Flush(msg string) {
+	if !b.cfg.enabled(b.lvl) {
+		// the entry isn't written, it must not start or extend a burst
+		b.e.Flush(msg)
+		return
+	}
+	if b.b.suppress(b.l, b.lvl, msg) {
+		b.e.Discard()
+		return
+	}
+	b.e.Flush(msg)
+}
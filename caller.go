@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"bytes"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// pkgPrefix is the prefix of the function names of this package
+var pkgPrefix = reflect.TypeOf(config{}).PkgPath() + "."
+
+// caller returns "file:line" of the first function outside of this package in the current call stack. skip
+// additional frames are skipped after leaving this package, e.g. for wrappers around a Logger.
+func caller(skip int) string {
+	f, ok := callerFrame(skip)
+	if !ok {
+		return ""
+	}
+	return f.File + ":" + strconv.Itoa(f.Line)
+}
+
+// callerFrame returns the frame of the first function outside of this package like caller
+func callerFrame(skip int) (runtime.Frame, bool) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !ownFrame(f) {
+			if skip <= 0 {
+				return f, true
+			}
+			skip--
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// addCaller adds the fields of AddCallerSkip to e
+func addCaller(e Entry, skip int) Entry {
+	f, ok := callerFrame(skip)
+	if !ok {
+		return e
+	}
+	return e.AddStr("caller", f.File+":"+strconv.Itoa(f.Line)).AddStr("caller_func", f.Function)
+}
+
+// ownFrame reports whether f belongs to this package. Tests of this package count as callers.
+func ownFrame(f runtime.Frame) bool {
+	return strings.HasPrefix(f.Function, pkgPrefix) && !strings.HasSuffix(f.File, "_test.go")
+}
+
+// goroutineID returns the id of the current goroutine, parsed from the first line of its stack trace, which looks
+// like "goroutine 42 [running]:". It returns 0 if the line can't be parsed.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	var id uint64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + uint64(c-'0')
+	}
+	return id
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+)
+
+// cardinality tracks the distinct values of the keys of a logger with the option CardinalityGuard
+type cardinality struct {
+	mu    sync.Mutex
+	limit int
+	// values holds up to limit distinct values of each guarded key. Keys that exceeded the limit are removed, they
+	// aren't tracked anymore.
+	values map[string]map[string]struct{}
+}
+
+func newCardinality(limit int, keys []string) *cardinality {
+	c := &cardinality{limit: limit, values: make(map[string]map[string]struct{})}
+	for _, k := range keys {
+		c.values[k] = make(map[string]struct{})
+	}
+	return c
+}
+
+// observe records val as a value of key. When a guarded key has more distinct values than the limit for the first
+// time, l logs a warning.
+func (c *cardinality) observe(l Logger, key string, val interface{}) {
+	c.mu.Lock()
+	vals, ok := c.values[key]
+	if !ok {
+		c.mu.Unlock()
+		return
+	}
+	vals[fmt.Sprint(val)] = struct{}{}
+	if len(vals) <= c.limit {
+		c.mu.Unlock()
+		return
+	}
+	delete(c.values, key)
+	c.mu.Unlock()
+	l.Warn().
+		AddStr("cardinality_key", key).
+		AddInt("cardinality_limit", c.limit).
+		Flush("field exceeds cardinality limit")
+}
+
+// hLog wraps a Logger so that the distinct values of guarded keys are tracked. See CardinalityGuard.
+type hLog struct {
+	wrapLog
+	g *cardinality
+}
+
+var _ Logger = (*hLog)(nil)
+
+// newCardinalityGuard returns a Logger that tracks the values of the keys guarded by g in the fields of l
+func newCardinalityGuard(l Logger, g *cardinality) Logger {
+	h := &hLog{g: g}
+	h.wrapLog = wrapLog{l, h}
+	return h
+}
+
+// with returns a logger that shares the tracked values and wraps l
+func (h *hLog) with(l Logger) Logger {
+	return newCardinalityGuard(l, h.g)
+}
+
+// entry returns a new entry that tracks the values of guarded keys added to e
+func (h *hLog) entry(e Entry, lvl Level) Entry {
+	he := &hEntry{l: h.l, g: h.g}
+	he.wrapEntry = wrapEntry{e: e, lvl: lvl, self: he}
+	return he
+}
+
+// WithField returns a new Logger that always logs the specified field
+func (h *hLog) WithField(key, value string) Logger {
+	h.g.observe(h.l, key, value)
+	return h.with(h.l.WithField(key, value))
+}
+
+// WithAny returns a new Logger that always logs the specified field. Unlike WithField, the value can be of any type
+func (h *hLog) WithAny(key string, value interface{}) Logger {
+	h.g.observe(h.l, key, value)
+	return h.with(h.l.WithAny(key, value))
+}
+
+// hEntry tracks the values of guarded keys added to it
+type hEntry struct {
+	wrapEntry
+	// l is the wrapped logger that logs the warning when a key exceeds the limit
+	l Logger
+	g *cardinality
+}
+
+var _ Entry = (*hEntry)(nil)
+
+// AddFields adds a range of fields to the log statement
+func (h *hEntry) AddFields(fs map[string]interface{}) Entry {
+	for k, v := range fs {
+		h.g.observe(h.l, k, v)
+	}
+	h.e = h.e.AddFields(fs)
+	return h
+}
+
+// AddInt adds an integer value to the log statement.
+func (h *hEntry) AddInt(key string, val int) Entry {
+	h.g.observe(h.l, key, val)
+	h.e = h.e.AddInt(key, val)
+	return h
+}
+
+// AddStr adds a string value to the log statement.
+func (h *hEntry) AddStr(key string, val string) Entry {
+	h.g.observe(h.l, key, val)
+	h.e = h.e.AddStr(key, val)
+	return h
+}
+
+// AddAny adds any value to the log statement.
+func (h *hEntry) AddAny(key string, val interface{}) Entry {
+	h.g.observe(h.l, key, val)
+	h.e = h.e.AddAny(key, val)
+	return h
+}
+
+// AddKV adds alternating keys and values to the log statement, e.g. AddKV("user", id, "ok", true), like AddAny for
+// each pair. It trades the type safety of the typed methods for brevity. Keys that aren't strings are formatted with
+// fmt.Sprint. If the number of arguments is odd, the last key is added under "kv_warning" as missing its value.
+func (h *hEntry) AddKV(pairs ...interface{}) Entry {
+	return addKV(h, pairs)
+}
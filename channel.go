@@ -0,0 +1,810 @@
+package logger
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/juju/errors"
+)
+
+// CapturedEntry is a log entry as delivered by a logger created with NewChannel
+type CapturedEntry struct {
+	Level   Level
+	Time    time.Time
+	Message string
+	Fields  map[string]interface{}
+	// Dropped is the number of entries that were dropped since the previous delivered entry because the channel was
+	// full
+	Dropped uint64
+}
+
+// NewChannel returns a logger that delivers each entry at level lvl or above on the returned channel, which has a
+// buffer of size buf. Delivery never blocks: if the channel is full, the entry is dropped and counted in the Dropped
+// field of the next delivered entry.
+func NewChannel(lvl Level, buf int, opts ...Option) (Logger, <-chan CapturedEntry) {
+	ch := make(chan CapturedEntry, buf)
+	c := newConfig(opts)
+	lvl = c.threshold(lvl)
+	return c.wrap(&cLog{sink: &cSink{ch: ch}, level: lvl, cfg: c}, lvl), ch
+}
+
+// sink receives the entries of a channel logger and all loggers derived from it
+type sink interface {
+	deliver(e CapturedEntry)
+}
+
+// formatter is implemented by sinks that write entries, so that Entry.Bytes can return the written bytes
+type formatter interface {
+	format(e CapturedEntry) ([]byte, error)
+}
+
+// cSink delivers entries on a channel
+type cSink struct {
+	mu      sync.Mutex
+	ch      chan CapturedEntry
+	dropped uint64
+}
+
+func (s *cSink) deliver(e CapturedEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.Dropped = s.dropped
+	select {
+	case s.ch <- e:
+		s.dropped = 0
+	default:
+		s.dropped++
+		drop()
+	}
+}
+
+type cLog struct {
+	sink   sink
+	level  Level
+	fields map[string]interface{}
+	cfg    *config
+}
+
+var _ Logger = (*cLog)(nil)
+
+// WithField returns a new Logger that always logs the specified field
+func (c *cLog) WithField(key, value string) Logger {
+	return c.WithAny(key, value)
+}
+
+// WithAny returns a new Logger that always logs the specified field. Unlike WithField, the value can be of any type
+func (c *cLog) WithAny(key string, value interface{}) Logger {
+	fields := make(map[string]interface{}, len(c.fields)+1)
+	for k, v := range c.fields {
+		fields[k] = v
+	}
+	fields[key] = value
+	return &cLog{sink: c.sink, level: c.level, fields: fields, cfg: c.cfg}
+}
+
+// WithMessagePrefix returns a new Logger that prefixes the message of each entry with prefix and ": ", e.g. for
+// consumers that grep for a tag instead of parsing fields. Empty messages are prefixed, too.
+func (c *cLog) WithMessagePrefix(prefix string) Logger {
+	return newPrefix(c, prefix)
+}
+
+// WithLevelRemap returns a new Logger that changes the level of its entries according to remap on Flush, e.g.
+// {ErrorLevel: WarnLevel} to keep the errors of a noisy dependency from triggering alerts. The level check of the
+// logger applies to the level before remapping: an entry is only written if its original level is enabled, and
+// then it's written at the new level. An entry remapped to a level below the level of the logger is dropped by the
+// logger, though. Entries at fatal and panic level are never remapped, and no level is remapped to them.
+func (c *cLog) WithLevelRemap(remap map[Level]Level) Logger {
+	return newRemap(c, remap)
+}
+
+// WithLabels returns a new Logger that adds labels to each entry like AddLabels. Labels of nested calls and of
+// AddLabels are merged into one object, later labels take precedence.
+func (c *cLog) WithLabels(labels map[string]string) Logger {
+	return newLabels(c, labels)
+}
+
+// WithEnvironment returns a new Logger that always logs the deployment environment env like "prod" under the key
+// "env", e.g. the result of DetectEnvironment. An empty env is logged as "unknown".
+func (c *cLog) WithEnvironment(env string) Logger {
+	return c.WithField("env", environment(env))
+}
+
+// WithCorrelation returns a new Logger that logs the correlation id under the key "correlation_id", e.g. the result
+// of ExtractCorrelation for an incoming request. InjectCorrelation sets the id in the headers of outgoing requests.
+// An empty id returns the logger unchanged.
+func (c *cLog) WithCorrelation(id string) Logger {
+	return newCorrelation(c, id)
+}
+
+// Level creates a new Entry with the specified Level
+func (c *cLog) Level(lvl Level) Entry {
+	lvl = validLevel(lvl)
+	if !c.cfg.sample(lvl) {
+		return nopEntry{}
+	}
+	return c.cfg.sampled(c.entry(lvl), lvl)
+}
+
+// Debug creates a new Entry with level Debug
+func (c *cLog) Debug() Entry {
+	if !c.cfg.sample(DebugLevel) {
+		return nopEntry{}
+	}
+	return c.cfg.sampled(c.entry(DebugLevel), DebugLevel)
+}
+
+// Info creates a new Entry with level Info
+func (c *cLog) Info() Entry {
+	if !c.cfg.sample(InfoLevel) {
+		return nopEntry{}
+	}
+	return c.cfg.sampled(c.entry(InfoLevel), InfoLevel)
+}
+
+// Warn creates a new Entry with level Warn
+func (c *cLog) Warn() Entry {
+	if !c.cfg.sample(WarnLevel) {
+		return nopEntry{}
+	}
+	return c.cfg.sampled(c.entry(WarnLevel), WarnLevel)
+}
+
+// Error creates a new Entry with level Error
+func (c *cLog) Error() Entry {
+	if !c.cfg.sample(ErrorLevel) {
+		return nopEntry{}
+	}
+	return c.cfg.sampled(c.entry(ErrorLevel), ErrorLevel)
+}
+
+// Fatal creates a new Entry with level Fatal. Executing a log at fatal level exits the application with exit code 1,
+// or the code set with FatalExitCode.
+func (c *cLog) Fatal() Entry {
+	return c.entry(FatalLevel)
+}
+
+// Panic creates a new Entry with level Panic. Executing a log at panic level will call panic().
+func (c *cLog) Panic() Entry {
+	return c.entry(PanicLevel)
+}
+
+// PipeWriter returns a writer that logs every line written to it as an entry at the specified level. The entries
+// have the field "stream" set to stream. The last line is logged on Close even if it doesn't end with a newline.
+func (c *cLog) PipeWriter(lvl Level, stream string) io.WriteCloser {
+	return newLineWriter(c, lvl, stream)
+}
+
+// Operation logs the start of an operation at debug level and returns a function that logs its end together with
+// the duration of the operation. The end is logged at info level, or at error level if a non-nil error is passed.
+// Use it as "defer done(err)". Both entries have the same id under the key "operation_id", see WithIDGenerator.
+func (c *cLog) Operation(name string) func(err error) {
+	return operation(c, name)
+}
+
+// NewTimer returns a Timer that starts now, e.g. "t := l.NewTimer(); parse(); t.Mark("parse")". Add the phases to an
+// entry with Timer.AddTo.
+func (c *cLog) NewTimer() *Timer {
+	return newTimer(c.cfg)
+}
+
+// StartHeartbeat logs msg at info level every interval with the time since StartHeartbeat was called under the key
+// "uptime" and the number of goroutines under the key "goroutines", e.g. to show that a service is alive. It returns
+// a function that stops the heartbeat and waits until an entry that is being written is done, calling it more than
+// once is safe. Each call starts an independent heartbeat. If interval isn't greater than zero, nothing is logged
+// and stop does nothing.
+func (c *cLog) StartHeartbeat(interval time.Duration, msg string) (stop func()) {
+	return heartbeat(c, interval, msg)
+}
+
+// Go runs fn in a new goroutine that recovers from a panic of fn and logs it at error level with the panic value
+// under the key "panic" and the stack under the key "stack", e.g. for background workers, whose panics would crash the
+// process without a log. By default, the panic is swallowed and the goroutine ends. With the option RepanicInGo, the
+// goroutine panics again after logging, which crashes the process.
+func (c *cLog) Go(fn func()) {
+	goSafe(c, fn)
+}
+
+// Sync flushes buffered entries to the destination without closing the logger. For loggers created by New with the
+// zerolog or gelf backend, the writer is synced if it has a method "Sync() error", like *os.File. Sync does nothing
+// for the logrus backend, for channel loggers and for loggers passed to FromZerolog.
+func (c *cLog) Sync() error {
+	if s, ok := c.sink.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// config returns the options of the logger
+func (c *cLog) config() *config {
+	return c.cfg
+}
+
+func (c *cLog) entry(lvl Level) *cEntry {
+	fields := make(map[string]interface{}, len(c.fields))
+	for k, v := range c.fields {
+		fields[k] = v
+	}
+	if c.cfg.retention != "" {
+		fields["retention"] = c.cfg.retention
+	}
+	return &cEntry{log: c, lvl: lvl, time: timeNow(c.cfg), fields: fields}
+}
+
+type cEntry struct {
+	log    *cLog
+	lvl    Level
+	time   time.Time
+	fields map[string]interface{}
+}
+
+var _ Entry = (*cEntry)(nil)
+
+// Flush writes the entry as a single log statement. Optionally, a message can be added which will
+// be included in the final log entry
+func (c *cEntry) Flush(msg string) {
+	cfg := c.log.cfg
+	if cfg.reportCaller {
+		c.fields["caller"] = caller(cfg.callerSkip)
+	}
+	if cfg.goroutineID {
+		c.fields["goroutine"] = goroutineID()
+	}
+	if len(cfg.scrub) > 0 {
+		scrubFields(c.fields, cfg.scrub)
+		msg, _ = scrub(msg, cfg.scrub)
+	}
+	if c.lvl <= c.log.level {
+		if !c.time.IsZero() {
+			c.time = cfg.stamp(c.time)
+		}
+		c.log.sink.deliver(CapturedEntry{Level: c.lvl, Time: c.time, Message: msg, Fields: c.fields})
+	}
+	if c.lvl == PanicLevel {
+		cfg.panicked(func() map[string]interface{} {
+			fs := make(map[string]interface{}, len(c.fields))
+			for k, v := range c.fields {
+				fs[k] = v
+			}
+			return fs
+		})
+		panic(msg)
+	} else if c.lvl == FatalLevel {
+		exitFunc(cfg.exitCode)
+	}
+}
+
+// Bytes returns the entry without a message as it would be written by Flush if the logger writes entries, e.g. a
+// CloudEvent for NewCloudEvents. Otherwise, the fields are encoded as JSON object together with "level", "time" and
+// "message". The level of the logger isn't applied. The entry can still be flushed afterwards.
+func (c *cEntry) Bytes() ([]byte, error) {
+	e := CapturedEntry{Level: c.lvl, Time: c.time, Fields: c.fields}
+	if f, ok := c.log.sink.(formatter); ok {
+		return f.format(e)
+	}
+	fs := make(map[string]interface{}, len(c.fields)+3)
+	for k, v := range c.fields {
+		fs[k] = v
+	}
+	fs["level"] = ltoz(c.lvl).String()
+	fs["message"] = ""
+	if !c.time.IsZero() {
+		fs["time"] = c.time
+	}
+	b, err := json.Marshal(fs)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// Discard abandons the entry without writing it. The entry must not be used afterwards.
+func (c *cEntry) Discard() {}
+
+// At changes the level of the entry. The level is evaluated when the entry is flushed.
+func (c *cEntry) At(lvl Level) Entry {
+	c.lvl = validLevel(lvl)
+	return c
+}
+
+// NoTime removes the time field that is added to each entry automatically, e.g. to add the time of an event instead.
+// The Time of the captured entry is zero.
+func (c *cEntry) NoTime() Entry {
+	c.time = time.Time{}
+	return c
+}
+
+// AddFields adds a range of fields to the log statement
+func (c *cEntry) AddFields(fs map[string]interface{}) Entry {
+	for k, v := range c.log.cfg.nonEmpty(fs) {
+		c.fields[k] = v
+	}
+	return c
+}
+
+// AddErr adds an error to the log statement. The error will have the key "err". An error stack will be included
+// under the key "err_stack". Use the option ErrorKeys to change the keys.
+func (c *cEntry) AddErr(err error) Entry {
+	c.fields[c.log.cfg.errKey] = err.Error()
+	c.fields[c.log.cfg.errStackKey] = errors.ErrorStack(err)
+	return c
+}
+
+// AddError adds an error to the log statement. An error stack will be included under the key "${key}_stack"
+func (c *cEntry) AddError(key string, val error) Entry {
+	c.fields[key] = val.Error()
+	c.fields[key+"_stack"] = errors.ErrorStack(val)
+	return c
+}
+
+// AddBool adds a bool value to the log statement.
+func (c *cEntry) AddBool(key string, val bool) Entry {
+	if c.log.cfg.omitEmpty && !val {
+		return c
+	}
+	c.fields[key] = val
+	return c
+}
+
+// AddInt adds an integer value to the log statement.
+func (c *cEntry) AddInt(key string, val int) Entry {
+	if c.log.cfg.omitEmpty && val == 0 {
+		return c
+	}
+	c.fields[key] = val
+	return c
+}
+
+// AddStr adds a string value to the log statement.
+func (c *cEntry) AddStr(key string, val string) Entry {
+	if c.log.cfg.omitEmpty && val == "" {
+		return c
+	}
+	c.fields[key] = val
+	return c
+}
+
+// AddTime adds a time value to the log statement.
+func (c *cEntry) AddTime(key string, val time.Time) Entry {
+	if c.log.cfg.omitEmpty && val.IsZero() {
+		return c
+	}
+	c.fields[key] = val
+	return c
+}
+
+// AddDur adds a duration value to the log statement.
+func (c *cEntry) AddDur(key string, val time.Duration) Entry {
+	if c.log.cfg.omitEmpty && val == 0 {
+		return c
+	}
+	c.fields[key] = val
+	return c
+}
+
+// AddAny adds any value to the log statement.
+func (c *cEntry) AddAny(key string, val interface{}) Entry {
+	c.fields[key] = val
+	return c
+}
+
+// AddJSONRaw adds pre-serialized JSON to the log statement without encoding it again. If raw is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (c *cEntry) AddJSONRaw(key string, raw []byte) Entry {
+	if !json.Valid(raw) {
+		c.fields[key+"_invalid"] = string(raw)
+		return c
+	}
+	c.fields[key] = json.RawMessage(append([]byte(nil), raw...))
+	return c
+}
+
+// AddCount adds a count to the log statement. The number is stored under key, a human readable form like "3 items"
+// is stored under the key "${key}_human"
+func (c *cEntry) AddCount(key string, n int, singular, plural string) Entry {
+	c.fields[key] = n
+	c.fields[key+"_human"] = pluralize(n, singular, plural)
+	return c
+}
+
+// AddQuery adds a database query to the log statement. The statement, its arguments as JSON array, the number of
+// rows and the duration are stored under the keys "db.sql", "db.args", "db.rows" and "db.duration"
+func (c *cEntry) AddQuery(sql string, args []interface{}, rows int, dur time.Duration) Entry {
+	c.fields["db.sql"] = sql
+	c.fields["db.args"] = args
+	c.fields["db.rows"] = rows
+	c.fields["db.duration"] = dur
+	return c
+}
+
+// AddTraceparent adds the trace id and span id of the W3C traceparent header in headers under the keys
+// "${key}_trace_id" and "${key}_span_id". A missing or malformed header adds nothing.
+func (c *cEntry) AddTraceparent(key string, headers http.Header) Entry {
+	if traceID, spanID, ok := traceparent(headers); ok {
+		c.fields[key+"_trace_id"] = traceID
+		c.fields[key+"_span_id"] = spanID
+	}
+	return c
+}
+
+// AddMetric adds a metric to the log statement. Name, value and tags are stored as a nested object under the key
+// "metric", so that log collectors can route the statement to a metrics sink.
+func (c *cEntry) AddMetric(name string, value float64, tags map[string]string) Entry {
+	c.fields["metric"] = map[string]interface{}{"name": name, "value": value, "tags": tags}
+	return c
+}
+
+// AddHex adds a byte slice to the log statement, encoded as hex string.
+func (c *cEntry) AddHex(key string, val []byte) Entry {
+	c.fields[key] = hex.EncodeToString(val)
+	return c
+}
+
+// AddBase64 adds a byte slice to the log statement, encoded as base64 string with standard encoding.
+func (c *cEntry) AddBase64(key string, val []byte) Entry {
+	c.fields[key] = base64.StdEncoding.EncodeToString(val)
+	return c
+}
+
+// AddElapsed adds the duration since the specified time to the log statement.
+func (c *cEntry) AddElapsed(key string, since time.Time) Entry {
+	return c.AddDur(key, timeNow(c.log.cfg).Sub(since))
+}
+
+// AddDurHuman adds a duration to the log statement as human readable string like "1.2s" or "3m04s". Use AddDur for
+// values that should be queryable.
+func (c *cEntry) AddDurHuman(key string, d time.Duration) Entry {
+	return c.AddStr(key, humanDur(d))
+}
+
+// AddErrN adds an error to the log statement like AddErr, but the error stack under the key "err_stack" is
+// truncated to the first maxFrames frames. The error message is kept intact.
+func (c *cEntry) AddErrN(err error, maxFrames int) Entry {
+	c.fields[c.log.cfg.errKey] = err.Error()
+	c.fields[c.log.cfg.errStackKey] = truncateStack(errors.ErrorStack(err), maxFrames)
+	return c
+}
+
+// AddSensitive adds the hex encoded SHA-256 hash of val under the key "${key}_hash" to the log statement. The raw
+// value is never logged. Use the options SensitiveSalt and SensitiveHashLen to configure the hash.
+func (c *cEntry) AddSensitive(key string, val string) Entry {
+	return c.AddStr(key+"_hash", c.log.cfg.hash(val))
+}
+
+// AddBytesLen adds the length of a byte slice to the log statement instead of its content. A nil slice has length 0.
+func (c *cEntry) AddBytesLen(key string, val []byte) Entry {
+	c.fields[key] = len(val)
+	return c
+}
+
+// AddStrLen adds the number of runes of a string to the log statement instead of the string itself.
+func (c *cEntry) AddStrLen(key string, val string) Entry {
+	c.fields[key] = utf8.RuneCountInString(val)
+	return c
+}
+
+// AddJSONString adds a JSON encoded string to the log statement without encoding it again. If s is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (c *cEntry) AddJSONString(key, s string) Entry {
+	return c.AddJSONRaw(key, []byte(s))
+}
+
+// AddRetry adds the state of a retry loop to the log statement. The attempt, the maximum number of attempts and the
+// backoff before the next attempt are stored under the keys "retry.attempt", "retry.max" and "retry.backoff"
+func (c *cEntry) AddRetry(attempt, max int, backoff time.Duration) Entry {
+	c.fields["retry.attempt"] = attempt
+	c.fields["retry.max"] = max
+	c.fields["retry.backoff"] = backoff
+	return c
+}
+
+// AddDeadline adds the time remaining until the deadline of ctx to the log statement. It is negative if the deadline
+// has passed. If ctx has no deadline, nothing is added.
+func (c *cEntry) AddDeadline(key string, ctx context.Context) Entry {
+	if d, ok := ctx.Deadline(); ok {
+		return c.AddDur(key, d.Sub(timeNow(c.log.cfg)))
+	}
+	return c
+}
+
+// AddRequest adds the method, path, remote address, user agent and host of r to the log statement under the keys
+// "http.method", "http.path", "http.remote_addr", "http.user_agent" and "http.host". Other headers are never logged.
+func (c *cEntry) AddRequest(r *http.Request) Entry {
+	return addRequest(c, r, c.log.cfg.forwardedFor)
+}
+
+// AddResponse adds the status code, the size of the body and the duration of an HTTP response to the log statement
+// under the keys "http.status", "http.bytes" and "http.duration". The class of the status like "5xx" is stored under
+// the key "http.status_class".
+func (c *cEntry) AddResponse(status, bytes int, dur time.Duration) Entry {
+	return addResponse(c, status, bytes, dur)
+}
+
+// AddSlice adds a slice or an array of any element type to the log statement as JSON array. Other values, or slices
+// that can't be encoded as JSON, are added like with AddAny.
+func (c *cEntry) AddSlice(key string, vals interface{}) Entry {
+	if b, ok := sliceJSON(vals); ok {
+		return c.AddJSONRaw(key, b)
+	}
+	return c.AddAny(key, vals)
+}
+
+// AddBoolPtr adds the value of a bool pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (c *cEntry) AddBoolPtr(key string, val *bool) Entry {
+	if val == nil {
+		if c.log.cfg.nilNull {
+			return c.AddAny(key, nil)
+		}
+		return c
+	}
+	return c.AddBool(key, *val)
+}
+
+// AddIntPtr adds the value of an integer pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (c *cEntry) AddIntPtr(key string, val *int) Entry {
+	if val == nil {
+		if c.log.cfg.nilNull {
+			return c.AddAny(key, nil)
+		}
+		return c
+	}
+	return c.AddInt(key, *val)
+}
+
+// AddStrPtr adds the value of a string pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (c *cEntry) AddStrPtr(key string, val *string) Entry {
+	if val == nil {
+		if c.log.cfg.nilNull {
+			return c.AddAny(key, nil)
+		}
+		return c
+	}
+	return c.AddStr(key, *val)
+}
+
+// AddCaller adds the location that called it as "file:line" under the key "caller" and the calling function under
+// the key "caller_func" to the log statement. Unlike the option ReportCaller, it only applies to this entry.
+func (c *cEntry) AddCaller() Entry {
+	return addCaller(c, 0)
+}
+
+// AddCallerSkip adds the caller like AddCaller, but skips n additional frames, e.g. in helpers that wrap an Entry.
+func (c *cEntry) AddCallerSkip(n int) Entry {
+	return addCaller(c, n)
+}
+
+// AddIntThreshold adds a value to the log statement like AddInt and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (c *cEntry) AddIntThreshold(key string, val, threshold int, flagKey string) Entry {
+	c.AddInt(key, val)
+	return thresholdFlag(c, flagKey, val > threshold, c.log.cfg.flagBelow)
+}
+
+// AddDurThreshold adds a value to the log statement like AddDur and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (c *cEntry) AddDurThreshold(key string, val, threshold time.Duration, flagKey string) Entry {
+	c.AddDur(key, val)
+	return thresholdFlag(c, flagKey, val > threshold, c.log.cfg.flagBelow)
+}
+
+// AddMoney adds an amount of money in minor units of an ISO 4217 currency, e.g. cents for "USD", to the log statement.
+// The amount and the currency are stored under the keys "${key}.amount_minor" and "${key}.currency", the formatted
+// amount like "$12.99" under the key "${key}.display". Unknown currencies, including an empty code, are formatted
+// with two decimal places and flagged with true under the key "${key}.currency_unknown".
+func (c *cEntry) AddMoney(key string, minorUnits int64, currency string) Entry {
+	currency = strings.ToUpper(currency)
+	c.fields[key+".amount_minor"] = minorUnits
+	c.fields[key+".currency"] = currency
+	display, known := formatMoney(minorUnits, currency)
+	c.fields[key+".display"] = display
+	if !known {
+		c.fields[key+".currency_unknown"] = true
+	}
+	return c
+}
+
+// AddErrChain adds the message of an error under key, the number of errors it wraps under the key "${key}_depth" and
+// the type names of the errors in its chain, starting with err, under the key "${key}_types" to the log statement.
+// Both the standard library's wrapping and juju/errors are followed. A nil error adds nothing.
+func (c *cEntry) AddErrChain(key string, err error) Entry {
+	if err == nil {
+		return c
+	}
+	depth, types := errChain(err)
+	c.fields[key] = err.Error()
+	c.fields[key+"_depth"] = depth
+	c.fields[key+"_types"] = types
+	return c
+}
+
+// AddInterval adds a time window to the log statement. Start and end are stored in UTC under the keys "${key}.start"
+// and "${key}.end", the duration between them under the key "${key}.duration". If end is before start, the duration
+// is negative.
+func (c *cEntry) AddInterval(key string, start, end time.Time) Entry {
+	c.fields[key+".start"] = start.UTC()
+	c.fields[key+".end"] = end.UTC()
+	c.fields[key+".duration"] = end.Sub(start)
+	return c
+}
+
+// AddValidationErrors adds the results of a failed validation to the log statement as nested object that maps each
+// invalid field to its error message, e.g. {"email": "must not be empty"}. A nil or empty map adds nothing.
+func (c *cEntry) AddValidationErrors(key string, errs map[string]string) Entry {
+	if len(errs) == 0 {
+		return c
+	}
+	c.fields[key] = errs
+	return c
+}
+
+// AddGeo adds coordinates to the log statement as nested object with the keys "lat" and "lon", which Elasticsearch
+// maps as geo_point. If the latitude isn't within [-90, 90] or the longitude isn't within [-180, 180], the
+// coordinates are still added and "${key}_invalid" is set to true.
+func (c *cEntry) AddGeo(key string, lat, lon float64) Entry {
+	p := geoPoint{lat, lon}
+	c.fields[key] = map[string]interface{}{"lat": lat, "lon": lon}
+	if !p.valid() {
+		c.fields[key+"_invalid"] = true
+	}
+	return c
+}
+
+// Retention sets the retention class of the entry under the key "retention", e.g. "short", "long" or "audit", so
+// that the log pipeline can route it to a storage tier. It overrides the class set with DefaultRetention.
+func (c *cEntry) Retention(class string) Entry {
+	c.fields["retention"] = class
+	return c
+}
+
+// AddDiff adds the old and the new value of a change to the log statement as nested object with the keys "old" and
+// "new". If both values are equal according to reflect.DeepEqual, nothing is added.
+func (c *cEntry) AddDiff(key string, oldVal, newVal interface{}) Entry {
+	if reflect.DeepEqual(oldVal, newVal) {
+		return c
+	}
+	c.fields[key] = map[string]interface{}{"old": oldVal, "new": newVal}
+	return c
+}
+
+// AddRuntime adds diagnostics of the Go runtime to the log statement: the Go version, the operating system and the
+// architecture under the keys "go.version", "go.os" and "go.arch", the number of goroutines under the key
+// "go.goroutines" and the allocated heap in MiB under the key "go.heap_mb". Reading the heap stops the world, so use
+// it for rare entries like crash reports, not in hot paths.
+func (c *cEntry) AddRuntime() Entry {
+	c.fields["go.version"] = runtime.Version()
+	c.fields["go.os"] = runtime.GOOS
+	c.fields["go.arch"] = runtime.GOARCH
+	c.fields["go.goroutines"] = runtime.NumGoroutine()
+	c.fields["go.heap_mb"] = heapMB()
+	return c
+}
+
+// AddForm adds the values of the listed form fields to the log statement under the keys "form.${name}". Multiple
+// values of a field are joined with commas. Fields that aren't listed are never added, e.g. passwords, and listed
+// fields missing from values are skipped.
+func (c *cEntry) AddForm(values url.Values, keys ...string) Entry {
+	for _, k := range keys {
+		if vs, ok := values[k]; ok {
+			c.AddStr("form."+k, strings.Join(vs, ","))
+		}
+	}
+	return c
+}
+
+// AddBackoff computes the wait before the next attempt of a retry loop and adds it to the log statement together with
+// the attempt under the keys "backoff.wait" and "backoff.attempt". The wait doubles with each attempt, starting at
+// base for attempt 1, and is capped at max. Half of it is random jitter, so that clients don't retry in lockstep.
+// The wait is returned so that the caller can sleep on it.
+func (c *cEntry) AddBackoff(attempt int, base, max time.Duration) (Entry, time.Duration) {
+	d := backoff(attempt, base, max)
+	c.fields["backoff.attempt"] = attempt
+	c.fields["backoff.wait"] = d
+	return c, d
+}
+
+// AddLabels adds a set of labels like environment, team or service to the log statement as nested object under the
+// key "labels". Label names that don't match the Prometheus label name syntax [a-zA-Z_][a-zA-Z0-9_]* are still added
+// and listed under the key "labels_invalid". Labels of a logger created by WithLabels are merged with labels, with
+// labels taking precedence. A nil or empty map adds nothing.
+func (c *cEntry) AddLabels(labels map[string]string) Entry {
+	return addLabels(c, labels)
+}
+
+// AddPercent adds a fraction like a success ratio to the log statement under key and the same value as percentage
+// string with one decimal under "${key}_pct", e.g. 0.873 as "87.3%", so that dashboards don't mix fractions and
+// percentages. A fraction outside [0, 1] is clamped and "${key}_invalid" is set to true, NaN is clamped to 0.
+func (c *cEntry) AddPercent(key string, fraction float64) Entry {
+	f, pct, ok := percent(fraction)
+	c.fields[key] = f
+	c.fields[key+"_pct"] = pct
+	if !ok {
+		c.fields[key+"_invalid"] = true
+	}
+	return c
+}
+
+// AddNullString adds the value of a sql.NullString to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (c *cEntry) AddNullString(key string, val sql.NullString) Entry {
+	if !val.Valid {
+		if c.log.cfg.nilNull {
+			return c.AddAny(key, nil)
+		}
+		return c
+	}
+	return c.AddStr(key, val.String)
+}
+
+// AddNullInt64 adds the value of a sql.NullInt64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (c *cEntry) AddNullInt64(key string, val sql.NullInt64) Entry {
+	if !val.Valid {
+		if c.log.cfg.nilNull {
+			return c.AddAny(key, nil)
+		}
+		return c
+	}
+	return c.AddAny(key, val.Int64)
+}
+
+// AddNullFloat64 adds the value of a sql.NullFloat64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (c *cEntry) AddNullFloat64(key string, val sql.NullFloat64) Entry {
+	if !val.Valid {
+		if c.log.cfg.nilNull {
+			return c.AddAny(key, nil)
+		}
+		return c
+	}
+	return c.AddAny(key, val.Float64)
+}
+
+// AddNullBool adds the value of a sql.NullBool to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (c *cEntry) AddNullBool(key string, val sql.NullBool) Entry {
+	if !val.Valid {
+		if c.log.cfg.nilNull {
+			return c.AddAny(key, nil)
+		}
+		return c
+	}
+	return c.AddBool(key, val.Bool)
+}
+
+// AddKV adds alternating keys and values to the log statement, e.g. AddKV("user", id, "ok", true), like AddAny for
+// each pair. It trades the type safety of the typed methods for brevity. Keys that aren't strings are formatted with
+// fmt.Sprint. If the number of arguments is odd, the last key is added under "kv_warning" as missing its value.
+func (c *cEntry) AddKV(pairs ...interface{}) Entry {
+	return addKV(c, pairs)
+}
+
+// AddBigRat adds an arbitrary-precision rational number to the log statement as string, so that exact values like
+// amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default. nil
+// is added as "<nil>".
+func (c *cEntry) AddBigRat(key string, val *big.Rat) Entry {
+	return c.AddStr(key, formatRat(val, c.log.cfg.bigPrec))
+}
+
+// AddBigFloat adds an arbitrary-precision floating-point number to the log statement as string, so that exact values
+// like amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default.
+// nil is added as "<nil>".
+func (c *cEntry) AddBigFloat(key string, val *big.Float) Entry {
+	return c.AddStr(key, formatBigFloat(val, c.log.cfg.bigPrec))
+}
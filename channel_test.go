@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewChannel(t *testing.T) {
+	l, ch := NewChannel(InfoLevel, 10)
+	l = l.WithAny("tenant_id", 42)
+	l.Debug().AddStr("debugkey", "val").Flush("debug")
+	l.Info().AddStr("str", "val").AddInt("int", 3).AddDur("dur", time.Second).AddErr(errors.New("asd")).Flush("message")
+	e := <-ch
+	assert.Equal(t, Level(InfoLevel), e.Level, "Entry should have the level it was created with")
+	assert.Equal(t, "message", e.Message, "Entry should have the message")
+	assert.False(t, e.Time.IsZero(), "Entry should have a time")
+	assert.Equal(t, 42, e.Fields["tenant_id"], "Entry should contain persistent fields")
+	assert.Equal(t, "val", e.Fields["str"], "Entry should contain typed fields")
+	assert.Equal(t, 3, e.Fields["int"], "Entry should contain typed fields")
+	assert.Equal(t, time.Second, e.Fields["dur"], "Entry should contain typed fields")
+	assert.Equal(t, "asd", e.Fields["err"], "Entry should contain error")
+	assert.Contains(t, e.Fields, "err_stack", "Entry should contain error stack")
+	assert.Len(t, ch, 0, "Entries below the level should not be delivered")
+}
+
+func TestNewChannel_Full(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 1)
+	before := DroppedCount()
+	l.Info().Flush("first")
+	l.Info().Flush("second")
+	l.Info().Flush("third")
+	assert.Equal(t, before+2, DroppedCount(), "Dropped entries should be counted globally")
+	e := <-ch
+	assert.Equal(t, "first", e.Message, "First entry should be delivered")
+	assert.Equal(t, uint64(0), e.Dropped, "No entry was dropped before the first one")
+	l.Info().Flush("fourth")
+	e = <-ch
+	assert.Equal(t, "fourth", e.Message, "Entries should be dropped while the channel is full")
+	assert.Equal(t, uint64(2), e.Dropped, "Dropped entries should be counted")
+}
+
+func TestCEntry_Fatal(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 1)
+	code := exitCode(func() { l.Fatal().Flush("fatal") })
+	assert.Equal(t, 1, code, "Call to Fatal level should exit with code 1")
+	assert.Equal(t, Level(FatalLevel), (<-ch).Level, "Fatal entry should be delivered")
+	assert.Panics(t, func() { l.Panic().Flush("panic") }, "Call to Panic level should panic")
+}
+
+func TestCEntry_NoTime(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 1)
+	l.Info().NoTime().Flush("")
+	assert.True(t, (<-ch).Time.IsZero(), "Entry should not have a time")
+}
+
+func TestCEntry_AddErrN(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 1)
+	err := errors.Annotate(errors.Annotate(errors.New("asd"), "other err"), "third err")
+	l.Info().AddErrN(err, 1).Flush("")
+	e := <-ch
+	st := e.Fields["err_stack"].(string)
+	assert.Len(t, strings.Split(st, "\n"), 2, "Error stack should contain one frame and the truncation note")
+	assert.True(t, strings.HasSuffix(st, "\n...2 more"), "Error stack should be truncated")
+	assert.Equal(t, "third err: other err: asd", e.Fields["err"], "Error message should be kept intact")
+}
+
+func TestCEntry_Bytes(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 1)
+	b, err := l.Warn().AddInt("key", 1).Bytes()
+	assert.NoError(t, err)
+	var m map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &m), "Bytes should be a JSON object")
+	assert.Equal(t, float64(1), m["key"], "Bytes should contain the fields")
+	assert.Equal(t, "warn", m["level"], "Bytes should contain the level")
+	assert.Len(t, ch, 0, "Bytes should not deliver the entry")
+
+	var sb strings.Builder
+	b, err = NewCloudEvents(&sb, "/test", InfoLevel).Info().AddInt("key", 1).Bytes()
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"specversion":"1.0"`, "Bytes should be formatted as CloudEvent")
+	assert.Empty(t, sb.String(), "Bytes should not write the entry")
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	mrand "math/rand"
+	"sync"
+	"time"
+)
+
+// CloudEventsTypeKey is the key of the field that sets the type of a CloudEvent written by a logger created with
+// NewCloudEvents, e.g. AddStr(CloudEventsTypeKey, "com.example.order.created"). The field is not part of the data.
+const CloudEventsTypeKey = "ce_type"
+
+// NewCloudEvents creates a logger that writes each entry at level lvl or above as a CloudEvent in the structured JSON
+// format of the CloudEvents specification 1.0, one event per line. The event has the attributes "specversion", "id",
+// "source", "type", "time" and "datacontenttype", the fields of the entry are the "data" together with the message
+// under the key "message" and the level under the key "level". The id is a random UUID, or generated by the function
+// passed to WithIDGenerator. The type is the field
+// CloudEventsTypeKey of the entry, or "log.${level}" like "log.info" if it isn't set.
+func NewCloudEvents(w io.Writer, source string, lvl Level, opts ...Option) Logger {
+	c := newConfig(opts)
+	lvl = c.threshold(lvl)
+	return c.wrap(&cLog{sink: &ceSink{w: w, source: source, cfg: c}, level: lvl, cfg: c}, lvl)
+}
+
+// cloudEvent is the envelope of a CloudEvent in the structured JSON format
+type cloudEvent struct {
+	SpecVersion     string                 `json:"specversion"`
+	ID              string                 `json:"id"`
+	Source          string                 `json:"source"`
+	Type            string                 `json:"type"`
+	Time            string                 `json:"time,omitempty"`
+	DataContentType string                 `json:"datacontenttype"`
+	Data            map[string]interface{} `json:"data"`
+}
+
+// ceSink writes entries as CloudEvents
+type ceSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	source string
+	cfg    *config
+}
+
+func (s *ceSink) deliver(e CapturedEntry) {
+	b, _ := s.format(e)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.w.Write(b)
+}
+
+// format encodes e as CloudEvent followed by a newline
+func (s *ceSink) format(e CapturedEntry) ([]byte, error) {
+	ev := cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              newID(s.cfg),
+		Source:          s.source,
+		Type:            "log." + ltoz(e.Level).String(),
+		DataContentType: "application/json",
+		Data:            make(map[string]interface{}, len(e.Fields)+2),
+	}
+	if !e.Time.IsZero() {
+		ev.Time = e.Time.UTC().Format(time.RFC3339Nano)
+	}
+	for k, v := range e.Fields {
+		if k == CloudEventsTypeKey {
+			if t, ok := v.(string); ok && t != "" {
+				ev.Type = t
+			}
+			continue
+		}
+		ev.Data[k] = v
+	}
+	ev.Data["level"] = ltoz(e.Level).String()
+	ev.Data["message"] = e.Message
+	b, err := json.Marshal(ev)
+	if err != nil {
+		// keep the event, but replace the data that can't be encoded
+		ev.Data = map[string]interface{}{"level": ev.Data["level"], "message": e.Message, "data_error": err.Error()}
+		b, _ = json.Marshal(ev)
+	}
+	return append(b, '\n'), nil
+}
+
+// Sync syncs the writer if it supports it
+func (s *ceSink) Sync() error {
+	return syncWriter(s.w)
+}
+
+// uuid returns a random UUID (version 4) in its canonical string form
+func uuid() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand doesn't fail on supported platforms, fall back to a pseudo-random id instead of failing the entry
+		mrand.Read(b[:])
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCloudEvents(t *testing.T) {
+	var sb strings.Builder
+	l := NewCloudEvents(&sb, "/orders", InfoLevel).WithField("service", "api")
+	l.Debug().Flush("hidden")
+	l.Info().AddStr(CloudEventsTypeKey, "com.example.order.created").AddInt("order", 42).Flush("created")
+	l.Warn().Flush("default type")
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	if !assert.Len(t, lines, 2, "Entries below the level should be skipped") {
+		return
+	}
+	var ev map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &ev), "Event should be valid JSON")
+	assert.Equal(t, "1.0", ev["specversion"], "Event should contain the spec version")
+	assert.Equal(t, "/orders", ev["source"], "Event should contain the source")
+	assert.Equal(t, "com.example.order.created", ev["type"], "Event should contain the type of the entry")
+	assert.Equal(t, "application/json", ev["datacontenttype"], "Event should contain the content type")
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), ev["id"],
+		"Id should be a random UUID")
+	_, err := time.Parse(time.RFC3339Nano, ev["time"].(string))
+	assert.NoError(t, err, "Time should be RFC 3339")
+	data := ev["data"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"service": "api", "order": float64(42), "level": "info", "message": "created"},
+		data, "Data should contain the fields, the level and the message")
+
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &ev), "Event should be valid JSON")
+	assert.Equal(t, "log.warn", ev["type"], "Type should default to the level")
+}
+
+func TestNewCloudEvents_ID(t *testing.T) {
+	var sb strings.Builder
+	l := NewCloudEvents(&sb, "/test", InfoLevel)
+	l.Info().NoTime().Flush("")
+	l.Info().Flush("")
+	var first, second map[string]interface{}
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.NotEqual(t, first["id"], second["id"], "Each event should have its own id")
+	assert.NotContains(t, first, "time", "NoTime should omit the time")
+}
+
+func TestNewCloudEvents_InvalidData(t *testing.T) {
+	var sb strings.Builder
+	NewCloudEvents(&sb, "/test", InfoLevel).Info().AddAny("func", func() {}).Flush("message")
+	var ev map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(sb.String()), &ev), "Event should be valid JSON")
+	data := ev["data"].(map[string]interface{})
+	assert.Equal(t, "message", data["message"], "Event should keep the message")
+	assert.Contains(t, data, "data_error", "Event should contain the encoding error")
+}
+
+func TestNewCloudEvents_IDGenerator(t *testing.T) {
+	var sb strings.Builder
+	NewCloudEvents(&sb, "/test", InfoLevel, WithIDGenerator(func() string { return "event-1" })).Info().Flush("")
+	assert.Contains(t, sb.String(), `"id":"event-1"`, "Id should be generated by the generator")
+}
@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// collapseTimeout is the time after which the summary of collapsed entries is written even if no different entry
+// has been flushed. It's a variable for tests.
+var collapseTimeout = 30 * time.Second
+
+// collapse tracks the last entry flushed by a logger with the option CollapseRepeats and the number of identical
+// entries that have been flushed after it
+type collapse struct {
+	mu  sync.Mutex
+	lvl Level
+	msg string
+	sig []interface{}
+	// l is the wrapped logger of the last entry that logs the summary. It's nil if there is no entry to compare with.
+	l     Logger
+	count int
+	timer *time.Timer
+	// gen counts the timers so that a timer that fires after it has been stopped is ignored
+	gen uint64
+}
+
+// flush writes r with the message msg unless it's identical to the last entry in level, message and signature.
+// Then, it's only counted. Otherwise, the summary of the last entry is written before r and r becomes the last entry.
+// Entries at fatal and panic level are never collapsed.
+func (c *collapse) flush(r *rEntry, msg string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.l != nil && c.lvl == r.lvl && c.msg == msg && reflect.DeepEqual(c.sig, r.sig) {
+		c.count++
+		if c.timer == nil {
+			c.gen++
+			gen := c.gen
+			c.timer = time.AfterFunc(collapseTimeout, func() { c.timeout(gen) })
+		}
+		r.e.Discard()
+		return
+	}
+	c.summarize()
+	c.l, c.lvl, c.msg, c.sig = r.l, r.lvl, msg, r.sig
+	if r.lvl == FatalLevel || r.lvl == PanicLevel {
+		c.l = nil
+	}
+	r.e.Flush(msg)
+}
+
+// timeout writes the summary when the timer of generation gen fires. The last entry is kept, later identical entries
+// are still collapsed.
+func (c *collapse) timeout(gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != gen || c.timer == nil {
+		return
+	}
+	c.summarize()
+}
+
+// summarize writes the summary of the collapsed entries, if any. c.mu must be held.
+func (c *collapse) summarize() {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if c.count == 0 {
+		return
+	}
+	n := c.count
+	c.count = 0
+	c.l.Level(c.lvl).AddInt("repeat_count", n).Flush(fmt.Sprintf("last message repeated %d times", n))
+}
+
+// rLog wraps a Logger so that consecutive identical entries are collapsed. See CollapseRepeats.
+type rLog struct {
+	wrapLog
+	c *collapse
+	// sig records the fields of the logger, they are part of the signature of its entries
+	sig []interface{}
+	// cfg is the config of the wrapped logger, entries below its level aren't collapsed. It's nil for loggers without
+	// a config, like multi loggers.
+	cfg *config
+}
+
+var _ Logger = (*rLog)(nil)
+
+// newCollapse returns a Logger that collapses consecutive identical entries of l
+func newCollapse(l Logger, c *collapse, sig []interface{}) Logger {
+	r := &rLog{c: c, sig: sig, cfg: configOf(l)}
+	r.wrapLog = wrapLog{l, r}
+	return r
+}
+
+// with returns a logger with the same state that wraps l
+func (r *rLog) with(l Logger) Logger {
+	return newCollapse(l, r.c, r.sig)
+}
+
+// extend returns a derived logger with the same state whose signature is extended by args
+func (r *rLog) extend(l Logger, args ...interface{}) Logger {
+	sig := make([]interface{}, 0, len(r.sig)+len(args))
+	return newCollapse(l, r.c, append(append(sig, r.sig...), args...))
+}
+
+// entry returns a new entry with the level lvl whose signature starts with the signature of the logger
+func (r *rLog) entry(e Entry, lvl Level) Entry {
+	re := &rEntry{l: r.l, c: r.c, cfg: r.cfg, sig: append([]interface{}(nil), r.sig...)}
+	re.wrapEntry = wrapEntry{e: e, lvl: lvl, self: re, observe: re.rec}
+	return re
+}
+
+// WithField returns a new Logger that always logs the specified field
+func (r *rLog) WithField(key, value string) Logger {
+	return r.extend(r.l.WithField(key, value), "WithField", key, value)
+}
+
+// WithAny returns a new Logger that always logs the specified field. Unlike WithField, the value can be of any type
+func (r *rLog) WithAny(key string, value interface{}) Logger {
+	return r.extend(r.l.WithAny(key, value), "WithAny", key, value)
+}
+
+// rEntry records the calls made on it so that it can be compared with the last entry on Flush
+type rEntry struct {
+	wrapEntry
+	// l is the wrapped logger that logs the summary of collapsed entries
+	l   Logger
+	c   *collapse
+	cfg *config
+	sig []interface{}
+}
+
+var _ Entry = (*rEntry)(nil)
+
+// rec records a call with its arguments in the signature of the entry
+func (r *rEntry) rec(method string, args ...interface{}) {
+	r.sig = append(r.sig, append([]interface{}{method}, args...))
+}
+
+// Flush writes the entry as a single log statement. Optionally, a message can be added which will
+// be included in the final log entry
+func (r *rEntry) Flush(msg string) {
+	if !r.cfg.enabled(r.lvl) {
+		// the entry isn't written, it must not replace the last entry
+		r.e.Flush(msg)
+		return
+	}
+	r.c.flush(r, msg)
+}
+
+// Bytes returns the entry in the format of the logger without writing it. Repeats aren't collapsed.
+func (r *rEntry) Bytes() ([]byte, error) {
+	return r.e.Bytes()
+}
+
+// At changes the level of the entry. The level is evaluated when the entry is flushed.
+func (r *rEntry) At(lvl Level) Entry {
+	r.lvl = validLevel(lvl)
+	r.e = r.e.At(lvl)
+	return r
+}
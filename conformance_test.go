@@ -0,0 +1,281 @@
+package logger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// entryCalls calls each method of Entry with a sample value. The keys are the names of the methods.
+var entryCalls = map[string]func(e Entry) Entry{
+	"At":        func(e Entry) Entry { return e.At(WarnLevel) },
+	"NoTime":    func(e Entry) Entry { return e.NoTime() },
+	"AddFields": func(e Entry) Entry { return e.AddFields(map[string]interface{}{"fields": 1}) },
+	"AddErr":    func(e Entry) Entry { return e.AddErr(errors.New("err")) },
+	"AddError":  func(e Entry) Entry { return e.AddError("error", errors.New("err")) },
+	"AddBool":   func(e Entry) Entry { return e.AddBool("bool", true) },
+	"AddInt":    func(e Entry) Entry { return e.AddInt("int", 1) },
+	"AddStr":    func(e Entry) Entry { return e.AddStr("str", "val") },
+	"AddTime":   func(e Entry) Entry { return e.AddTime("time_key", time.Now()) },
+	"AddDur":    func(e Entry) Entry { return e.AddDur("dur", time.Second) },
+	"AddAny":    func(e Entry) Entry { return e.AddAny("any", []int{1}) },
+	"AddJSONRaw": func(e Entry) Entry {
+		return e.AddJSONRaw("json", []byte(`{"a":1}`))
+	},
+	"AddCount": func(e Entry) Entry { return e.AddCount("count", 2, "item", "items") },
+	"AddQuery": func(e Entry) Entry {
+		return e.AddQuery("SELECT 1", []interface{}{1}, 1, time.Millisecond)
+	},
+	"AddTraceparent": func(e Entry) Entry {
+		h := http.Header{"Traceparent": {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}}
+		return e.AddTraceparent("trace", h)
+	},
+	"AddMetric":       func(e Entry) Entry { return e.AddMetric("metric", 1.5, map[string]string{"a": "b"}) },
+	"AddHex":          func(e Entry) Entry { return e.AddHex("hex", []byte{1}) },
+	"AddBase64":       func(e Entry) Entry { return e.AddBase64("base64", []byte{1}) },
+	"AddElapsed":      func(e Entry) Entry { return e.AddElapsed("elapsed", time.Now()) },
+	"AddDurHuman":     func(e Entry) Entry { return e.AddDurHuman("dur_human", time.Second) },
+	"AddErrN":         func(e Entry) Entry { return e.AddErrN(errors.New("err"), 1) },
+	"AddSensitive":    func(e Entry) Entry { return e.AddSensitive("sensitive", "val") },
+	"AddBytesLen":     func(e Entry) Entry { return e.AddBytesLen("bytes_len", []byte{1}) },
+	"AddStrLen":       func(e Entry) Entry { return e.AddStrLen("str_len", "val") },
+	"AddJSONString":   func(e Entry) Entry { return e.AddJSONString("json_string", `{"a":1}`) },
+	"AddRetry":        func(e Entry) Entry { return e.AddRetry(1, 3, time.Second) },
+	"AddDeadline":     func(e Entry) Entry { return e.AddDeadline("deadline", context.Background()) },
+	"AddRequest":      func(e Entry) Entry { return e.AddRequest(httptest.NewRequest("GET", "/", nil)) },
+	"AddResponse":     func(e Entry) Entry { return e.AddResponse(200, 10, time.Millisecond) },
+	"AddSlice":        func(e Entry) Entry { return e.AddSlice("slice", []int{1}) },
+	"AddBoolPtr":      func(e Entry) Entry { return e.AddBoolPtr("bool_ptr", nil) },
+	"AddIntPtr":       func(e Entry) Entry { return e.AddIntPtr("int_ptr", nil) },
+	"AddStrPtr":       func(e Entry) Entry { return e.AddStrPtr("str_ptr", nil) },
+	"AddNullString":   func(e Entry) Entry { return e.AddNullString("null_str", sql.NullString{}) },
+	"AddNullInt64":    func(e Entry) Entry { return e.AddNullInt64("null_int", sql.NullInt64{}) },
+	"AddNullFloat64":  func(e Entry) Entry { return e.AddNullFloat64("null_float", sql.NullFloat64{}) },
+	"AddNullBool":     func(e Entry) Entry { return e.AddNullBool("null_bool", sql.NullBool{}) },
+	"AddCaller":       func(e Entry) Entry { return e.AddCaller() },
+	"AddCallerSkip":   func(e Entry) Entry { return e.AddCallerSkip(1) },
+	"AddIntThreshold": func(e Entry) Entry { return e.AddIntThreshold("int_threshold", 2, 1, "int_flag") },
+	"AddDurThreshold": func(e Entry) Entry { return e.AddDurThreshold("dur_threshold", 2, 1, "dur_flag") },
+	"AddMoney":        func(e Entry) Entry { return e.AddMoney("money", 1299, "USD") },
+	"AddErrChain":     func(e Entry) Entry { return e.AddErrChain("err_chain", errors.New("err")) },
+	"AddInterval":     func(e Entry) Entry { return e.AddInterval("interval", time.Now(), time.Now()) },
+	"AddGeo":          func(e Entry) Entry { return e.AddGeo("geo", 52.52, 13.405) },
+	"AddPercent":      func(e Entry) Entry { return e.AddPercent("ratio", 0.5) },
+	"Retention":       func(e Entry) Entry { return e.Retention("audit") },
+	"AddDiff":         func(e Entry) Entry { return e.AddDiff("diff", 1, 2) },
+	"AddRuntime":      func(e Entry) Entry { return e.AddRuntime() },
+	"AddBackoff": func(e Entry) Entry {
+		e, _ = e.AddBackoff(2, time.Second, time.Minute)
+		return e
+	},
+	"AddBigRat":   func(e Entry) Entry { return e.AddBigRat("rat", big.NewRat(1, 3)) },
+	"AddBigFloat": func(e Entry) Entry { return e.AddBigFloat("float", big.NewFloat(1.5)) },
+	"AddKV":       func(e Entry) Entry { return e.AddKV("user", 1, "ok", true) },
+	"AddLabels":   func(e Entry) Entry { return e.AddLabels(map[string]string{"team": "core"}) },
+	"AddForm":     func(e Entry) Entry { return e.AddForm(url.Values{"q": {"a", "b"}}, "q") },
+	"AddValidationErrors": func(e Entry) Entry {
+		return e.AddValidationErrors("validation", map[string]string{"email": "must not be empty"})
+	},
+}
+
+// RunLoggerConformance exercises every method of Logger and Entry on the loggers returned by factory. Each backend
+// has to pass it. factory is called once per subtest and must return a logger at debug level.
+func RunLoggerConformance(t *testing.T, factory func() Logger) {
+	t.Run("EntryMethods", func(t *testing.T) {
+		typ := reflect.TypeOf((*Entry)(nil)).Elem()
+		for i := 0; i < typ.NumMethod(); i++ {
+			name := typ.Method(i).Name
+			if name == "Flush" || name == "Discard" || name == "Bytes" {
+				continue
+			}
+			call, ok := entryCalls[name]
+			if !assert.True(t, ok, "Conformance suite should cover Entry.%s", name) {
+				continue
+			}
+			e := call(factory().Info())
+			assert.NotNil(t, e, "Entry.%s should return an entry", name)
+			assert.NotPanics(t, func() { e.Flush(name) }, "Entry.%s should not break Flush", name)
+		}
+	})
+
+	t.Run("Discard", func(t *testing.T) {
+		for name, call := range entryCalls {
+			e := call(factory().Info())
+			assert.NotPanics(t, e.Discard, "Entry.%s should not break Discard", name)
+		}
+	})
+
+	t.Run("Bytes", func(t *testing.T) {
+		e := factory().Info().AddStr("str", "val")
+		b, err := e.Bytes()
+		assert.NoError(t, err, "Bytes should not fail")
+		assert.Contains(t, string(b), "val", "Bytes should contain the fields")
+		assert.NotPanics(t, func() { e.Flush("") }, "Entry should be flushed after Bytes")
+	})
+
+	t.Run("Chain", func(t *testing.T) {
+		e := factory().Debug()
+		for _, call := range entryCalls {
+			e = call(e)
+		}
+		assert.NotPanics(t, func() { e.Flush("chain") }, "All methods should be chainable")
+	})
+
+	t.Run("Levels", func(t *testing.T) {
+		l := factory()
+		for _, e := range []Entry{l.Debug(), l.Info(), l.Warn(), l.Error(), l.Level(InfoLevel), l.Level(IncorrectLevel)} {
+			assert.NotNil(t, e, "Logger should create entries")
+			e.Flush("")
+		}
+		assert.Equal(t, 1, exitCode(func() { l.Fatal().Flush("") }), "Fatal should exit with code 1")
+		assert.Panics(t, func() { l.Panic().Flush("") }, "Panic should panic")
+	})
+
+	t.Run("Fields", func(t *testing.T) {
+		l := factory()
+		assert.NotNil(t, l.WithField("key", "val"), "WithField should return a logger")
+		assert.NotNil(t, l.WithAny("key", 1), "WithAny should return a logger")
+		l.WithField("key", "val").WithAny("any", 1).Info().Flush("")
+	})
+
+	t.Run("PipeWriter", func(t *testing.T) {
+		w := factory().PipeWriter(InfoLevel, "stdout")
+		_, err := w.Write([]byte("line\n"))
+		assert.NoError(t, err, "Write should not fail")
+		assert.NoError(t, w.Close(), "Close should not fail")
+	})
+
+	t.Run("Operation", func(t *testing.T) {
+		l := factory()
+		l.Operation("op")(nil)
+		l.Operation("op")(errors.New("err"))
+	})
+
+	t.Run("Sync", func(t *testing.T) {
+		assert.NoError(t, factory().Sync(), "Sync should not fail")
+	})
+
+	t.Run("Go", func(t *testing.T) {
+		done := make(chan struct{})
+		factory().Go(func() { close(done) })
+		<-done
+	})
+}
+
+func TestConformance(t *testing.T) {
+	for _, impl := range backends() {
+		impl := impl
+		t.Run(implName(impl), func(t *testing.T) {
+			RunLoggerConformance(t, func() Logger {
+				var sb strings.Builder
+				return New(&sb, DebugLevel, impl)
+			})
+		})
+	}
+	t.Run("Multi", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			l, _ := multiLogger(DebugLevel)
+			return l
+		})
+	})
+	t.Run("Channel", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			l, _ := NewChannel(DebugLevel, 100)
+			return l
+		})
+	})
+	t.Run("ECS", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			var sb strings.Builder
+			return NewECS(&sb, DebugLevel)
+		})
+	})
+	t.Run("CloudEvents", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			var sb strings.Builder
+			return NewCloudEvents(&sb, "/test", DebugLevel)
+		})
+	})
+	t.Run("Allowlist", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			var sb strings.Builder
+			return NewAllowlist(New(&sb, DebugLevel, ZeroLogBackend), []string{"str"})
+		})
+	})
+	t.Run("BurstSummary", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			var sb strings.Builder
+			return New(&sb, DebugLevel, ZeroLogBackend, BurstSummary(time.Millisecond))
+		})
+	})
+	t.Run("ByteRateLimit", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			var sb strings.Builder
+			return New(&sb, DebugLevel, ZeroLogBackend, WithByteRateLimit(1<<20))
+		})
+	})
+	t.Run("LevelRemap", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			var sb strings.Builder
+			return New(&sb, DebugLevel, ZeroLogBackend).WithLevelRemap(map[Level]Level{ErrorLevel: WarnLevel})
+		})
+	})
+	t.Run("Labels", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			var sb strings.Builder
+			return New(&sb, DebugLevel, ZeroLogBackend).WithLabels(map[string]string{"env": "test"})
+		})
+	})
+	t.Run("CardinalityGuard", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			var sb strings.Builder
+			return New(&sb, DebugLevel, ZeroLogBackend, CardinalityGuard(1, "str", "int"))
+		})
+	})
+	t.Run("LevelFunc", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			var sb strings.Builder
+			return New(&sb, InfoLevel, ZeroLogBackend, WithLevelFunc(func() Level { return DebugLevel }))
+		})
+	})
+	t.Run("CollapseRepeats", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			var sb strings.Builder
+			return New(&sb, DebugLevel, ZeroLogBackend, CollapseRepeats())
+		})
+	})
+	t.Run("MessagePrefix", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			var sb strings.Builder
+			return New(&sb, DebugLevel, ZeroLogBackend).WithMessagePrefix("tag")
+		})
+	})
+	t.Run("GuardReuse", func(t *testing.T) {
+		RunLoggerConformance(t, func() Logger {
+			var sb strings.Builder
+			return New(&sb, DebugLevel, ZeroLogBackend, GuardReuse())
+		})
+	})
+}
+
+func implName(impl Implementation) string {
+	switch impl {
+	case ZeroLogBackend:
+		return "Zerolog"
+	case LogrusBackend:
+		return "Logrus"
+	case GelfBackend:
+		return "Gelf"
+	}
+	return "Unknown"
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+// +build !windows
+
+package logger
+
+import "io"
+
+// consoleWriter returns w unchanged, consoles on this platform need no adaption
+func consoleWriter(w io.Writer) io.Writer {
+	return w
+}
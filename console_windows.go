@@ -0,0 +1,11 @@
+//go:build windows
+// +build windows
+
+package logger
+
+import "io"
+
+// consoleWriter buffers lines and converts line endings to CRLF for Windows consoles
+func consoleWriter(w io.Writer) io.Writer {
+	return newCRLFWriter(w)
+}
@@ -0,0 +1,37 @@
+package logger
+
+import "context"
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx that carries the logger l. Use FromContext to retrieve it.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx by NewContext. The second return value reports whether ctx carries
+// a logger. If the logger has the option WithContextExtractor, the extracted fields of ctx are added to it.
+func FromContext(ctx context.Context) (Logger, bool) {
+	l, ok := ctx.Value(ctxKey{}).(Logger)
+	if !ok {
+		return nil, false
+	}
+	if c := configOf(l); c != nil && c.ctxExtractor != nil {
+		for k, v := range c.ctxExtractor(ctx) {
+			l = l.WithAny(k, v)
+		}
+	}
+	return l, true
+}
+
+// AddToContextLogger returns a copy of ctx whose logger always logs the specified field, in addition to the fields
+// of the logger in ctx. The logger in ctx itself, and thus in all contexts ctx was derived from, is not modified.
+// If ctx doesn't carry a logger, ctx is returned unchanged.
+func AddToContextLogger(ctx context.Context, key, value string) context.Context {
+	// the stored logger is used, so that extracted fields are not added twice
+	l, ok := ctx.Value(ctxKey{}).(Logger)
+	if !ok {
+		return ctx
+	}
+	return NewContext(ctx, l.WithField(key, value))
+}
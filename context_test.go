@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok, "Empty context should not carry a logger")
+
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	got, ok := FromContext(NewContext(context.Background(), l))
+	assert.True(t, ok, "Context should carry a logger")
+	assert.Equal(t, l, got, "Context should carry the stored logger")
+}
+
+func TestAddToContextLogger(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		parent := NewContext(context.Background(), New(&sb, DebugLevel, impl))
+		child := AddToContextLogger(parent, "request_id", "req1")
+		child = AddToContextLogger(child, "user_id", "user1")
+
+		l, _ := FromContext(child)
+		l.Info().Flush("")
+		s := sb.String()
+		assert.Contains(t, s, "req1", "Logger should contain field of the first layer")
+		assert.Contains(t, s, "user1", "Logger should contain field of the second layer")
+
+		sb.Reset()
+		l, _ = FromContext(parent)
+		l.Info().Flush("")
+		s = sb.String()
+		assert.NotContains(t, s, "req1", "Logger of the parent context should not be modified")
+		assert.NotContains(t, s, "user1", "Logger of the parent context should not be modified")
+	}
+
+	ctx := context.Background()
+	assert.Equal(t, ctx, AddToContextLogger(ctx, "key", "val"), "Context without logger should be returned unchanged")
+}
+
+type requestIDKey struct{}
+
+func TestWithContextExtractor(t *testing.T) {
+	extract := func(ctx context.Context) map[string]interface{} {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return map[string]interface{}{"request_id": id}
+		}
+		return nil
+	}
+	for _, impl := range backends() {
+		var sb strings.Builder
+		ctx := NewContext(context.Background(), New(&sb, DebugLevel, impl, WithContextExtractor(extract)))
+		l, _ := FromContext(ctx)
+		l.Info().Flush("")
+		assert.NotContains(t, sb.String(), "request_id", "Context without value should add nothing")
+
+		sb.Reset()
+		ctx = context.WithValue(ctx, requestIDKey{}, "req1")
+		ctx = AddToContextLogger(ctx, "user_id", "user1")
+		l, _ = FromContext(ctx)
+		l.Info().Flush("")
+		s := sb.String()
+		assert.Contains(t, s, "req1", "Extracted field should be added")
+		assert.Equal(t, 1, strings.Count(s, "request_id"), "Extracted field should be added once")
+		assert.Contains(t, s, "user1", "Added field should be kept")
+	}
+}
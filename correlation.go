@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DefaultCorrelationHeader is the header that carries the correlation id unless SetCorrelationHeader is called
+const DefaultCorrelationHeader = "X-Correlation-ID"
+
+var (
+	correlationMu     sync.RWMutex
+	correlationHeader = DefaultCorrelationHeader
+)
+
+// SetCorrelationHeader sets the name of the header used by InjectCorrelation and ExtractCorrelation. An empty name
+// restores DefaultCorrelationHeader. It is safe for concurrent use and applies to all loggers of this package.
+func SetCorrelationHeader(name string) {
+	if name == "" {
+		name = DefaultCorrelationHeader
+	}
+	correlationMu.Lock()
+	correlationHeader = name
+	correlationMu.Unlock()
+}
+
+func correlationHeaderName() string {
+	correlationMu.RLock()
+	defer correlationMu.RUnlock()
+	return correlationHeader
+}
+
+// ExtractCorrelation returns the correlation id of the header set by SetCorrelationHeader in h, e.g. of an incoming
+// request, or "" if h doesn't have one. Add it to the logger with WithCorrelation and store the logger with
+// NewContext, so that the entries of both services can be stitched together.
+func ExtractCorrelation(h http.Header) string {
+	if h == nil {
+		return ""
+	}
+	return strings.TrimSpace(h.Get(correlationHeaderName()))
+}
+
+// InjectCorrelation makes sure h, e.g. of an outgoing request, carries a correlation id and returns it. An id that h
+// already carries is kept. Otherwise, the id of l set with WithCorrelation is set in h. Only if l has none, a new id
+// is generated with the generator of l, see WithIDGenerator. h must not be nil.
+func InjectCorrelation(l Logger, h http.Header) string {
+	if id := ExtractCorrelation(h); id != "" {
+		return id
+	}
+	id := correlationOf(l)
+	if id == "" {
+		id = newID(configOf(l))
+	}
+	h.Set(correlationHeaderName(), id)
+	return id
+}
+
+// correlationOf returns the correlation id of l set with WithCorrelation, or "" if it has none
+func correlationOf(l Logger) string {
+	for {
+		switch v := l.(type) {
+		case *iLog:
+			return v.id
+		case interface{ wrapped() Logger }:
+			l = v.wrapped()
+		default:
+			return ""
+		}
+	}
+}
+
+// iLog wraps a Logger that logs a correlation id, so that InjectCorrelation can find the id. See
+// Logger.WithCorrelation.
+type iLog struct {
+	wrapLog
+	id string
+}
+
+var _ Logger = (*iLog)(nil)
+
+// newCorrelation returns a Logger that logs the correlation id with each entry of l
+func newCorrelation(l Logger, id string) Logger {
+	if id == "" {
+		return l
+	}
+	return newCorrelationOf(l.WithField("correlation_id", id), id)
+}
+
+// newCorrelationOf returns a Logger that remembers the correlation id of l, which already logs it
+func newCorrelationOf(l Logger, id string) Logger {
+	i := &iLog{id: id}
+	i.wrapLog = wrapLog{l, i}
+	return i
+}
+
+// with returns a logger with the same correlation id that wraps l
+func (i *iLog) with(l Logger) Logger {
+	return newCorrelationOf(l, i.id)
+}
+
+// entry returns e unchanged, the id is a field of the wrapped logger
+func (i *iLog) entry(e Entry, lvl Level) Entry {
+	return e
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelation(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend, WithIDGenerator(func() string { return "id-1" }))
+
+	h := http.Header{}
+	assert.Equal(t, "", ExtractCorrelation(h), "Headers without id should return an empty id")
+	assert.Equal(t, "", ExtractCorrelation(nil), "nil headers should return an empty id")
+	assert.Equal(t, "id-1", InjectCorrelation(l, h), "A new id should be generated")
+	assert.Equal(t, "id-1", h.Get("X-Correlation-ID"), "The id should be set in the header")
+	assert.Equal(t, "id-1", ExtractCorrelation(h))
+
+	h = http.Header{"X-Correlation-Id": {" upstream "}}
+	assert.Equal(t, "upstream", InjectCorrelation(l, h), "Existing ids should be kept")
+	assert.Equal(t, " upstream ", h.Get("X-Correlation-ID"), "Existing ids should not be modified")
+
+	SetCorrelationHeader("X-Request-ID")
+	defer SetCorrelationHeader("")
+	h = http.Header{}
+	InjectCorrelation(l, h)
+	assert.Equal(t, "id-1", h.Get("X-Request-ID"), "The configured header should be used")
+	assert.Equal(t, "id-1", ExtractCorrelation(h))
+	assert.Len(t, InjectCorrelation(New(&sb, DebugLevel, GelfBackend), http.Header{}), 36, "Without generator, a UUID should be generated")
+}
+
+func TestCorrelation_TwoHops(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend, WithIDGenerator(func() string { return "generated" }))
+
+	in := http.Header{"X-Correlation-Id": {"upstream"}}
+	hop := l.WithCorrelation(ExtractCorrelation(in)).WithField("handler", "orders").WithMessagePrefix("orders")
+	out := http.Header{}
+	assert.Equal(t, "upstream", InjectCorrelation(hop, out), "The id of the logger should be injected")
+	assert.Equal(t, "upstream", ExtractCorrelation(out), "The next hop should get the id of the logger")
+	hop.Info().Flush("request")
+	assert.Contains(t, sb.String(), `"correlation_id":"upstream"`, "The id should be logged")
+
+	assert.Equal(t, l, l.WithCorrelation(""), "An empty id should return the logger unchanged")
+	assert.Equal(t, "generated", InjectCorrelation(l.WithCorrelation(""), http.Header{}), "Without id, one should be generated")
+}
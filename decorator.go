@@ -0,0 +1,762 @@
+package logger
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// decorator is a Logger that wraps another Logger to change how its entries are written, like the loggers of the
+// options GuardReuse or BurstSummary. Decorators embed wrapLog and wrapEntry, which forward all methods, and override
+// only the methods they change.
+type decorator interface {
+	Logger
+	// with returns a decorator with the same state that wraps l, e.g. the result of WithField of the wrapped logger
+	with(l Logger) Logger
+	// entry decorates e, a new entry of the wrapped logger with level lvl
+	entry(e Entry, lvl Level) Entry
+}
+
+// wrapLog forwards the methods of Logger to l
+type wrapLog struct {
+	l Logger
+	// self is the decorator that embeds wrapLog. Derived loggers and entries are created by it, and helpers like
+	// Operation log through it.
+	self decorator
+}
+
+// wrapped returns the wrapped logger
+func (w *wrapLog) wrapped() Logger {
+	return w.l
+}
+
+// config returns the options of the wrapped logger
+func (w *wrapLog) config() *config {
+	return configOf(w.l)
+}
+
+// WithField returns a new Logger that always logs the specified field
+func (w *wrapLog) WithField(key, value string) Logger {
+	return w.self.with(w.l.WithField(key, value))
+}
+
+// WithAny returns a new Logger that always logs the specified field. Unlike WithField, the value can be of any type
+func (w *wrapLog) WithAny(key string, value interface{}) Logger {
+	return w.self.with(w.l.WithAny(key, value))
+}
+
+// WithMessagePrefix returns a new Logger that prefixes the message of each entry with prefix and ": ", e.g. for
+// consumers that grep for a tag instead of parsing fields. Empty messages are prefixed, too.
+func (w *wrapLog) WithMessagePrefix(prefix string) Logger {
+	return newPrefix(w.self, prefix)
+}
+
+// WithLevelRemap returns a new Logger that changes the level of its entries according to remap on Flush, e.g.
+// {ErrorLevel: WarnLevel} to keep the errors of a noisy dependency from triggering alerts. The level check of the
+// logger applies to the level before remapping: an entry is only written if its original level is enabled, and
+// then it's written at the new level. An entry remapped to a level below the level of the logger is dropped by the
+// logger, though. Entries at fatal and panic level are never remapped, and no level is remapped to them.
+func (w *wrapLog) WithLevelRemap(remap map[Level]Level) Logger {
+	return newRemap(w.self, remap)
+}
+
+// WithLabels returns a new Logger that adds labels to each entry like AddLabels. Labels of nested calls and of
+// AddLabels are merged into one object, later labels take precedence.
+func (w *wrapLog) WithLabels(labels map[string]string) Logger {
+	return newLabels(w.self, labels)
+}
+
+// WithEnvironment returns a new Logger that always logs the deployment environment env like "prod" under the key
+// "env", e.g. the result of DetectEnvironment. An empty env is logged as "unknown".
+func (w *wrapLog) WithEnvironment(env string) Logger {
+	return w.self.WithField("env", environment(env))
+}
+
+// WithCorrelation returns a new Logger that logs the correlation id under the key "correlation_id", e.g. the result
+// of ExtractCorrelation for an incoming request. InjectCorrelation sets the id in the headers of outgoing requests.
+// An empty id returns the logger unchanged.
+func (w *wrapLog) WithCorrelation(id string) Logger {
+	return newCorrelation(w.self, id)
+}
+
+// Level creates a new Entry with the specified Level
+func (w *wrapLog) Level(lvl Level) Entry {
+	return w.self.entry(w.l.Level(lvl), validLevel(lvl))
+}
+
+// Debug creates a new Entry with level Debug
+func (w *wrapLog) Debug() Entry {
+	return w.self.entry(w.l.Debug(), DebugLevel)
+}
+
+// Info creates a new Entry with level Info
+func (w *wrapLog) Info() Entry {
+	return w.self.entry(w.l.Info(), InfoLevel)
+}
+
+// Warn creates a new Entry with level Warn
+func (w *wrapLog) Warn() Entry {
+	return w.self.entry(w.l.Warn(), WarnLevel)
+}
+
+// Error creates a new Entry with level Error
+func (w *wrapLog) Error() Entry {
+	return w.self.entry(w.l.Error(), ErrorLevel)
+}
+
+// Fatal creates a new Entry with level Fatal. Executing a log at fatal level exits the application with exit code 1,
+// or the code set with FatalExitCode.
+func (w *wrapLog) Fatal() Entry {
+	return w.self.entry(w.l.Fatal(), FatalLevel)
+}
+
+// Panic creates a new Entry with level Panic. Executing a log at panic level will call panic().
+func (w *wrapLog) Panic() Entry {
+	return w.self.entry(w.l.Panic(), PanicLevel)
+}
+
+// PipeWriter returns a writer that logs every line written to it as an entry at the specified level. The entries
+// have the field "stream" set to stream. The last line is logged on Close even if it doesn't end with a newline.
+func (w *wrapLog) PipeWriter(lvl Level, stream string) io.WriteCloser {
+	return newLineWriter(w.self, lvl, stream)
+}
+
+// Operation logs the start of an operation at debug level and returns a function that logs its end together with
+// the duration of the operation. The end is logged at info level, or at error level if a non-nil error is passed.
+// Use it as "defer done(err)". Both entries have the same id under the key "operation_id", see WithIDGenerator.
+func (w *wrapLog) Operation(name string) func(err error) {
+	return operation(w.self, name)
+}
+
+// NewTimer returns a Timer that starts now, e.g. "t := l.NewTimer(); parse(); t.Mark("parse")". Add the phases to an
+// entry with Timer.AddTo.
+func (w *wrapLog) NewTimer() *Timer {
+	return w.l.NewTimer()
+}
+
+// StartHeartbeat logs msg at info level every interval with the time since StartHeartbeat was called under the key
+// "uptime" and the number of goroutines under the key "goroutines", e.g. to show that a service is alive. It returns
+// a function that stops the heartbeat and waits until an entry that is being written is done, calling it more than
+// once is safe. Each call starts an independent heartbeat. If interval isn't greater than zero, nothing is logged
+// and stop does nothing.
+func (w *wrapLog) StartHeartbeat(interval time.Duration, msg string) (stop func()) {
+	return heartbeat(w.self, interval, msg)
+}
+
+// Go runs fn in a new goroutine that recovers from a panic of fn and logs it at error level with the panic value
+// under the key "panic" and the stack under the key "stack", e.g. for background workers, whose panics would crash the
+// process without a log. By default, the panic is swallowed and the goroutine ends. With the option RepanicInGo, the
+// goroutine panics again after logging, which crashes the process.
+func (w *wrapLog) Go(fn func()) {
+	goSafe(w.self, fn)
+}
+
+// Sync flushes buffered entries to the destination without closing the logger. For loggers created by New with the
+// zerolog or gelf backend, the writer is synced if it has a method "Sync() error", like *os.File. Sync does nothing
+// for the logrus backend, for channel loggers and for loggers passed to FromZerolog.
+func (w *wrapLog) Sync() error {
+	return w.l.Sync()
+}
+
+// wrapEntry forwards the methods of Entry to e
+type wrapEntry struct {
+	e Entry
+	// lvl is the level of the entry, At changes it
+	lvl Level
+	// self is the entry that embeds wrapEntry, the methods return it so that it stays in the chain
+	self Entry
+	// observe is called with the name and the arguments of each method before the method is forwarded if it isn't
+	// nil, e.g. to record the calls made on the entry
+	observe func(method string, args ...interface{})
+}
+
+// Flush writes the entry as a single log statement. Optionally, a message can be added which will
+// be included in the final log entry
+func (w *wrapEntry) Flush(msg string) {
+	w.e.Flush(msg)
+}
+
+// Bytes returns the entry in the format of the logger without writing it. The entry can still be flushed afterwards.
+func (w *wrapEntry) Bytes() ([]byte, error) {
+	if w.observe != nil {
+		w.observe("Bytes")
+	}
+	return w.e.Bytes()
+}
+
+// Discard abandons the entry without writing it. The entry must not be used afterwards.
+func (w *wrapEntry) Discard() {
+	if w.observe != nil {
+		w.observe("Discard")
+	}
+	w.e.Discard()
+}
+
+// At changes the level of the entry. The level is evaluated when the entry is flushed.
+func (w *wrapEntry) At(lvl Level) Entry {
+	if w.observe != nil {
+		w.observe("At", lvl)
+	}
+	w.lvl = validLevel(lvl)
+	w.e = w.e.At(lvl)
+	return w.self
+}
+
+// NoTime removes the time field that is added to each entry automatically, e.g. to add the time of an event instead.
+func (w *wrapEntry) NoTime() Entry {
+	if w.observe != nil {
+		w.observe("NoTime")
+	}
+	w.e = w.e.NoTime()
+	return w.self
+}
+
+// AddFields adds a range of fields to the log statement
+func (w *wrapEntry) AddFields(fs map[string]interface{}) Entry {
+	if w.observe != nil {
+		w.observe("AddFields", fs)
+	}
+	w.e = w.e.AddFields(fs)
+	return w.self
+}
+
+// AddErr adds an error to the log statement. The error will have the key "err". An error stack will be included
+// under the key "err_stack". Use the option ErrorKeys to change the keys.
+func (w *wrapEntry) AddErr(err error) Entry {
+	if w.observe != nil {
+		w.observe("AddErr", err)
+	}
+	w.e = w.e.AddErr(err)
+	return w.self
+}
+
+// AddError adds an error to the log statement. An error stack will be included under the key "${key}_stack"
+func (w *wrapEntry) AddError(key string, val error) Entry {
+	if w.observe != nil {
+		w.observe("AddError", key, val)
+	}
+	w.e = w.e.AddError(key, val)
+	return w.self
+}
+
+// AddBool adds a bool value to the log statement.
+func (w *wrapEntry) AddBool(key string, val bool) Entry {
+	if w.observe != nil {
+		w.observe("AddBool", key, val)
+	}
+	w.e = w.e.AddBool(key, val)
+	return w.self
+}
+
+// AddInt adds an integer value to the log statement.
+func (w *wrapEntry) AddInt(key string, val int) Entry {
+	if w.observe != nil {
+		w.observe("AddInt", key, val)
+	}
+	w.e = w.e.AddInt(key, val)
+	return w.self
+}
+
+// AddStr adds a string value to the log statement.
+func (w *wrapEntry) AddStr(key string, val string) Entry {
+	if w.observe != nil {
+		w.observe("AddStr", key, val)
+	}
+	w.e = w.e.AddStr(key, val)
+	return w.self
+}
+
+// AddTime adds a time value to the log statement.
+func (w *wrapEntry) AddTime(key string, val time.Time) Entry {
+	if w.observe != nil {
+		w.observe("AddTime", key, val)
+	}
+	w.e = w.e.AddTime(key, val)
+	return w.self
+}
+
+// AddDur adds a duration value to the log statement.
+func (w *wrapEntry) AddDur(key string, val time.Duration) Entry {
+	if w.observe != nil {
+		w.observe("AddDur", key, val)
+	}
+	w.e = w.e.AddDur(key, val)
+	return w.self
+}
+
+// AddAny adds any value to the log statement.
+func (w *wrapEntry) AddAny(key string, val interface{}) Entry {
+	if w.observe != nil {
+		w.observe("AddAny", key, val)
+	}
+	w.e = w.e.AddAny(key, val)
+	return w.self
+}
+
+// AddJSONRaw adds pre-serialized JSON to the log statement without encoding it again. If raw is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (w *wrapEntry) AddJSONRaw(key string, raw []byte) Entry {
+	if w.observe != nil {
+		w.observe("AddJSONRaw", key, raw)
+	}
+	w.e = w.e.AddJSONRaw(key, raw)
+	return w.self
+}
+
+// AddCount adds a count to the log statement. The number is stored under key, a human readable form like "3 items"
+// is stored under the key "${key}_human"
+func (w *wrapEntry) AddCount(key string, n int, singular, plural string) Entry {
+	if w.observe != nil {
+		w.observe("AddCount", key, n, singular, plural)
+	}
+	w.e = w.e.AddCount(key, n, singular, plural)
+	return w.self
+}
+
+// AddQuery adds a database query to the log statement. The statement, its arguments as JSON array, the number of
+// rows and the duration are stored under the keys "db.sql", "db.args", "db.rows" and "db.duration"
+func (w *wrapEntry) AddQuery(sql string, args []interface{}, rows int, dur time.Duration) Entry {
+	if w.observe != nil {
+		w.observe("AddQuery", sql, args, rows, dur)
+	}
+	w.e = w.e.AddQuery(sql, args, rows, dur)
+	return w.self
+}
+
+// AddTraceparent adds the trace id and span id of the W3C traceparent header in headers under the keys
+// "${key}_trace_id" and "${key}_span_id". A missing or malformed header adds nothing.
+func (w *wrapEntry) AddTraceparent(key string, headers http.Header) Entry {
+	if w.observe != nil {
+		w.observe("AddTraceparent", key, headers)
+	}
+	w.e = w.e.AddTraceparent(key, headers)
+	return w.self
+}
+
+// AddMetric adds a metric to the log statement. Name, value and tags are stored as a nested object under the key
+// "metric", so that log collectors can route the statement to a metrics sink.
+func (w *wrapEntry) AddMetric(name string, value float64, tags map[string]string) Entry {
+	if w.observe != nil {
+		w.observe("AddMetric", name, value, tags)
+	}
+	w.e = w.e.AddMetric(name, value, tags)
+	return w.self
+}
+
+// AddHex adds a byte slice to the log statement, encoded as hex string.
+func (w *wrapEntry) AddHex(key string, val []byte) Entry {
+	if w.observe != nil {
+		w.observe("AddHex", key, val)
+	}
+	w.e = w.e.AddHex(key, val)
+	return w.self
+}
+
+// AddBase64 adds a byte slice to the log statement, encoded as base64 string with standard encoding.
+func (w *wrapEntry) AddBase64(key string, val []byte) Entry {
+	if w.observe != nil {
+		w.observe("AddBase64", key, val)
+	}
+	w.e = w.e.AddBase64(key, val)
+	return w.self
+}
+
+// AddElapsed adds the duration since the specified time to the log statement.
+func (w *wrapEntry) AddElapsed(key string, since time.Time) Entry {
+	if w.observe != nil {
+		w.observe("AddElapsed", key, since)
+	}
+	w.e = w.e.AddElapsed(key, since)
+	return w.self
+}
+
+// AddDurHuman adds a duration to the log statement as human readable string like "1.2s" or "3m04s". Use AddDur for
+// values that should be queryable.
+func (w *wrapEntry) AddDurHuman(key string, d time.Duration) Entry {
+	if w.observe != nil {
+		w.observe("AddDurHuman", key, d)
+	}
+	w.e = w.e.AddDurHuman(key, d)
+	return w.self
+}
+
+// AddErrN adds an error to the log statement like AddErr, but the error stack under the key "err_stack" is
+// truncated to the first maxFrames frames. The error message is kept intact.
+func (w *wrapEntry) AddErrN(err error, maxFrames int) Entry {
+	if w.observe != nil {
+		w.observe("AddErrN", err, maxFrames)
+	}
+	w.e = w.e.AddErrN(err, maxFrames)
+	return w.self
+}
+
+// AddSensitive adds the hex encoded SHA-256 hash of val under the key "${key}_hash" to the log statement. The raw
+// value is never logged. Use the options SensitiveSalt and SensitiveHashLen to configure the hash.
+func (w *wrapEntry) AddSensitive(key string, val string) Entry {
+	if w.observe != nil {
+		w.observe("AddSensitive", key, val)
+	}
+	w.e = w.e.AddSensitive(key, val)
+	return w.self
+}
+
+// AddBytesLen adds the length of a byte slice to the log statement instead of its content. A nil slice has length 0.
+func (w *wrapEntry) AddBytesLen(key string, val []byte) Entry {
+	if w.observe != nil {
+		w.observe("AddBytesLen", key, val)
+	}
+	w.e = w.e.AddBytesLen(key, val)
+	return w.self
+}
+
+// AddStrLen adds the number of runes of a string to the log statement instead of the string itself.
+func (w *wrapEntry) AddStrLen(key string, val string) Entry {
+	if w.observe != nil {
+		w.observe("AddStrLen", key, val)
+	}
+	w.e = w.e.AddStrLen(key, val)
+	return w.self
+}
+
+// AddJSONString adds a JSON encoded string to the log statement without encoding it again. If s is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (w *wrapEntry) AddJSONString(key, s string) Entry {
+	if w.observe != nil {
+		w.observe("AddJSONString", key, s)
+	}
+	w.e = w.e.AddJSONString(key, s)
+	return w.self
+}
+
+// AddRetry adds the state of a retry loop to the log statement. The attempt, the maximum number of attempts and the
+// backoff before the next attempt are stored under the keys "retry.attempt", "retry.max" and "retry.backoff"
+func (w *wrapEntry) AddRetry(attempt, max int, backoff time.Duration) Entry {
+	if w.observe != nil {
+		w.observe("AddRetry", attempt, max, backoff)
+	}
+	w.e = w.e.AddRetry(attempt, max, backoff)
+	return w.self
+}
+
+// AddDeadline adds the time remaining until the deadline of ctx to the log statement. It is negative if the deadline
+// has passed. If ctx has no deadline, nothing is added.
+func (w *wrapEntry) AddDeadline(key string, ctx context.Context) Entry {
+	if w.observe != nil {
+		w.observe("AddDeadline", key, ctx)
+	}
+	w.e = w.e.AddDeadline(key, ctx)
+	return w.self
+}
+
+// AddRequest adds the method, path, remote address, user agent and host of r to the log statement under the keys
+// "http.method", "http.path", "http.remote_addr", "http.user_agent" and "http.host". Other headers are never logged.
+func (w *wrapEntry) AddRequest(r *http.Request) Entry {
+	if w.observe != nil {
+		w.observe("AddRequest", r)
+	}
+	w.e = w.e.AddRequest(r)
+	return w.self
+}
+
+// AddResponse adds the status code, the size of the body and the duration of an HTTP response to the log statement
+// under the keys "http.status", "http.bytes" and "http.duration". The class of the status like "5xx" is stored under
+// the key "http.status_class".
+func (w *wrapEntry) AddResponse(status, bytes int, dur time.Duration) Entry {
+	if w.observe != nil {
+		w.observe("AddResponse", status, bytes, dur)
+	}
+	w.e = w.e.AddResponse(status, bytes, dur)
+	return w.self
+}
+
+// AddSlice adds a slice or an array of any element type to the log statement as JSON array. Other values, or slices
+// that can't be encoded as JSON, are added like with AddAny.
+func (w *wrapEntry) AddSlice(key string, vals interface{}) Entry {
+	if w.observe != nil {
+		w.observe("AddSlice", key, vals)
+	}
+	w.e = w.e.AddSlice(key, vals)
+	return w.self
+}
+
+// AddBoolPtr adds the value of a bool pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (w *wrapEntry) AddBoolPtr(key string, val *bool) Entry {
+	if w.observe != nil {
+		w.observe("AddBoolPtr", key, val)
+	}
+	w.e = w.e.AddBoolPtr(key, val)
+	return w.self
+}
+
+// AddIntPtr adds the value of an integer pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (w *wrapEntry) AddIntPtr(key string, val *int) Entry {
+	if w.observe != nil {
+		w.observe("AddIntPtr", key, val)
+	}
+	w.e = w.e.AddIntPtr(key, val)
+	return w.self
+}
+
+// AddStrPtr adds the value of a string pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (w *wrapEntry) AddStrPtr(key string, val *string) Entry {
+	if w.observe != nil {
+		w.observe("AddStrPtr", key, val)
+	}
+	w.e = w.e.AddStrPtr(key, val)
+	return w.self
+}
+
+// AddCaller adds the location that called it as "file:line" under the key "caller" and the calling function under
+// the key "caller_func" to the log statement. Unlike the option ReportCaller, it only applies to this entry.
+func (w *wrapEntry) AddCaller() Entry {
+	if w.observe != nil {
+		w.observe("AddCaller")
+	}
+	w.e = w.e.AddCaller()
+	return w.self
+}
+
+// AddCallerSkip adds the caller like AddCaller, but skips n additional frames, e.g. in helpers that wrap an Entry.
+func (w *wrapEntry) AddCallerSkip(n int) Entry {
+	if w.observe != nil {
+		w.observe("AddCallerSkip", n)
+	}
+	w.e = w.e.AddCallerSkip(n)
+	return w.self
+}
+
+// AddIntThreshold adds a value to the log statement like AddInt and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (w *wrapEntry) AddIntThreshold(key string, val, threshold int, flagKey string) Entry {
+	if w.observe != nil {
+		w.observe("AddIntThreshold", key, val, threshold, flagKey)
+	}
+	w.e = w.e.AddIntThreshold(key, val, threshold, flagKey)
+	return w.self
+}
+
+// AddDurThreshold adds a value to the log statement like AddDur and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (w *wrapEntry) AddDurThreshold(key string, val, threshold time.Duration, flagKey string) Entry {
+	if w.observe != nil {
+		w.observe("AddDurThreshold", key, val, threshold, flagKey)
+	}
+	w.e = w.e.AddDurThreshold(key, val, threshold, flagKey)
+	return w.self
+}
+
+// AddMoney adds an amount of money in minor units of an ISO 4217 currency, e.g. cents for "USD", to the log statement.
+// The amount and the currency are stored under the keys "${key}.amount_minor" and "${key}.currency", the formatted
+// amount like "$12.99" under the key "${key}.display". Unknown currencies, including an empty code, are formatted
+// with two decimal places and flagged with true under the key "${key}.currency_unknown".
+func (w *wrapEntry) AddMoney(key string, minorUnits int64, currency string) Entry {
+	if w.observe != nil {
+		w.observe("AddMoney", key, minorUnits, currency)
+	}
+	w.e = w.e.AddMoney(key, minorUnits, currency)
+	return w.self
+}
+
+// AddErrChain adds the message of an error under key, the number of errors it wraps under the key "${key}_depth" and
+// the type names of the errors in its chain, starting with err, under the key "${key}_types" to the log statement.
+// Both the standard library's wrapping and juju/errors are followed. A nil error adds nothing.
+func (w *wrapEntry) AddErrChain(key string, err error) Entry {
+	if w.observe != nil {
+		w.observe("AddErrChain", key, err)
+	}
+	w.e = w.e.AddErrChain(key, err)
+	return w.self
+}
+
+// AddInterval adds a time window to the log statement. Start and end are stored in UTC under the keys "${key}.start"
+// and "${key}.end", the duration between them under the key "${key}.duration". If end is before start, the duration
+// is negative.
+func (w *wrapEntry) AddInterval(key string, start, end time.Time) Entry {
+	if w.observe != nil {
+		w.observe("AddInterval", key, start, end)
+	}
+	w.e = w.e.AddInterval(key, start, end)
+	return w.self
+}
+
+// AddValidationErrors adds the results of a failed validation to the log statement as nested object that maps each
+// invalid field to its error message, e.g. {"email": "must not be empty"}. A nil or empty map adds nothing.
+func (w *wrapEntry) AddValidationErrors(key string, errs map[string]string) Entry {
+	if w.observe != nil {
+		w.observe("AddValidationErrors", key, errs)
+	}
+	w.e = w.e.AddValidationErrors(key, errs)
+	return w.self
+}
+
+// AddGeo adds coordinates to the log statement as nested object with the keys "lat" and "lon", which Elasticsearch
+// maps as geo_point. If the latitude isn't within [-90, 90] or the longitude isn't within [-180, 180], the
+// coordinates are still added and "${key}_invalid" is set to true.
+func (w *wrapEntry) AddGeo(key string, lat, lon float64) Entry {
+	if w.observe != nil {
+		w.observe("AddGeo", key, lat, lon)
+	}
+	w.e = w.e.AddGeo(key, lat, lon)
+	return w.self
+}
+
+// Retention sets the retention class of the entry under the key "retention", e.g. "short", "long" or "audit", so
+// that the log pipeline can route it to a storage tier. It overrides the class set with DefaultRetention.
+func (w *wrapEntry) Retention(class string) Entry {
+	if w.observe != nil {
+		w.observe("Retention", class)
+	}
+	w.e = w.e.Retention(class)
+	return w.self
+}
+
+// AddDiff adds the old and the new value of a change to the log statement as nested object with the keys "old" and
+// "new". If both values are equal according to reflect.DeepEqual, nothing is added.
+func (w *wrapEntry) AddDiff(key string, oldVal, newVal interface{}) Entry {
+	if w.observe != nil {
+		w.observe("AddDiff", key, oldVal, newVal)
+	}
+	w.e = w.e.AddDiff(key, oldVal, newVal)
+	return w.self
+}
+
+// AddRuntime adds diagnostics of the Go runtime to the log statement: the Go version, the operating system and the
+// architecture under the keys "go.version", "go.os" and "go.arch", the number of goroutines under the key
+// "go.goroutines" and the allocated heap in MiB under the key "go.heap_mb". Reading the heap stops the world, so use
+// it for rare entries like crash reports, not in hot paths.
+func (w *wrapEntry) AddRuntime() Entry {
+	if w.observe != nil {
+		w.observe("AddRuntime")
+	}
+	w.e = w.e.AddRuntime()
+	return w.self
+}
+
+// AddForm adds the values of the listed form fields to the log statement under the keys "form.${name}". Multiple
+// values of a field are joined with commas. Fields that aren't listed are never added, e.g. passwords, and listed
+// fields missing from values are skipped.
+func (w *wrapEntry) AddForm(values url.Values, keys ...string) Entry {
+	if w.observe != nil {
+		w.observe("AddForm", values, keys)
+	}
+	w.e = w.e.AddForm(values, keys...)
+	return w.self
+}
+
+// AddBackoff computes the wait before the next attempt of a retry loop and adds it to the log statement together with
+// the attempt under the keys "backoff.wait" and "backoff.attempt". The wait doubles with each attempt, starting at
+// base for attempt 1, and is capped at max. Half of it is random jitter, so that clients don't retry in lockstep.
+// The wait is returned so that the caller can sleep on it.
+func (w *wrapEntry) AddBackoff(attempt int, base, max time.Duration) (Entry, time.Duration) {
+	if w.observe != nil {
+		w.observe("AddBackoff", attempt, base, max)
+	}
+	e, d := w.e.AddBackoff(attempt, base, max)
+	w.e = e
+	return w.self, d
+}
+
+// AddLabels adds a set of labels like environment, team or service to the log statement as nested object under the
+// key "labels". Label names that don't match the Prometheus label name syntax [a-zA-Z_][a-zA-Z0-9_]* are still added
+// and listed under the key "labels_invalid". Labels of a logger created by WithLabels are merged with labels, with
+// labels taking precedence. A nil or empty map adds nothing.
+func (w *wrapEntry) AddLabels(labels map[string]string) Entry {
+	if w.observe != nil {
+		w.observe("AddLabels", labels)
+	}
+	w.e = w.e.AddLabels(labels)
+	return w.self
+}
+
+// AddPercent adds a fraction like a success ratio to the log statement under key and the same value as percentage
+// string with one decimal under "${key}_pct", e.g. 0.873 as "87.3%", so that dashboards don't mix fractions and
+// percentages. A fraction outside [0, 1] is clamped and "${key}_invalid" is set to true, NaN is clamped to 0.
+func (w *wrapEntry) AddPercent(key string, fraction float64) Entry {
+	if w.observe != nil {
+		w.observe("AddPercent", key, fraction)
+	}
+	w.e = w.e.AddPercent(key, fraction)
+	return w.self
+}
+
+// AddNullString adds the value of a sql.NullString to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (w *wrapEntry) AddNullString(key string, val sql.NullString) Entry {
+	if w.observe != nil {
+		w.observe("AddNullString", key, val)
+	}
+	w.e = w.e.AddNullString(key, val)
+	return w.self
+}
+
+// AddNullInt64 adds the value of a sql.NullInt64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (w *wrapEntry) AddNullInt64(key string, val sql.NullInt64) Entry {
+	if w.observe != nil {
+		w.observe("AddNullInt64", key, val)
+	}
+	w.e = w.e.AddNullInt64(key, val)
+	return w.self
+}
+
+// AddNullFloat64 adds the value of a sql.NullFloat64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (w *wrapEntry) AddNullFloat64(key string, val sql.NullFloat64) Entry {
+	if w.observe != nil {
+		w.observe("AddNullFloat64", key, val)
+	}
+	w.e = w.e.AddNullFloat64(key, val)
+	return w.self
+}
+
+// AddNullBool adds the value of a sql.NullBool to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (w *wrapEntry) AddNullBool(key string, val sql.NullBool) Entry {
+	if w.observe != nil {
+		w.observe("AddNullBool", key, val)
+	}
+	w.e = w.e.AddNullBool(key, val)
+	return w.self
+}
+
+// AddKV adds alternating keys and values to the log statement, e.g. AddKV("user", id, "ok", true), like AddAny for
+// each pair. It trades the type safety of the typed methods for brevity. Keys that aren't strings are formatted with
+// fmt.Sprint. If the number of arguments is odd, the last key is added under "kv_warning" as missing its value.
+func (w *wrapEntry) AddKV(pairs ...interface{}) Entry {
+	if w.observe != nil {
+		w.observe("AddKV", pairs)
+	}
+	w.e = w.e.AddKV(pairs...)
+	return w.self
+}
+
+// AddBigRat adds an arbitrary-precision rational number to the log statement as string, so that exact values like
+// amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default. nil
+// is added as "<nil>".
+func (w *wrapEntry) AddBigRat(key string, val *big.Rat) Entry {
+	if w.observe != nil {
+		w.observe("AddBigRat", key, val)
+	}
+	w.e = w.e.AddBigRat(key, val)
+	return w.self
+}
+
+// AddBigFloat adds an arbitrary-precision floating-point number to the log statement as string, so that exact values
+// like amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default.
+// nil is added as "<nil>".
+func (w *wrapEntry) AddBigFloat(key string, val *big.Float) Entry {
+	if w.observe != nil {
+		w.observe("AddBigFloat", key, val)
+	}
+	w.e = w.e.AddBigFloat(key, val)
+	return w.self
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+)
+
+// diagnose logs the effective configuration of l, which was created by New with lvl and impl. It is logged at info
+// level, or at lvl if only more severe entries are written, so that it is always visible.
+func diagnose(l Logger, lvl Level, impl Implementation, c *config) {
+	lvl = validLevel(lvl)
+	at := lvl
+	if at > InfoLevel {
+		at = InfoLevel
+	}
+	l.Level(at).AddFields(c.describe(lvl, impl)).Flush("logger configured")
+}
+
+// describe returns the fields that describe the configuration of a logger created by New with lvl and impl
+func (c *config) describe(lvl Level, impl Implementation) map[string]interface{} {
+	fs := map[string]interface{}{
+		"logger.level":             ltoz(lvl).String(),
+		"logger.backend":           backendName(impl),
+		"logger.debug_sample_rate": c.debugSampleRate,
+		"logger.time_format":       timeFormatName(c.timeFormat),
+	}
+	if len(c.levelSampling) > 0 {
+		sampling := make(map[string]float64, len(c.levelSampling))
+		for l, p := range c.levelSampling {
+			sampling[ltoz(validLevel(l)).String()] = p
+		}
+		fs["logger.level_sampling"] = sampling
+	}
+	if c.formatter != nil && impl == LogrusBackend {
+		fs["logger.formatter"] = fmt.Sprintf("%T", c.formatter)
+	}
+	if c.burstWindow > 0 {
+		fs["logger.burst_window"] = c.burstWindow
+	}
+	if c.nestKey != "" {
+		fs["logger.nest_fields"] = c.nestKey
+	}
+	if c.cardinalityLimit > 0 {
+		fs["logger.cardinality_limit"] = c.cardinalityLimit
+	}
+	if c.byteRate > 0 {
+		fs["logger.byte_rate_limit"] = c.byteRate
+	}
+	if c.maxInt > 0 {
+		fs["logger.quote_ints_above"] = c.maxInt
+	}
+	if len(c.scrub) > 0 {
+		fs["logger.scrub_patterns"] = len(c.scrub)
+	}
+	if c.bigPrec >= 0 {
+		fs["logger.big_precision"] = c.bigPrec
+	}
+	if c.exitCode != 1 {
+		fs["logger.fatal_exit_code"] = c.exitCode
+	}
+	flags := map[string]bool{
+		"AuditMode":          c.audit,
+		"AuditHashChain":     c.auditChain,
+		"CollapseRepeats":    c.collapseRepeats,
+		"ConsoleWriter":      c.console,
+		"FlagBelowThreshold": c.flagBelow,
+		"GuardReuse":         c.guardReuse,
+		"NilAsNull":          c.nilNull,
+		"OmitEmpty":          c.omitEmpty,
+		"OnPanic":            c.onPanic != nil,
+		"WithLevelFunc":      c.levelFunc != nil,
+		"RepanicInGo":        c.repanicGo,
+		"ReportCaller":       c.reportCaller,
+		"TrustForwardedFor":  c.forwardedFor,
+		"WithBuildInfo":      c.buildInfo,
+		"WithGoroutineID":    c.goroutineID,
+	}
+	opts := []string{}
+	for name, on := range flags {
+		if on {
+			opts = append(opts, name)
+		}
+	}
+	sort.Strings(opts)
+	fs["logger.options"] = opts
+	return fs
+}
+
+func backendName(impl Implementation) string {
+	switch impl {
+	case LogrusBackend:
+		return "logrus"
+	case GelfBackend:
+		return "gelf"
+	}
+	return "zerolog"
+}
+
+func timeFormatName(f TimeFormat) string {
+	switch f {
+	case EpochMillis:
+		return "epoch_millis"
+	case EpochNanos:
+		return "epoch_nanos"
+	}
+	return "default"
+}
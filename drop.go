@@ -0,0 +1,18 @@
+package logger
+
+import "sync/atomic"
+
+// dropped counts the entries that were dropped by any mechanism, e.g. sampling, a full channel or a rate limit
+var dropped uint64
+
+// DroppedCount returns the number of entries that were dropped since the start of the application, e.g. by sampling,
+// because a channel was full, by a failing hook or by the byte rate limit. It is safe for concurrent use and can be
+// exposed as metric.
+func DroppedCount() uint64 {
+	return atomic.LoadUint64(&dropped)
+}
+
+// drop increments the counter returned by DroppedCount
+func drop() {
+	atomic.AddUint64(&dropped, 1)
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"io"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ECSVersion is the version of the Elastic Common Schema NewECS conforms to
+const ECSVersion = "1.6.0"
+
+// NewECS creates a logger that writes JSON in the Elastic Common Schema, which Kibana understands without further
+// processing. Each entry has the fields "@timestamp", "log.level", "message" and "ecs.version", AddErr stores errors
+// under "error.message" and "error.stack_trace". It uses the zerolog backend.
+func NewECS(w io.Writer, lvl Level, opts ...Option) Logger {
+	opts = append([]Option{ErrorKeys("error.message", "error.stack_trace")}, opts...)
+	c := newConfig(opts)
+	lvl = c.threshold(lvl)
+	c.ecs = true
+	c.ecsLevel = lvl
+	return c.wrap(newZeroLog(w, lvl, c), lvl)
+}
+
+// ecsWrite adds the ECS base fields to the event e of an entry and writes it
+func ecsWrite(e *zerolog.Event, c *config, lvl Level, msg string, timestamp bool, created time.Time) {
+	if timestamp {
+		e.Str("@timestamp", c.stamp(created).UTC().Format(time.RFC3339Nano))
+	}
+	e.Str("log.level", ltoz(lvl).String())
+	e.Str("ecs.version", ECSVersion)
+	e.Str("message", msg)
+	// This skips a message in zerolog
+	e.Msg("")
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewECS(t *testing.T) {
+	var sb strings.Builder
+	l := NewECS(&sb, InfoLevel).WithField("service", "api")
+	l.Debug().Flush("hidden")
+	l.Warn().AddErr(errors.New("failed")).Flush("message")
+	var m map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(sb.String()), &m), "Output should be a single JSON object")
+	assert.Equal(t, "warn", m["log.level"], "Entry should contain the level")
+	assert.Equal(t, "message", m["message"], "Entry should contain the message")
+	assert.Equal(t, ECSVersion, m["ecs.version"], "Entry should contain the ECS version")
+	assert.Equal(t, "failed", m["error.message"], "Entry should contain the error message")
+	assert.Contains(t, m, "error.stack_trace", "Entry should contain the error stack")
+	assert.Contains(t, m, "@timestamp", "Entry should contain the timestamp")
+	assert.Equal(t, "api", m["service"], "Entry should contain the fields of the logger")
+	assert.NotContains(t, m, "level", "Entry should not contain the zerolog level")
+	assert.NotContains(t, m, "time", "Entry should not contain the zerolog time")
+}
+
+func TestNewECS_Level(t *testing.T) {
+	var sb strings.Builder
+	l := NewECS(&sb, WarnLevel)
+	l.Info().Flush("info")
+	l.Info().At(ErrorLevel).Flush("raised")
+	s := sb.String()
+	assert.NotContains(t, s, `"info"`, "Entries below the level should be skipped")
+	assert.Contains(t, s, `"raised"`, "Raised entries should be written")
+	assert.Equal(t, 1, exitCode(func() { l.Fatal().Flush("fatal") }), "Fatal should exit")
+}
@@ -0,0 +1,19 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AddEnum adds a value of a string based type, like "type Status string", to e under key as its string value, so
+// call sites don't need a conversion. Values of other kinds are added with fmt.Sprint.
+//
+// Go generics would restrict val to string based types at compile time, but this module supports Go versions
+// without them.
+func AddEnum(e Entry, key string, val interface{}) Entry {
+	v := reflect.ValueOf(val)
+	if v.Kind() == reflect.String {
+		return e.AddStr(key, v.String())
+	}
+	return e.AddStr(key, fmt.Sprint(val))
+}
@@ -0,0 +1,27 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type status string
+
+// String is ignored by AddEnum, the string value is logged
+func (s status) String() string { return "status " + string(s) }
+
+func TestAddEnum(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		e := New(&sb, DebugLevel, impl).Info()
+		e = AddEnum(e, "status", status("active"))
+		e = AddEnum(e, "other", 42)
+		e.Flush("")
+		s := sb.String()
+		assert.Contains(t, s, "active", "Message should contain the enum value")
+		assert.NotContains(t, s, "status active", "Message should contain the string value, not String()")
+		assert.Contains(t, s, "42", "Message should contain other values")
+	}
+}
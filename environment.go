@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// DefaultEnvironmentVar is the environment variable read by DetectEnvironment unless SetEnvironmentVar is called
+const DefaultEnvironmentVar = "APP_ENV"
+
+// UnknownEnvironment is the environment of loggers whose environment is empty or can't be detected
+const UnknownEnvironment = "unknown"
+
+var (
+	environmentMu  sync.RWMutex
+	environmentVar = DefaultEnvironmentVar
+)
+
+// SetEnvironmentVar sets the name of the environment variable read by DetectEnvironment. An empty name restores
+// DefaultEnvironmentVar. It is safe for concurrent use and applies to all loggers of this package.
+func SetEnvironmentVar(name string) {
+	if name == "" {
+		name = DefaultEnvironmentVar
+	}
+	environmentMu.Lock()
+	environmentVar = name
+	environmentMu.Unlock()
+}
+
+// DetectEnvironment returns the deployment environment like "prod" or "staging" from the environment variable set
+// by SetEnvironmentVar, APP_ENV by default. If the variable is unset or empty, it returns UnknownEnvironment. Pass
+// the result to Logger.WithEnvironment.
+func DetectEnvironment() string {
+	environmentMu.RLock()
+	name := environmentVar
+	environmentMu.RUnlock()
+	return environment(os.Getenv(name))
+}
+
+// environment returns env without surrounding whitespace, or UnknownEnvironment if it's empty
+func environment(env string) string {
+	if env = strings.TrimSpace(env); env != "" {
+		return env
+	}
+	return UnknownEnvironment
+}
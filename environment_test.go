@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectEnvironment(t *testing.T) {
+	defer os.Unsetenv("APP_ENV")
+	defer os.Unsetenv("DEPLOY_ENV")
+
+	os.Unsetenv("APP_ENV")
+	assert.Equal(t, "unknown", DetectEnvironment(), "Unset variables should fall back to unknown")
+	os.Setenv("APP_ENV", " ")
+	assert.Equal(t, "unknown", DetectEnvironment(), "Empty variables should fall back to unknown")
+	os.Setenv("APP_ENV", "staging")
+	assert.Equal(t, "staging", DetectEnvironment())
+
+	SetEnvironmentVar("DEPLOY_ENV")
+	defer SetEnvironmentVar("")
+	os.Setenv("DEPLOY_ENV", "prod")
+	assert.Equal(t, "prod", DetectEnvironment(), "The configured variable should be read")
+}
+
+func TestWithEnvironment(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl)
+		l.WithEnvironment("prod").Info().Flush("")
+		assert.Contains(t, sb.String(), "prod", "%s: Environment should be logged", implName(impl))
+
+		sb.Reset()
+		l.WithEnvironment("").Info().Flush("")
+		assert.Contains(t, sb.String(), "unknown", "%s: Empty environments should be logged as unknown", implName(impl))
+	}
+
+	l, ch := NewChannel(DebugLevel, 1, GuardReuse())
+	l.WithMessagePrefix("tag").WithEnvironment("dev").Info().Flush("")
+	assert.Equal(t, "dev", (<-ch).Fields["env"], "Decorated loggers should log the environment")
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EventLogID is the event id of the events written by a logger created with NewEventLog
+const EventLogID = 1
+
+// eventType is the type of an event in the Windows Event Log
+type eventType int
+
+const (
+	eventInfo eventType = iota
+	eventWarning
+	eventError
+)
+
+// eventTypeOf maps lvl to the type of an event: error, fatal and panic to Error, warn to Warning, and info and debug
+// to Information, as the Event Log has no debug type
+func eventTypeOf(lvl Level) eventType {
+	switch {
+	case lvl <= ErrorLevel:
+		return eventError
+	case lvl == WarnLevel:
+		return eventWarning
+	}
+	return eventInfo
+}
+
+// eventMessage returns the message of the event of e: the message of the entry followed by its fields as
+// "key=value" lines sorted by key, as Event Viewer shows the message only
+func eventMessage(e CapturedEntry) string {
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString(e.Message)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "\r\n%s=%v", k, e.Fields[k])
+	}
+	return sb.String()
+}
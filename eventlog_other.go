@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package logger
+
+import (
+	"runtime"
+
+	"github.com/juju/errors"
+)
+
+// NewEventLog creates a logger that writes to the Windows Event Log. On this platform, it returns an error that
+// satisfies errors.IsNotSupported of github.com/juju/errors.
+func NewEventLog(source string, lvl Level, opts ...Option) (Logger, error) {
+	return nil, errors.NotSupportedf("Windows Event Log on %s", runtime.GOOS)
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventTypeOf(t *testing.T) {
+	tests := []struct {
+		lvl  Level
+		want eventType
+	}{
+		{DebugLevel, eventInfo},
+		{InfoLevel, eventInfo},
+		{WarnLevel, eventWarning},
+		{ErrorLevel, eventError},
+		{FatalLevel, eventError},
+		{PanicLevel, eventError},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, eventTypeOf(tt.lvl), "Level %d", tt.lvl)
+	}
+}
+
+func TestEventMessage(t *testing.T) {
+	e := CapturedEntry{Message: "service started", Fields: map[string]interface{}{"port": 8080, "env": "prod"}}
+	assert.Equal(t, "service started\r\nenv=prod\r\nport=8080", eventMessage(e), "Fields should be appended sorted")
+	assert.Equal(t, "", eventMessage(CapturedEntry{}))
+}
+
+func TestNewEventLog(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Registering an event source requires administrator rights")
+	}
+	l, err := NewEventLog("logger-test", InfoLevel)
+	assert.Nil(t, l)
+	assert.True(t, errors.IsNotSupported(err), "The Event Log should not be supported on %s", runtime.GOOS)
+}
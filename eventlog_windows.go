@@ -0,0 +1,68 @@
+//go:build windows
+// +build windows
+
+package logger
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// NewEventLog creates a logger that writes each entry at level lvl or above as event of source to the Windows Event
+// Log, e.g. for services whose operators use Event Viewer. Entries at error, fatal and panic level are written as
+// errors, at warn level as warnings, and at info and debug level as information. The fields of an entry are appended
+// to its message as "key=value" lines, and each event has the id EventLogID. The time of an event is the time it is
+// written. The source is registered if it doesn't exist, which requires administrator rights once. The event log
+// stays open for the lifetime of the process.
+func NewEventLog(source string, lvl Level, opts ...Option) (Logger, error) {
+	if !eventSourceExists(source) {
+		err := eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info)
+		if err != nil {
+			return nil, errors.Annotatef(err, "register event source %q", source)
+		}
+	}
+	el, err := eventlog.Open(source)
+	if err != nil {
+		return nil, errors.Annotatef(err, "open event log of source %q", source)
+	}
+	c := newConfig(opts)
+	lvl = c.threshold(lvl)
+	return c.wrap(&cLog{sink: &elSink{log: el}, level: lvl, cfg: c}, lvl), nil
+}
+
+// eventSourceExists reports whether source is registered. Checking it doesn't require administrator rights, unlike
+// registering it.
+func eventSourceExists(source string) bool {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, eventSourceKey+source, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	k.Close()
+	return true
+}
+
+// eventSourceKey is the registry key under which the sources of the Application log are registered
+const eventSourceKey = `SYSTEM\CurrentControlSet\Services\EventLog\Application\`
+
+// elSink writes entries to the Windows Event Log
+type elSink struct {
+	mu  sync.Mutex
+	log *eventlog.Log
+}
+
+func (s *elSink) deliver(e CapturedEntry) {
+	msg := eventMessage(e)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch eventTypeOf(e.Level) {
+	case eventError:
+		s.log.Error(EventLogID, msg)
+	case eventWarning:
+		s.log.Warning(EventLogID, msg)
+	default:
+		s.log.Info(EventLogID, msg)
+	}
+}
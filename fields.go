@@ -0,0 +1,286 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// backoff returns the wait before the attempt after attempt: base doubled for each attempt after the first, capped
+// at max, of which a random part up to half is subtracted as jitter. An attempt below 1 is treated as 1. A base or max
+// of 0 or less doesn't wait.
+func backoff(attempt int, base, max time.Duration) time.Duration {
+	if base <= 0 || max <= 0 {
+		return 0
+	}
+	d := base
+	if attempt > 1 {
+		for i := 1; i < attempt && d < max; i++ {
+			if d > max/2 {
+				d = max
+				break
+			}
+			d *= 2
+		}
+	}
+	if d > max {
+		d = max
+	}
+	half := d / 2
+	return d - half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// promLabelName matches valid Prometheus label names
+var promLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// mergeLabels returns a new map with the labels of a and b, b takes precedence. If b is empty, a is returned.
+func mergeLabels(a, b map[string]string) map[string]string {
+	if len(b) == 0 {
+		return a
+	}
+	m := make(map[string]string, len(a)+len(b))
+	for k, v := range a {
+		m[k] = v
+	}
+	for k, v := range b {
+		m[k] = v
+	}
+	return m
+}
+
+// addLabels adds labels to e under the key "labels" and the sorted names that aren't valid Prometheus label names
+// under the key "labels_invalid"
+func addLabels(e Entry, labels map[string]string) Entry {
+	if len(labels) == 0 {
+		return e
+	}
+	var invalid []string
+	for k := range labels {
+		if !promLabelName.MatchString(k) {
+			invalid = append(invalid, k)
+		}
+	}
+	e = e.AddAny("labels", mergeLabels(nil, labels))
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		e = e.AddSlice("labels_invalid", invalid)
+	}
+	return e
+}
+
+// pluralize returns n followed by singular if n is 1 and by plural otherwise, e.g. "1 item" or "3 items"
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return strconv.Itoa(n) + " " + singular
+	}
+	return strconv.Itoa(n) + " " + plural
+}
+
+// humanDur formats d with a unit that fits its magnitude, e.g. "350µs", "12.5ms", "1.2s", "3m04s" or "2h05m"
+func humanDur(d time.Duration) string {
+	if d < 0 {
+		return "-" + humanDur(-d)
+	}
+	switch {
+	case d < time.Microsecond:
+		return fmt.Sprintf("%dns", d)
+	case d < time.Millisecond:
+		return fmt.Sprintf("%dµs", d/time.Microsecond)
+	case d < time.Second:
+		return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", d/time.Minute, d%time.Minute/time.Second)
+	}
+	return fmt.Sprintf("%dh%02dm", d/time.Hour, d%time.Hour/time.Minute)
+}
+
+// truncateStack keeps the first n lines of the error stack st and replaces the rest with "...N more"
+func truncateStack(st string, n int) string {
+	lines := strings.Split(st, "\n")
+	if n < 0 {
+		n = 0
+	}
+	if len(lines) <= n {
+		return st
+	}
+	more := fmt.Sprintf("...%d more", len(lines)-n)
+	if n == 0 {
+		return more
+	}
+	return strings.Join(lines[:n], "\n") + "\n" + more
+}
+
+// maxErrChain limits the walk of errChain, e.g. for errors that wrap themselves, so that it always terminates
+const maxErrChain = 100
+
+// errChain walks the chain of errors wrapped by err and returns the number of wrapped errors and the concrete type
+// names of all errors in the chain, starting with err. It follows Unwrap of the standard library, Underlying of
+// juju/errors and Cause of errors that don't implement either.
+func errChain(err error) (int, []string) {
+	var types []string
+	for err != nil && len(types) < maxErrChain {
+		types = append(types, fmt.Sprintf("%T", err))
+		var next error
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			next = e.Unwrap()
+		case interface{ Underlying() error }:
+			next = e.Underlying()
+		case interface{ Cause() error }:
+			next = e.Cause()
+		}
+		err = next
+	}
+	return len(types) - 1, types
+}
+
+// sliceJSON returns vals encoded as JSON array if it is a slice or an array. A nil slice is encoded as empty array.
+func sliceJSON(vals interface{}) ([]byte, bool) {
+	v := reflect.ValueOf(vals)
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return []byte("[]"), true
+		}
+	case reflect.Array:
+	default:
+		return nil, false
+	}
+	b, err := json.Marshal(vals)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// thresholdFlag adds flagKey to e as true if exceeded is set, and as false if below is set
+func thresholdFlag(e Entry, flagKey string, exceeded, below bool) Entry {
+	if exceeded || below {
+		return e.AddBool(flagKey, exceeded)
+	}
+	return e
+}
+
+// metric is the object AddMetric stores under the key "metric"
+type metric struct {
+	Name  string            `json:"name"`
+	Value float64           `json:"value"`
+	Tags  map[string]string `json:"tags,omitempty"`
+}
+
+// geoPoint is the object AddGeo stores, compatible with the geo_point type of Elasticsearch
+type geoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// valid reports whether the latitude is within [-90, 90] and the longitude within [-180, 180]
+func (p geoPoint) valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+// percent clamps fraction to [0, 1] and formats it as percentage with one decimal, like "87.3%". ok is false if
+// fraction was outside [0, 1] or NaN, which is clamped to 0.
+func percent(fraction float64) (f float64, pct string, ok bool) {
+	switch {
+	case math.IsNaN(fraction) || fraction < 0:
+		f = 0
+	case fraction > 1:
+		f = 1
+	default:
+		f, ok = fraction, true
+	}
+	return f, strconv.FormatFloat(f*100, 'f', 1, 64) + "%", ok
+}
+
+// addKV adds the alternating keys and values of pairs to e, see Entry.AddKV
+func addKV(e Entry, pairs []interface{}) Entry {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			key = fmt.Sprint(pairs[i])
+		}
+		e = e.AddAny(key, pairs[i+1])
+	}
+	if len(pairs)%2 == 1 {
+		e = e.AddStr("kv_warning", fmt.Sprintf("missing value for key %v", pairs[len(pairs)-1]))
+	}
+	return e
+}
+
+// diff is the object AddDiff stores
+type diff struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// heapMB returns the bytes of allocated heap objects in MiB. It reads the memory statistics of the runtime, which
+// stops the world.
+func heapMB() float64 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return float64(ms.HeapAlloc) / (1 << 20)
+}
+
+// isZero reports whether v is nil or the zero value of its type
+func isZero(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	return reflect.DeepEqual(v, reflect.Zero(reflect.TypeOf(v)).Interface())
+}
+
+// largeInt returns val formatted as string if it is an integer whose absolute value exceeds max
+func largeInt(val interface{}, max uint64) (string, bool) {
+	var n int64
+	switch v := val.(type) {
+	case int:
+		n = int64(v)
+	case int64:
+		n = v
+	case int32:
+		n = int64(v)
+	case uint:
+		return largeUint(uint64(v), max)
+	case uint64:
+		return largeUint(v, max)
+	case uint32:
+		return largeUint(uint64(v), max)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			n = i
+		} else if s := strings.TrimPrefix(string(v), "-"); s != "" && strings.Trim(s, "0123456789") == "" {
+			// integer beyond the range of int64
+			return string(v), true
+		} else {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	abs := uint64(n)
+	if n < 0 {
+		abs = uint64(-(n + 1)) + 1
+	}
+	if abs > max {
+		return strconv.FormatInt(n, 10), true
+	}
+	return "", false
+}
+
+func largeUint(n, max uint64) (string, bool) {
+	if n > max {
+		return strconv.FormatUint(n, 10), true
+	}
+	return "", false
+}
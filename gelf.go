@@ -1,10 +1,22 @@
 package logger
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
-	"os"
+	"math/big"
+	"net/http"
+	"net/url"
+	"reflect"
+	"runtime"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/juju/errors"
 	"github.com/rs/zerolog"
@@ -28,20 +40,67 @@ func ltog(level Level) zerolog.Level {
 	panic(fmt.Sprintf("Can't map level %d to zerolog level", level))
 }
 
-func newGelfLog(w io.Writer, lvl Level) Logger {
+func newGelfLog(w io.Writer, lvl Level, c *config) Logger {
 	l := zerolog.New(w).Level(ltog(lvl))
-	return &gLog{&l, lvl}
+	return &gLog{writer: &l, level: lvl, cfg: c, out: w}
 }
 
 type gLog struct {
 	writer *zerolog.Logger
 	level  Level
+	cfg    *config
+	// out is the writer passed to New, it is synced by Sync
+	out io.Writer
+	// keys is the number of fields in the context of writer, see gEntry.fields
+	keys int
 }
 
+var _ Logger = (*gLog)(nil)
+
 // WithField returns a new Logger that always logs the specified field
 func (g *gLog) WithField(key, value string) Logger {
 	writer := g.writer.With().Str("_"+key, value).Logger()
-	return &gLog{writer: &writer, level: g.level}
+	return &gLog{writer: &writer, level: g.level, cfg: g.cfg, out: g.out, keys: g.keys + 1}
+}
+
+// WithAny returns a new Logger that always logs the specified field. Unlike WithField, the value can be of any type
+func (g *gLog) WithAny(key string, value interface{}) Logger {
+	writer := g.writer.With().Interface("_"+key, value).Logger()
+	return &gLog{writer: &writer, level: g.level, cfg: g.cfg, out: g.out, keys: g.keys + 1}
+}
+
+// WithMessagePrefix returns a new Logger that prefixes the message of each entry with prefix and ": ", e.g. for
+// consumers that grep for a tag instead of parsing fields. Empty messages are prefixed, too.
+func (g *gLog) WithMessagePrefix(prefix string) Logger {
+	return newPrefix(g, prefix)
+}
+
+// WithLevelRemap returns a new Logger that changes the level of its entries according to remap on Flush, e.g.
+// {ErrorLevel: WarnLevel} to keep the errors of a noisy dependency from triggering alerts. The level check of the
+// logger applies to the level before remapping: an entry is only written if its original level is enabled, and
+// then it's written at the new level. An entry remapped to a level below the level of the logger is dropped by the
+// logger, though. Entries at fatal and panic level are never remapped, and no level is remapped to them.
+func (g *gLog) WithLevelRemap(remap map[Level]Level) Logger {
+	return newRemap(g, remap)
+}
+
+// WithLabels returns a new Logger that adds labels to each entry like AddLabels. Labels of nested calls and of
+// AddLabels are merged into one object, later labels take precedence.
+func (g *gLog) WithLabels(labels map[string]string) Logger {
+	return newLabels(g, labels)
+}
+
+// WithEnvironment returns a new Logger that always logs the deployment environment env like "prod" under the key
+// "env", e.g. the result of DetectEnvironment. An empty env is logged as "unknown".
+func (g *gLog) WithEnvironment(env string) Logger {
+	return g.WithField("env", environment(env))
+}
+
+// WithCorrelation returns a new Logger that logs the correlation id under the key "correlation_id", e.g. the result
+// of ExtractCorrelation for an incoming request. InjectCorrelation sets the id in the headers of outgoing requests.
+// An empty id returns the logger unchanged.
+func (g *gLog) WithCorrelation(id string) Logger {
+	return newCorrelation(g, id)
 }
 
 // Level creates a new Entry with the specified Level
@@ -66,99 +125,264 @@ func (g *gLog) Level(lvl Level) Entry {
 
 // Debug creates a new Entry with level Debug
 func (g *gLog) Debug() Entry {
-	l := g.writer.With().Int("level", int(DebugLevel)).Logger()
-	var e = l.Log()
-	if g.level < DebugLevel {
-		e = nil
+	if !g.cfg.sample(DebugLevel) {
+		return nopEntry{}
 	}
-	return &gEntry{e, DebugLevel}
+	return g.cfg.sampled(g.entry(DebugLevel), DebugLevel)
 }
 
 // Info creates a new Entry with level Info
 func (g *gLog) Info() Entry {
-	l := g.writer.With().Int("level", int(InfoLevel)).Logger()
-	var e = l.Log()
-	if g.level < InfoLevel {
-		e = nil
+	if !g.cfg.sample(InfoLevel) {
+		return nopEntry{}
 	}
-	return &gEntry{e, InfoLevel}
+	return g.cfg.sampled(g.entry(InfoLevel), InfoLevel)
 }
 
 // Warn creates a new Entry with level Warn
 func (g *gLog) Warn() Entry {
-	l := g.writer.With().Int("level", int(WarnLevel)).Logger()
-	var e = l.Log()
-	if g.level < WarnLevel {
-		e = nil
+	if !g.cfg.sample(WarnLevel) {
+		return nopEntry{}
 	}
-	return &gEntry{e, WarnLevel}
+	return g.cfg.sampled(g.entry(WarnLevel), WarnLevel)
 }
 
 // Error creates a new Entry with level Error
 func (g *gLog) Error() Entry {
-	l := g.writer.With().Int("level", int(ErrorLevel)).Logger()
-	var e = l.Log()
-	if g.level < ErrorLevel {
-		e = nil
+	if !g.cfg.sample(ErrorLevel) {
+		return nopEntry{}
 	}
-	return &gEntry{e, ErrorLevel}
+	return g.cfg.sampled(g.entry(ErrorLevel), ErrorLevel)
 }
 
-// Fatal creates a new Entry with level Fatal. Executing a log at fatal level exits the application with exit code 1.
+// Fatal creates a new Entry with level Fatal. Executing a log at fatal level exits the application with exit code 1,
+// or the code set with FatalExitCode.
 func (g *gLog) Fatal() Entry {
-	l := g.writer.With().Int("level", int(FatalLevel)).Logger()
-	var e = l.Log()
-	if g.level < FatalLevel {
-		e = nil
-	}
-	return &gEntry{e, FatalLevel}
+	return g.entry(FatalLevel)
 }
 
 // Panic creates a new Entry with level Panic. Executing a log at panic level will call panic().
 func (g *gLog) Panic() Entry {
-	l := g.writer.With().Int("level", int(PanicLevel)).Logger()
-	var e = l.Log()
-	if g.level < PanicLevel {
-		e = nil
-	}
-	return &gEntry{e, PanicLevel}
+	return g.entry(PanicLevel)
+}
+
+// entry creates a new Entry with level lvl
+func (g *gLog) entry(lvl Level) *gEntry {
+	e := &gEntry{writer: g.writer, keys: g.keys, lvl: lvl, max: g.level, cfg: g.cfg, retention: g.cfg.retention,
+		created: timeNow(g.cfg)}
+	e.open()
+	return e
 }
 
+// PipeWriter returns a writer that logs every line written to it as an entry at the specified level. The entries
+// have the field "stream" set to stream. The last line is logged on Close even if it doesn't end with a newline.
+func (g *gLog) PipeWriter(lvl Level, stream string) io.WriteCloser {
+	return newLineWriter(g, lvl, stream)
+}
+
+// Operation logs the start of an operation at debug level and returns a function that logs its end together with
+// the duration of the operation. The end is logged at info level, or at error level if a non-nil error is passed.
+// Use it as "defer done(err)". Both entries have the same id under the key "operation_id", see WithIDGenerator.
+func (g *gLog) Operation(name string) func(err error) {
+	return operation(g, name)
+}
+
+// NewTimer returns a Timer that starts now, e.g. "t := l.NewTimer(); parse(); t.Mark("parse")". Add the phases to an
+// entry with Timer.AddTo.
+func (g *gLog) NewTimer() *Timer {
+	return newTimer(g.cfg)
+}
+
+// StartHeartbeat logs msg at info level every interval with the time since StartHeartbeat was called under the key
+// "uptime" and the number of goroutines under the key "goroutines", e.g. to show that a service is alive. It returns
+// a function that stops the heartbeat and waits until an entry that is being written is done, calling it more than
+// once is safe. Each call starts an independent heartbeat. If interval isn't greater than zero, nothing is logged
+// and stop does nothing.
+func (g *gLog) StartHeartbeat(interval time.Duration, msg string) (stop func()) {
+	return heartbeat(g, interval, msg)
+}
+
+// Go runs fn in a new goroutine that recovers from a panic of fn and logs it at error level with the panic value
+// under the key "panic" and the stack under the key "stack", e.g. for background workers, whose panics would crash the
+// process without a log. By default, the panic is swallowed and the goroutine ends. With the option RepanicInGo, the
+// goroutine panics again after logging, which crashes the process.
+func (g *gLog) Go(fn func()) {
+	goSafe(g, fn)
+}
+
+// Sync flushes buffered entries to the destination without closing the logger. For loggers created by New with the
+// zerolog or gelf backend, the writer is synced if it has a method "Sync() error", like *os.File. Sync does nothing
+// for the logrus backend, for channel loggers and for loggers passed to FromZerolog.
+func (g *gLog) Sync() error {
+	return syncWriter(g.out)
+}
+
+// config returns the options of the logger
+func (g *gLog) config() *config {
+	return g.cfg
+}
+
+// gEntry writes its fields directly to a zerolog event. The GELF level is a field that is added on Flush, so the level
+// can change until then without moving the fields.
 type gEntry struct {
-	entry *zerolog.Event
-	lvl   Level
+	ev *zerolog.Event
+	// parked is set if ev is detached from the logger, see Bytes
+	parked bool
+	writer *zerolog.Logger
+	// keys is the number of fields in the context of writer
+	keys int
+	lvl  Level
+	// max is the level of the logger that created the entry
+	max Level
+	cfg *config
+	// noTime skips the timestamp
+	noTime bool
+	// retention is added under the key "_retention" on Flush, see Retention
+	retention string
+	// created is the time the entry was created, see WithTimeCapture
+	created time.Time
+}
+
+var _ Entry = (*gEntry)(nil)
+
+// open creates the event of the entry, or a detached event if the logger doesn't write any events
+func (g *gEntry) open() {
+	g.ev = g.writer.Log()
+	g.parked = g.ev == nil
+	if g.parked {
+		g.ev = zerolog.Dict()
+	}
+}
+
+// settle returns the event of the entry with all fields, or nil if the logger doesn't write it. The fields are only
+// moved if they were added to a detached event.
+func (g *gEntry) settle() *zerolog.Event {
+	if !g.parked {
+		return g.ev
+	}
+	e := g.writer.Log()
+	if e == nil {
+		g.release()
+		return nil
+	}
+	addRawFields(e, g.fields())
+	g.ev, g.parked = e, false
+	return e
+}
+
+// fields removes the fields of the entry from its event and returns them. The event can't be used afterwards.
+func (g *gEntry) fields() []rawField {
+	fs := rawFields(g.ev)
+	if g.parked {
+		return fs
+	}
+	// an event of the logger starts with the context of the logger
+	if g.keys > len(fs) {
+		return nil
+	}
+	return fs[g.keys:]
+}
+
+// release returns a detached event to the pool of zerolog. Events of the logger are left to the garbage collector,
+// because writing them would run the hooks of the logger.
+func (g *gEntry) release() {
+	if g.parked {
+		g.ev.Msg("")
+	}
+	g.ev = nil
 }
 
 // Flush writes the entry as a single log statement. Optionally, a message can be added which will
 // be included in the final log entry
 func (g *gEntry) Flush(msg string) {
-	g.entry.Int64("timestamp", time.Now().Unix())
-	g.entry.Str("version", "1.1")
-	g.entry.Str("short_message", msg)
-	// This skips a message in zerolog
-	g.entry.Msg("")
+	if g.cfg.reportCaller {
+		g.ev.Str("_caller", caller(g.cfg.callerSkip))
+	}
+	if g.cfg.goroutineID {
+		g.ev.Uint64("_goroutine", goroutineID())
+	}
+	var b []byte
+	if g.lvl == PanicLevel && g.cfg.onPanic != nil {
+		b, _ = g.Bytes()
+	}
+	if g.lvl > g.max {
+		g.release()
+	} else if e := g.settle(); e != nil {
+		g.write(e, msg)
+	}
 	if g.lvl == PanicLevel {
+		g.cfg.panicked(func() map[string]interface{} {
+			return jsonFields(b, "_")
+		})
 		panic("logger called at panic level with message: " + msg)
 	} else if g.lvl == FatalLevel {
-		os.Exit(1)
+		exitFunc(g.cfg.exitCode)
+	}
+}
+
+// write adds the GELF fields to e and writes it with the message msg
+func (g *gEntry) write(e *zerolog.Event, msg string) {
+	if g.retention != "" {
+		e.Str("_retention", g.retention)
 	}
+	e.Int("level", int(g.lvl))
+	if !g.noTime {
+		e.Int64("timestamp", g.cfg.stamp(g.created).Unix())
+	}
+	e.Str("version", "1.1")
+	e.Str("short_message", msg)
+	// This skips a message in zerolog
+	e.Msg("")
+}
+
+// Bytes returns the entry as it would be written by Flush without a message, but doesn't write it. The level of the
+// logger isn't applied. The entry can still be flushed afterwards.
+func (g *gEntry) Bytes() ([]byte, error) {
+	fs := g.fields()
+	// keep the fields in a detached event, Flush moves them to an event of the logger again
+	g.ev, g.parked = zerolog.Dict(), true
+	addRawFields(g.ev, fs)
+	var buf bytes.Buffer
+	l := g.writer.Output(&buf)
+	e := l.Log()
+	addRawFields(e, fs)
+	g.write(e, "")
+	return buf.Bytes(), nil
+}
+
+// Discard abandons the entry without writing it. The entry must not be used afterwards.
+func (g *gEntry) Discard() {
+	g.release()
+}
+
+// At changes the level of the entry. The level is evaluated when the entry is flushed.
+func (g *gEntry) At(lvl Level) Entry {
+	g.lvl = validLevel(lvl)
+	return g
+}
+
+// NoTime removes the time field that is added to each entry automatically, e.g. to add the time of an event instead.
+func (g *gEntry) NoTime() Entry {
+	g.noTime = true
+	return g
 }
 
 // AddFields adds a range of fields to the log statement
 func (g *gEntry) AddFields(fs map[string]interface{}) Entry {
+	fs = g.cfg.fields(fs)
 	for k, v := range fs {
-		g.entry = g.entry.Interface("_"+k, v)
+		g.ev.Interface("_"+k, v)
 	}
 	return g
 }
 
 // AddErr adds an error to the log statement. The error will have the key "err". An error stack will be included
-// under the key "err_stack"
+// under the key "err_stack". Use the option ErrorKeys to change the keys.
 func (g *gEntry) AddErr(err error) Entry {
 	msg := err.Error()
 	st := errors.ErrorStack(err)
-	g.entry = g.entry.Str("_err", msg)
-	g.entry = g.entry.Str("_err_stack", st)
+	g.ev.Str("_"+g.cfg.errKey, msg)
+	g.ev.Str("_"+g.cfg.errStackKey, st)
 	return g
 }
 
@@ -166,44 +390,473 @@ func (g *gEntry) AddErr(err error) Entry {
 func (g *gEntry) AddError(key string, val error) Entry {
 	msg := val.Error()
 	st := errors.ErrorStack(val)
-	g.entry = g.entry.Str("_"+key, msg)
-	g.entry = g.entry.Str("_"+key+"_stack", st)
-	g.entry = g.entry.AnErr("_"+key, val)
+	g.ev.Str("_"+key, msg)
+	g.ev.Str("_"+key+"_stack", st)
+	g.ev.AnErr("_"+key, val)
 	return g
 }
 
 // AddBool adds a bool value to the log statement.
 func (g *gEntry) AddBool(key string, val bool) Entry {
-	g.entry = g.entry.Bool("_"+key, val)
+	if g.cfg.omitEmpty && !val {
+		return g
+	}
+	g.ev.Bool("_"+key, val)
 	return g
 }
 
 // AddInt adds an integer value to the log statement.
 func (g *gEntry) AddInt(key string, val int) Entry {
-	g.entry = g.entry.Int("_"+key, val)
+	if g.cfg.omitEmpty && val == 0 {
+		return g
+	}
+	if g.cfg.maxInt != 0 {
+		if s, ok := largeInt(val, g.cfg.maxInt); ok {
+			g.ev.Str("_"+key, s)
+			return g
+		}
+	}
+	g.ev.Int("_"+key, val)
 	return g
 }
 
 // AddStr adds a string value to the log statement.
 func (g *gEntry) AddStr(key string, val string) Entry {
-	g.entry = g.entry.Str("_"+key, val)
+	if g.cfg.omitEmpty && val == "" {
+		return g
+	}
+	g.ev.Str("_"+key, val)
 	return g
 }
 
 // AddTime adds a time value to the log statement.
 func (g *gEntry) AddTime(key string, val time.Time) Entry {
-	g.entry = g.entry.Time("_"+key, val)
+	if g.cfg.omitEmpty && val.IsZero() {
+		return g
+	}
+	g.ev.Time("_"+key, val)
 	return g
 }
 
 // AddDur adds a duration value to the log statement.
 func (g *gEntry) AddDur(key string, val time.Duration) Entry {
-	g.entry = g.entry.Dur("_"+key, val)
+	if g.cfg.omitEmpty && val == 0 {
+		return g
+	}
+	g.ev.Dur("_"+key, val)
 	return g
 }
 
 // AddAny adds any value to the log statement.
 func (g *gEntry) AddAny(key string, val interface{}) Entry {
-	g.entry = g.entry.Interface("_"+key, val)
+	g.ev.Interface("_"+key, g.cfg.quote(val))
+	return g
+}
+
+// AddJSONRaw adds pre-serialized JSON to the log statement without encoding it again. If raw is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (g *gEntry) AddJSONRaw(key string, raw []byte) Entry {
+	if !json.Valid(raw) {
+		g.ev.Str("_"+key+"_invalid", string(raw))
+		return g
+	}
+	g.ev.RawJSON("_"+key, raw)
 	return g
 }
+
+// AddCount adds a count to the log statement. The number is stored under key, a human readable form like "3 items"
+// is stored under the key "${key}_human"
+func (g *gEntry) AddCount(key string, n int, singular, plural string) Entry {
+	g.ev.Int("_"+key, n)
+	g.ev.Str("_"+key+"_human", pluralize(n, singular, plural))
+	return g
+}
+
+// AddQuery adds a database query to the log statement. The statement, its arguments as JSON array, the number of
+// rows and the duration are stored under the keys "db.sql", "db.args", "db.rows" and "db.duration"
+func (g *gEntry) AddQuery(sql string, args []interface{}, rows int, dur time.Duration) Entry {
+	g.ev.Str("_db.sql", sql)
+	g.ev.Interface("_db.args", args)
+	g.ev.Int("_db.rows", rows)
+	g.ev.Dur("_db.duration", dur)
+	return g
+}
+
+// AddTraceparent adds the trace id and span id of the W3C traceparent header in headers under the keys
+// "${key}_trace_id" and "${key}_span_id". A missing or malformed header adds nothing.
+func (g *gEntry) AddTraceparent(key string, headers http.Header) Entry {
+	if traceID, spanID, ok := traceparent(headers); ok {
+		g.ev.Str("_"+key+"_trace_id", traceID)
+		g.ev.Str("_"+key+"_span_id", spanID)
+	}
+	return g
+}
+
+// AddMetric adds a metric to the log statement. Name, value and tags are stored as a nested object under the key
+// "metric", so that log collectors can route the statement to a metrics sink.
+func (g *gEntry) AddMetric(name string, value float64, tags map[string]string) Entry {
+	g.ev.Interface("_metric", metric{name, value, tags})
+	return g
+}
+
+// AddHex adds a byte slice to the log statement, encoded as hex string.
+func (g *gEntry) AddHex(key string, val []byte) Entry {
+	g.ev.Str("_"+key, hex.EncodeToString(val))
+	return g
+}
+
+// AddBase64 adds a byte slice to the log statement, encoded as base64 string with standard encoding.
+func (g *gEntry) AddBase64(key string, val []byte) Entry {
+	g.ev.Str("_"+key, base64.StdEncoding.EncodeToString(val))
+	return g
+}
+
+// AddElapsed adds the duration since the specified time to the log statement.
+func (g *gEntry) AddElapsed(key string, since time.Time) Entry {
+	return g.AddDur(key, timeNow(g.cfg).Sub(since))
+}
+
+// AddDurHuman adds a duration to the log statement as human readable string like "1.2s" or "3m04s". Use AddDur for
+// values that should be queryable.
+func (g *gEntry) AddDurHuman(key string, d time.Duration) Entry {
+	return g.AddStr(key, humanDur(d))
+}
+
+// AddErrN adds an error to the log statement like AddErr, but the error stack under the key "err_stack" is
+// truncated to the first maxFrames frames. The error message is kept intact.
+func (g *gEntry) AddErrN(err error, maxFrames int) Entry {
+	g.ev.Str("_"+g.cfg.errKey, err.Error())
+	g.ev.Str("_"+g.cfg.errStackKey, truncateStack(errors.ErrorStack(err), maxFrames))
+	return g
+}
+
+// AddSensitive adds the hex encoded SHA-256 hash of val under the key "${key}_hash" to the log statement. The raw
+// value is never logged. Use the options SensitiveSalt and SensitiveHashLen to configure the hash.
+func (g *gEntry) AddSensitive(key string, val string) Entry {
+	return g.AddStr(key+"_hash", g.cfg.hash(val))
+}
+
+// AddBytesLen adds the length of a byte slice to the log statement instead of its content. A nil slice has length 0.
+func (g *gEntry) AddBytesLen(key string, val []byte) Entry {
+	g.ev.Int("_"+key, len(val))
+	return g
+}
+
+// AddStrLen adds the number of runes of a string to the log statement instead of the string itself.
+func (g *gEntry) AddStrLen(key string, val string) Entry {
+	g.ev.Int("_"+key, utf8.RuneCountInString(val))
+	return g
+}
+
+// AddJSONString adds a JSON encoded string to the log statement without encoding it again. If s is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (g *gEntry) AddJSONString(key, s string) Entry {
+	return g.AddJSONRaw(key, []byte(s))
+}
+
+// AddRetry adds the state of a retry loop to the log statement. The attempt, the maximum number of attempts and the
+// backoff before the next attempt are stored under the keys "retry.attempt", "retry.max" and "retry.backoff"
+func (g *gEntry) AddRetry(attempt, max int, backoff time.Duration) Entry {
+	g.ev.Int("_retry.attempt", attempt)
+	g.ev.Int("_retry.max", max)
+	g.ev.Dur("_retry.backoff", backoff)
+	return g
+}
+
+// AddDeadline adds the time remaining until the deadline of ctx to the log statement. It is negative if the deadline
+// has passed. If ctx has no deadline, nothing is added.
+func (g *gEntry) AddDeadline(key string, ctx context.Context) Entry {
+	if d, ok := ctx.Deadline(); ok {
+		return g.AddDur(key, d.Sub(timeNow(g.cfg)))
+	}
+	return g
+}
+
+// AddRequest adds the method, path, remote address, user agent and host of r to the log statement under the keys
+// "http.method", "http.path", "http.remote_addr", "http.user_agent" and "http.host". Other headers are never logged.
+func (g *gEntry) AddRequest(r *http.Request) Entry {
+	return addRequest(g, r, g.cfg.forwardedFor)
+}
+
+// AddResponse adds the status code, the size of the body and the duration of an HTTP response to the log statement
+// under the keys "http.status", "http.bytes" and "http.duration". The class of the status like "5xx" is stored under
+// the key "http.status_class".
+func (g *gEntry) AddResponse(status, bytes int, dur time.Duration) Entry {
+	return addResponse(g, status, bytes, dur)
+}
+
+// AddSlice adds a slice or an array of any element type to the log statement as JSON array. Other values, or slices
+// that can't be encoded as JSON, are added like with AddAny.
+func (g *gEntry) AddSlice(key string, vals interface{}) Entry {
+	if b, ok := sliceJSON(vals); ok {
+		return g.AddJSONRaw(key, b)
+	}
+	return g.AddAny(key, vals)
+}
+
+// AddBoolPtr adds the value of a bool pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (g *gEntry) AddBoolPtr(key string, val *bool) Entry {
+	if val == nil {
+		if g.cfg.nilNull {
+			return g.AddAny(key, nil)
+		}
+		return g
+	}
+	return g.AddBool(key, *val)
+}
+
+// AddIntPtr adds the value of an integer pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (g *gEntry) AddIntPtr(key string, val *int) Entry {
+	if val == nil {
+		if g.cfg.nilNull {
+			return g.AddAny(key, nil)
+		}
+		return g
+	}
+	return g.AddInt(key, *val)
+}
+
+// AddStrPtr adds the value of a string pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (g *gEntry) AddStrPtr(key string, val *string) Entry {
+	if val == nil {
+		if g.cfg.nilNull {
+			return g.AddAny(key, nil)
+		}
+		return g
+	}
+	return g.AddStr(key, *val)
+}
+
+// AddCaller adds the location that called it as "file:line" under the key "caller" and the calling function under
+// the key "caller_func" to the log statement. Unlike the option ReportCaller, it only applies to this entry.
+func (g *gEntry) AddCaller() Entry {
+	return addCaller(g, 0)
+}
+
+// AddCallerSkip adds the caller like AddCaller, but skips n additional frames, e.g. in helpers that wrap an Entry.
+func (g *gEntry) AddCallerSkip(n int) Entry {
+	return addCaller(g, n)
+}
+
+// AddIntThreshold adds a value to the log statement like AddInt and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (g *gEntry) AddIntThreshold(key string, val, threshold int, flagKey string) Entry {
+	g.AddInt(key, val)
+	return thresholdFlag(g, flagKey, val > threshold, g.cfg.flagBelow)
+}
+
+// AddDurThreshold adds a value to the log statement like AddDur and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (g *gEntry) AddDurThreshold(key string, val, threshold time.Duration, flagKey string) Entry {
+	g.AddDur(key, val)
+	return thresholdFlag(g, flagKey, val > threshold, g.cfg.flagBelow)
+}
+
+// AddMoney adds an amount of money in minor units of an ISO 4217 currency, e.g. cents for "USD", to the log statement.
+// The amount and the currency are stored under the keys "${key}.amount_minor" and "${key}.currency", the formatted
+// amount like "$12.99" under the key "${key}.display". Unknown currencies, including an empty code, are formatted
+// with two decimal places and flagged with true under the key "${key}.currency_unknown".
+func (g *gEntry) AddMoney(key string, minorUnits int64, currency string) Entry {
+	currency = strings.ToUpper(currency)
+	g.ev.Int64("_"+key+".amount_minor", minorUnits)
+	g.ev.Str("_"+key+".currency", currency)
+	display, known := formatMoney(minorUnits, currency)
+	g.ev.Str("_"+key+".display", display)
+	if !known {
+		g.ev.Bool("_"+key+".currency_unknown", true)
+	}
+	return g
+}
+
+// AddErrChain adds the message of an error under key, the number of errors it wraps under the key "${key}_depth" and
+// the type names of the errors in its chain, starting with err, under the key "${key}_types" to the log statement.
+// Both the standard library's wrapping and juju/errors are followed. A nil error adds nothing.
+func (g *gEntry) AddErrChain(key string, err error) Entry {
+	if err == nil {
+		return g
+	}
+	depth, types := errChain(err)
+	g.ev.Str("_"+key, err.Error())
+	g.ev.Int("_"+key+"_depth", depth)
+	g.ev.Strs("_"+key+"_types", types)
+	return g
+}
+
+// AddInterval adds a time window to the log statement. Start and end are stored in UTC under the keys "${key}.start"
+// and "${key}.end", the duration between them under the key "${key}.duration". If end is before start, the duration
+// is negative.
+func (g *gEntry) AddInterval(key string, start, end time.Time) Entry {
+	g.ev.Time("_"+key+".start", start.UTC())
+	g.ev.Time("_"+key+".end", end.UTC())
+	g.ev.Dur("_"+key+".duration", end.Sub(start))
+	return g
+}
+
+// AddValidationErrors adds the results of a failed validation to the log statement as nested object that maps each
+// invalid field to its error message, e.g. {"email": "must not be empty"}. A nil or empty map adds nothing.
+func (g *gEntry) AddValidationErrors(key string, errs map[string]string) Entry {
+	if len(errs) == 0 {
+		return g
+	}
+	g.ev.Interface("_"+key, errs)
+	return g
+}
+
+// AddGeo adds coordinates to the log statement as nested object with the keys "lat" and "lon", which Elasticsearch
+// maps as geo_point. If the latitude isn't within [-90, 90] or the longitude isn't within [-180, 180], the
+// coordinates are still added and "${key}_invalid" is set to true.
+func (g *gEntry) AddGeo(key string, lat, lon float64) Entry {
+	p := geoPoint{lat, lon}
+	g.ev.Interface("_"+key, p)
+	if !p.valid() {
+		g.ev.Bool("_"+key+"_invalid", true)
+	}
+	return g
+}
+
+// Retention sets the retention class of the entry under the key "retention", e.g. "short", "long" or "audit", so
+// that the log pipeline can route it to a storage tier. It overrides the class set with DefaultRetention.
+func (g *gEntry) Retention(class string) Entry {
+	g.retention = class
+	return g
+}
+
+// AddDiff adds the old and the new value of a change to the log statement as nested object with the keys "old" and
+// "new". If both values are equal according to reflect.DeepEqual, nothing is added.
+func (g *gEntry) AddDiff(key string, oldVal, newVal interface{}) Entry {
+	if reflect.DeepEqual(oldVal, newVal) {
+		return g
+	}
+	g.ev.Interface("_"+key, diff{oldVal, newVal})
+	return g
+}
+
+// AddRuntime adds diagnostics of the Go runtime to the log statement: the Go version, the operating system and the
+// architecture under the keys "go.version", "go.os" and "go.arch", the number of goroutines under the key
+// "go.goroutines" and the allocated heap in MiB under the key "go.heap_mb". Reading the heap stops the world, so use
+// it for rare entries like crash reports, not in hot paths.
+func (g *gEntry) AddRuntime() Entry {
+	g.ev.Str("_go.version", runtime.Version())
+	g.ev.Str("_go.os", runtime.GOOS)
+	g.ev.Str("_go.arch", runtime.GOARCH)
+	g.ev.Int("_go.goroutines", runtime.NumGoroutine())
+	g.ev.Float64("_go.heap_mb", heapMB())
+	return g
+}
+
+// AddForm adds the values of the listed form fields to the log statement under the keys "form.${name}". Multiple
+// values of a field are joined with commas. Fields that aren't listed are never added, e.g. passwords, and listed
+// fields missing from values are skipped.
+func (g *gEntry) AddForm(values url.Values, keys ...string) Entry {
+	for _, k := range keys {
+		if vs, ok := values[k]; ok {
+			g.AddStr("form."+k, strings.Join(vs, ","))
+		}
+	}
+	return g
+}
+
+// AddBackoff computes the wait before the next attempt of a retry loop and adds it to the log statement together with
+// the attempt under the keys "backoff.wait" and "backoff.attempt". The wait doubles with each attempt, starting at
+// base for attempt 1, and is capped at max. Half of it is random jitter, so that clients don't retry in lockstep.
+// The wait is returned so that the caller can sleep on it.
+func (g *gEntry) AddBackoff(attempt int, base, max time.Duration) (Entry, time.Duration) {
+	d := backoff(attempt, base, max)
+	g.ev.Int("_backoff.attempt", attempt)
+	g.ev.Dur("_backoff.wait", d)
+	return g, d
+}
+
+// AddLabels adds a set of labels like environment, team or service to the log statement as nested object under the
+// key "labels". Label names that don't match the Prometheus label name syntax [a-zA-Z_][a-zA-Z0-9_]* are still added
+// and listed under the key "labels_invalid". Labels of a logger created by WithLabels are merged with labels, with
+// labels taking precedence. A nil or empty map adds nothing.
+func (g *gEntry) AddLabels(labels map[string]string) Entry {
+	return addLabels(g, labels)
+}
+
+// AddPercent adds a fraction like a success ratio to the log statement under key and the same value as percentage
+// string with one decimal under "${key}_pct", e.g. 0.873 as "87.3%", so that dashboards don't mix fractions and
+// percentages. A fraction outside [0, 1] is clamped and "${key}_invalid" is set to true, NaN is clamped to 0.
+func (g *gEntry) AddPercent(key string, fraction float64) Entry {
+	f, pct, ok := percent(fraction)
+	g.ev.Float64("_"+key, f).Str("_"+key+"_pct", pct)
+	if !ok {
+		g.ev.Bool("_"+key+"_invalid", true)
+	}
+	return g
+}
+
+// AddNullString adds the value of a sql.NullString to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (g *gEntry) AddNullString(key string, val sql.NullString) Entry {
+	if !val.Valid {
+		if g.cfg.nilNull {
+			return g.AddAny(key, nil)
+		}
+		return g
+	}
+	return g.AddStr(key, val.String)
+}
+
+// AddNullInt64 adds the value of a sql.NullInt64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (g *gEntry) AddNullInt64(key string, val sql.NullInt64) Entry {
+	if !val.Valid {
+		if g.cfg.nilNull {
+			return g.AddAny(key, nil)
+		}
+		return g
+	}
+	return g.AddAny(key, val.Int64)
+}
+
+// AddNullFloat64 adds the value of a sql.NullFloat64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (g *gEntry) AddNullFloat64(key string, val sql.NullFloat64) Entry {
+	if !val.Valid {
+		if g.cfg.nilNull {
+			return g.AddAny(key, nil)
+		}
+		return g
+	}
+	return g.AddAny(key, val.Float64)
+}
+
+// AddNullBool adds the value of a sql.NullBool to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (g *gEntry) AddNullBool(key string, val sql.NullBool) Entry {
+	if !val.Valid {
+		if g.cfg.nilNull {
+			return g.AddAny(key, nil)
+		}
+		return g
+	}
+	return g.AddBool(key, val.Bool)
+}
+
+// AddKV adds alternating keys and values to the log statement, e.g. AddKV("user", id, "ok", true), like AddAny for
+// each pair. It trades the type safety of the typed methods for brevity. Keys that aren't strings are formatted with
+// fmt.Sprint. If the number of arguments is odd, the last key is added under "kv_warning" as missing its value.
+func (g *gEntry) AddKV(pairs ...interface{}) Entry {
+	return addKV(g, pairs)
+}
+
+// AddBigRat adds an arbitrary-precision rational number to the log statement as string, so that exact values like
+// amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default. nil
+// is added as "<nil>".
+func (g *gEntry) AddBigRat(key string, val *big.Rat) Entry {
+	return g.AddStr(key, formatRat(val, g.cfg.bigPrec))
+}
+
+// AddBigFloat adds an arbitrary-precision floating-point number to the log statement as string, so that exact values
+// like amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default.
+// nil is added as "<nil>".
+func (g *gEntry) AddBigFloat(key string, val *big.Float) Entry {
+	return g.AddStr(key, formatBigFloat(val, g.cfg.bigPrec))
+}
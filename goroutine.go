@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// goSafe runs fn in a new goroutine. A panic of fn is logged by l at error level with the panic value under the key
+// "panic" and the stack of the goroutine under the key "stack". Afterwards, the goroutine ends, or panics again with
+// the same value with the option RepanicInGo.
+func goSafe(l Logger, fn func()) {
+	c := configOf(l)
+	go func() {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			l.Error().AddStr("panic", fmt.Sprint(r)).AddStr("stack", string(debug.Stack())).Flush("goroutine panicked")
+			if c != nil && c.repanicGo {
+				panic(r)
+			}
+		}()
+		fn()
+	}()
+}
@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_Go(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 1)
+	l.WithField("worker", "sync").Go(func() { panic("boom") })
+	var e CapturedEntry
+	select {
+	case e = <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("The panic should be logged")
+	}
+	assert.Equal(t, Level(ErrorLevel), e.Level, "The panic should be logged at error level")
+	assert.Equal(t, "goroutine panicked", e.Message)
+	assert.Equal(t, "boom", e.Fields["panic"], "Entry should contain the panic value")
+	assert.Contains(t, e.Fields["stack"], "goroutine_test.go", "Entry should contain the stack of the panic")
+	assert.Equal(t, "sync", e.Fields["worker"], "Entry should have the fields of the logger")
+
+	done := make(chan struct{})
+	l.Go(func() { close(done) })
+	<-done
+	assert.Empty(t, ch, "Nothing should be logged without a panic")
+}
+
+func TestLogger_Go_Repanic(t *testing.T) {
+	if os.Getenv("LOGGER_TEST_REPANIC") == "1" {
+		l, _ := NewChannel(DebugLevel, 1, RepanicInGo())
+		l.Go(func() { panic("boom") })
+		time.Sleep(time.Second)
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestLogger_Go_Repanic$")
+	cmd.Env = append(os.Environ(), "LOGGER_TEST_REPANIC=1")
+	out, err := cmd.CombinedOutput()
+	assert.Error(t, err, "The process should crash with RepanicInGo")
+	assert.Contains(t, string(out), "panic: boom", "The goroutine should panic again with the same value")
+}
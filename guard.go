@@ -0,0 +1,62 @@
+package logger
+
+// uLog wraps a Logger so that its entries panic when they are used after Flush. See GuardReuse.
+type uLog struct {
+	wrapLog
+}
+
+var _ Logger = (*uLog)(nil)
+
+// newGuard returns a Logger whose entries panic when they are used after Flush
+func newGuard(l Logger) Logger {
+	u := &uLog{}
+	u.wrapLog = wrapLog{l, u}
+	return u
+}
+
+// with returns a guarded logger that wraps l
+func (u *uLog) with(l Logger) Logger {
+	return newGuard(l)
+}
+
+// entry returns a new entry that checks each use of e
+func (u *uLog) entry(e Entry, lvl Level) Entry {
+	ue := &uEntry{}
+	ue.wrapEntry = wrapEntry{e: e, lvl: lvl, self: ue, observe: ue.use}
+	return ue
+}
+
+// uEntry panics when it's used after Flush or Discard
+type uEntry struct {
+	wrapEntry
+	flushed bool
+}
+
+var _ Entry = (*uEntry)(nil)
+
+// check panics if the entry has already been flushed
+func (u *uEntry) check() {
+	if u.flushed {
+		panic("logger: entry used after Flush")
+	}
+}
+
+// use checks the entry before a method is forwarded
+func (u *uEntry) use(method string, args ...interface{}) {
+	u.check()
+}
+
+// Flush writes the entry as a single log statement. Optionally, a message can be added which will
+// be included in the final log entry
+func (u *uEntry) Flush(msg string) {
+	u.check()
+	u.flushed = true
+	u.e.Flush(msg)
+}
+
+// Discard abandons the entry without writing it. The entry must not be used afterwards.
+func (u *uEntry) Discard() {
+	u.check()
+	u.flushed = true
+	u.e.Discard()
+}
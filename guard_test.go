@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuardReuse(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl, GuardReuse()).WithField("somekey", "someval")
+		e := l.Info().AddStr("first", "val")
+		e.Flush("")
+		assert.Panics(t, func() { e.AddStr("second", "val") }, "Adding a field after Flush should panic")
+		assert.Panics(t, func() { e.Flush("") }, "Flushing twice should panic")
+		assert.Equal(t, 1, strings.Count(sb.String(), "first"), "Entry should have been written once")
+		assert.Contains(t, sb.String(), "someval", "Entry should contain the persistent field")
+
+		e = New(&sb, DebugLevel, impl).Info()
+		e.Flush("")
+		assert.NotPanics(t, func() { e.AddStr("second", "val") }, "Reuse should not panic without the guard")
+	}
+}
+
+func TestGuardReuse_Discard(t *testing.T) {
+	var sb strings.Builder
+	e := New(&sb, DebugLevel, ZeroLogBackend, GuardReuse()).Info().AddStr("key", "val")
+	e.Discard()
+	assert.Panics(t, func() { e.Flush("") }, "Flush after Discard should panic")
+	assert.Empty(t, sb.String(), "Discarded entry should not be written")
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+// NewGzip creates a logger that compresses its output with gzip before writing it to w, e.g. to archive logs without
+// compressing rotated files afterwards. It uses the zerolog backend.
+//
+// The returned Closer must be called when the logger is no longer used: it flushes the buffered entries and writes the
+// gzip footer, without which the stream is truncated and can't be decompressed completely. It doesn't close w. Entries
+// flushed after Close are dropped and counted by DroppedCount. Sync flushes the buffered entries without ending the
+// stream, so that the entries written so far can be read, at the expense of the compression ratio. gzip.Writer isn't
+// safe for concurrent use, the writes, Sync and Close are serialized by the logger.
+func NewGzip(w io.Writer, lvl Level, opts ...Option) (Logger, io.Closer) {
+	gw := &lockedWriter{w: &gzipWriter{gz: gzip.NewWriter(w), w: w}}
+	return New(gw, lvl, ZeroLogBackend, opts...), gw
+}
+
+// gzipWriter compresses the output to w. It isn't safe for concurrent use, it must be wrapped in a lockedWriter.
+type gzipWriter struct {
+	gz     *gzip.Writer
+	w      io.Writer
+	closed bool
+}
+
+// Write compresses p. After Close, p is discarded and os.ErrClosed is returned.
+func (w *gzipWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		drop()
+		return 0, os.ErrClosed
+	}
+	return w.gz.Write(p)
+}
+
+// Sync writes the buffered compressed data to the underlying writer and syncs it if it supports it
+func (w *gzipWriter) Sync() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.gz.Flush(); err != nil {
+		return err
+	}
+	return syncWriter(w.w)
+}
+
+// Close writes the buffered compressed data and the gzip footer. It doesn't close the underlying writer. Calling Close
+// more than once has no effect.
+func (w *gzipWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.gz.Close()
+}
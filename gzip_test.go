@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gunzip decompresses b, it returns an error if the stream is truncated
+func gunzip(b []byte) (string, error) {
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	out, err := ioutil.ReadAll(r)
+	return string(out), err
+}
+
+func TestNewGzip(t *testing.T) {
+	var buf bytes.Buffer
+	l, c := NewGzip(&buf, InfoLevel)
+	l.Debug().Flush("hidden")
+	l.Info().AddStr("key", "val").Flush("first")
+	l.Warn().Flush("second")
+	_, err := gunzip(buf.Bytes())
+	assert.Error(t, err, "The stream should be incomplete before Close")
+
+	assert.NoError(t, c.Close())
+	s, err := gunzip(buf.Bytes())
+	assert.NoError(t, err, "The stream should be complete after Close")
+	assert.Equal(t, 2, strings.Count(s, "\n"), "Both entries should be written")
+	assert.Contains(t, s, `"key":"val"`)
+	assert.Contains(t, s, `"message":"first"`)
+	assert.NotContains(t, s, "hidden", "The level should be applied")
+
+	n, d := buf.Len(), DroppedCount()
+	l.Info().Flush("late")
+	assert.NoError(t, c.Close(), "Close should be idempotent")
+	assert.Equal(t, n, buf.Len(), "Entries after Close should be dropped")
+	assert.Equal(t, d+1, DroppedCount(), "Entries after Close should be counted as dropped")
+}
+
+func TestGzipWriter_WriteAfterClose(t *testing.T) {
+	var buf bytes.Buffer
+	w := &gzipWriter{gz: gzip.NewWriter(&buf), w: &buf}
+	assert.NoError(t, w.Close())
+	n, err := w.Write([]byte("late\n"))
+	assert.Equal(t, 0, n)
+	assert.Equal(t, os.ErrClosed, err, "Writes after Close should report the closed stream")
+}
+
+func TestNewGzip_Sync(t *testing.T) {
+	var buf bytes.Buffer
+	l, c := NewGzip(&buf, InfoLevel)
+	defer c.Close()
+	l.Info().Flush("synced")
+	assert.NoError(t, l.Sync())
+	r, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	b := make([]byte, 512)
+	n, _ := r.Read(b)
+	assert.Contains(t, string(b[:n]), "synced", "Sync should flush the entries written so far")
+}
+
+func TestNewGzip_Concurrent(t *testing.T) {
+	var buf bytes.Buffer
+	l, c := NewGzip(&buf, InfoLevel)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Info().AddInt("j", j).Flush("concurrent")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.NoError(t, c.Close())
+	s, err := gunzip(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, 800, strings.Count(s, `"message":"concurrent"}`+"\n"), "Entries should not interleave")
+}
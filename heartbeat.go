@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// heartbeat logs msg at info level every interval until the returned function is called. An interval of zero or less
+// would make time.NewTicker panic, no heartbeat is started then.
+func heartbeat(l Logger, interval time.Duration, msg string) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+	c := configOf(l)
+	start := timeNow(c)
+	t := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-t.C:
+				l.Info().
+					AddDur("uptime", timeNow(c).Sub(start)).
+					AddInt("goroutines", runtime.NumGoroutine()).
+					Flush(msg)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.Stop()
+			close(done)
+			<-stopped
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartHeartbeat(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 100)
+	stop := l.WithField("component", "worker").StartHeartbeat(10*time.Millisecond, "alive")
+	var e CapturedEntry
+	for i := 0; i < 2; i++ {
+		select {
+		case e = <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("Heartbeat should be logged every interval")
+		}
+		assert.Equal(t, "alive", e.Message)
+		assert.Equal(t, Level(InfoLevel), e.Level, "Heartbeat should be logged at info level")
+		assert.Equal(t, "worker", e.Fields["component"], "Heartbeat should have the fields of the logger")
+		assert.Contains(t, e.Fields, "goroutines", "Heartbeat should contain the number of goroutines")
+	}
+	assert.True(t, e.Fields["uptime"].(time.Duration) >= 20*time.Millisecond, "Uptime should be since the start")
+
+	stop()
+	stop()
+	n := len(ch)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, n, len(ch), "No heartbeat should be logged after stop")
+}
+
+func TestStartHeartbeat_Independent(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 100)
+	stop1 := l.StartHeartbeat(10*time.Millisecond, "one")
+	stop2 := l.StartHeartbeat(10*time.Millisecond, "two")
+	stop1()
+	for len(ch) > 0 {
+		<-ch
+	}
+	select {
+	case e := <-ch:
+		assert.Equal(t, "two", e.Message, "Stopping a heartbeat should not stop others")
+	case <-time.After(time.Second):
+		t.Fatal("Heartbeat should still be logged")
+	}
+	stop2()
+}
+
+func TestStartHeartbeat_InvalidInterval(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 100)
+	for _, interval := range []time.Duration{0, -time.Second} {
+		stop := l.StartHeartbeat(interval, "alive")
+		time.Sleep(10 * time.Millisecond)
+		stop()
+		stop()
+	}
+	assert.Equal(t, 0, len(ch), "No heartbeat should be started without a positive interval")
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// lrtol maps a logrus level to a level of this package. Trace is mapped to debug.
+func lrtol(level logrus.Level) Level {
+	switch level {
+	case logrus.PanicLevel:
+		return PanicLevel
+	case logrus.FatalLevel:
+		return FatalLevel
+	case logrus.ErrorLevel:
+		return ErrorLevel
+	case logrus.WarnLevel:
+		return WarnLevel
+	case logrus.InfoLevel:
+		return InfoLevel
+	}
+	return DebugLevel
+}
+
+// queueHook publishes the entries of a logrus logger, see NewQueueHook
+type queueHook struct {
+	publish func(level Level, payload []byte) error
+	levels  []logrus.Level
+}
+
+// NewQueueHook returns a logrus hook that formats each entry with the formatter of the logger and passes it to
+// publish, e.g. to send it to Kafka or NATS without adding the broker client to this package. Only entries at one of
+// levels are published, all entries if no level is passed. Add the hook with the option WithHook or to a logger passed
+// to FromLogrus.
+//
+// publish is called synchronously while logrus holds the lock of the logger, so it should return quickly and apply
+// its own timeout, e.g. by handing the payload to a buffered client. If formatting or publish fails, the entry is
+// counted in DroppedCount and logrus reports the error on stderr. The entry is still written to the output of the
+// logger.
+func NewQueueHook(publish func(level Level, payload []byte) error, levels ...Level) logrus.Hook {
+	h := &queueHook{publish: publish}
+	if len(levels) == 0 {
+		h.levels = logrus.AllLevels
+	}
+	for _, lvl := range levels {
+		h.levels = append(h.levels, ltolr(validLevel(lvl)))
+	}
+	return h
+}
+
+// Levels returns the levels the hook publishes
+func (h *queueHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire formats e and publishes it
+func (h *queueHook) Fire(e *logrus.Entry) error {
+	b, err := e.Logger.Formatter.Format(e)
+	if err == nil {
+		err = h.publish(lrtol(e.Level), b)
+	}
+	if err != nil {
+		drop()
+	}
+	return err
+}
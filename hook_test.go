@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewQueueHook(t *testing.T) {
+	var levels []Level
+	var payloads []string
+	publish := func(lvl Level, payload []byte) error {
+		levels = append(levels, lvl)
+		payloads = append(payloads, string(payload))
+		return nil
+	}
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend, WithFormatter(&logrus.JSONFormatter{}),
+		WithHook(NewQueueHook(publish, WarnLevel, ErrorLevel)))
+	l.Info().Flush("info")
+	l.Warn().AddStr("key", "val").Flush("warn")
+	l.Error().Flush("error")
+	assert.Equal(t, []Level{WarnLevel, ErrorLevel}, levels, "Only entries at the levels of the hook should be published")
+	var m map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(payloads[0]), &m), "Payload should be formatted by the formatter")
+	assert.Equal(t, "warn", m["msg"], "Payload should contain the message")
+	assert.Equal(t, "val", m["key"], "Payload should contain the fields")
+	assert.Equal(t, 3, strings.Count(sb.String(), "\n"), "Entries should still be written")
+}
+
+func TestNewQueueHook_Error(t *testing.T) {
+	h := NewQueueHook(func(Level, []byte) error { return errors.New("broker down") })
+	assert.Equal(t, logrus.AllLevels, h.Levels(), "Hook should publish all levels by default")
+	lr := logrus.New()
+	var sb strings.Builder
+	lr.SetOutput(&sb)
+	lr.AddHook(h)
+	before := DroppedCount()
+	FromLogrus(lr).Info().Flush("message")
+	assert.Equal(t, before+1, DroppedCount(), "Failed publishes should be counted as dropped")
+	assert.Contains(t, sb.String(), "message", "Entry should still be written")
+}
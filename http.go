@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseTraceparent parses a W3C trace context traceparent header value. It returns false if the value is malformed.
+// See https://www.w3.org/TR/trace-context/#traceparent-header
+func parseTraceparent(s string) (traceID, spanID string, ok bool) {
+	s = strings.TrimSpace(s)
+	// version "-" trace-id "-" parent-id "-" trace-flags
+	if len(s) < 55 {
+		return "", "", false
+	}
+	version := s[0:2]
+	if !isLowerHex(version) || version == "ff" {
+		return "", "", false
+	}
+	// Version 00 has exactly four parts, future versions may append further parts
+	if version == "00" && len(s) != 55 || len(s) > 55 && s[55] != '-' {
+		return "", "", false
+	}
+	if s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return "", "", false
+	}
+	traceID, spanID, flags := s[3:35], s[36:52], s[53:55]
+	if !isLowerHex(traceID) || !isLowerHex(spanID) || !isLowerHex(flags) {
+		return "", "", false
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(spanID, "0") == "" {
+		return "", "", false
+	}
+	return traceID, spanID, true
+}
+
+// isLowerHex reports whether s only consists of lowercase hex digits
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// traceparent returns the trace and span id of the traceparent header in h
+func traceparent(h http.Header) (traceID, spanID string, ok bool) {
+	if h == nil {
+		return "", "", false
+	}
+	return parseTraceparent(h.Get("traceparent"))
+}
+
+// remoteAddr returns the address of the client of r. If forwarded is set, the first address of the header
+// X-Forwarded-For is preferred over the address of the connection.
+func remoteAddr(r *http.Request, forwarded bool) string {
+	if forwarded {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			if i := strings.IndexByte(xff, ','); i >= 0 {
+				xff = xff[:i]
+			}
+			return strings.TrimSpace(xff)
+		}
+	}
+	return r.RemoteAddr
+}
+
+// addRequest adds the fields of AddRequest to e. Headers other than User-Agent are never added.
+func addRequest(e Entry, r *http.Request, forwarded bool) Entry {
+	var path string
+	if r.URL != nil {
+		path = r.URL.Path
+	}
+	return e.AddStr("http.method", r.Method).
+		AddStr("http.path", path).
+		AddStr("http.remote_addr", remoteAddr(r, forwarded)).
+		AddStr("http.user_agent", r.UserAgent()).
+		AddStr("http.host", r.Host)
+}
+
+// addResponse adds the fields of AddResponse to e
+func addResponse(e Entry, status, bytes int, dur time.Duration) Entry {
+	return e.AddInt("http.status", status).
+		AddStr("http.status_class", strconv.Itoa(status/100)+"xx").
+		AddInt("http.bytes", bytes).
+		AddDur("http.duration", dur)
+}
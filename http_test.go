@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		val     string
+		traceID string
+		spanID  string
+		ok      bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", "", "", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "", "", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", "", "", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", "", "", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", "", "", false},
+		{"00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "", "", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", "", "", false},
+		{"", "", "", false},
+	}
+	for _, test := range tests {
+		traceID, spanID, ok := parseTraceparent(test.val)
+		assert.Equal(t, test.ok, ok, "Unexpected result parsing "+test.val)
+		assert.Equal(t, test.traceID, traceID, "Unexpected trace id parsing "+test.val)
+		assert.Equal(t, test.spanID, spanID, "Unexpected span id parsing "+test.val)
+	}
+}
@@ -0,0 +1,63 @@
+package logger
+
+// kLog wraps a Logger so that its entries have a set of labels. See Logger.WithLabels.
+type kLog struct {
+	wrapLog
+	// labels must not be modified, loggers and entries share it
+	labels map[string]string
+}
+
+var _ Logger = (*kLog)(nil)
+
+// newLabels returns a Logger that adds labels to the entries of l. The map is copied.
+func newLabels(l Logger, labels map[string]string) Logger {
+	k := &kLog{labels: mergeLabels(nil, labels)}
+	k.wrapLog = wrapLog{l, k}
+	return k
+}
+
+// with returns a logger with the same labels that wraps l
+func (k *kLog) with(l Logger) Logger {
+	n := &kLog{labels: k.labels}
+	n.wrapLog = wrapLog{l, n}
+	return n
+}
+
+// entry returns a new entry that starts with the labels of the logger
+func (k *kLog) entry(e Entry, lvl Level) Entry {
+	ke := &kEntry{labels: k.labels}
+	ke.wrapEntry = wrapEntry{e: e, lvl: lvl, self: ke}
+	return ke
+}
+
+// WithLabels returns a new Logger that adds labels to each entry like AddLabels. Labels of nested calls and of
+// AddLabels are merged into one object, later labels take precedence.
+func (k *kLog) WithLabels(labels map[string]string) Logger {
+	n := &kLog{labels: mergeLabels(k.labels, labels)}
+	n.wrapLog = wrapLog{k.l, n}
+	return n
+}
+
+// kEntry collects the labels of the logger and of AddLabels and adds them on Flush
+type kEntry struct {
+	wrapEntry
+	// labels is shared with the logger until AddLabels is called
+	labels map[string]string
+}
+
+var _ Entry = (*kEntry)(nil)
+
+// Flush writes the entry as a single log statement. Optionally, a message can be added which will
+// be included in the final log entry
+func (k *kEntry) Flush(msg string) {
+	k.e.AddLabels(k.labels).Flush(msg)
+}
+
+// AddLabels adds a set of labels like environment, team or service to the log statement as nested object under the
+// key "labels". Label names that don't match the Prometheus label name syntax [a-zA-Z_][a-zA-Z0-9_]* are still added
+// and listed under the key "labels_invalid". Labels of a logger created by WithLabels are merged with labels, with
+// labels taking precedence. A nil or empty map adds nothing.
+func (k *kEntry) AddLabels(labels map[string]string) Entry {
+	k.labels = mergeLabels(k.labels, labels)
+	return k
+}
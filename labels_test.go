@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLabels(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 10)
+	labels := map[string]string{"env": "prod", "team": "core"}
+	ll := l.WithLabels(labels).WithField("key", "val")
+	labels["env"] = "changed"
+
+	ll.Info().Flush("")
+	e := <-ch
+	assert.Equal(t, map[string]string{"env": "prod", "team": "core"}, e.Fields["labels"],
+		"Labels of the logger should be added and copied")
+	assert.Equal(t, "val", e.Fields["key"], "Fields of the logger should be kept")
+
+	ll.WithLabels(map[string]string{"service": "api"}).Info().AddLabels(map[string]string{"team": "infra"}).Flush("")
+	e = <-ch
+	assert.Equal(t, map[string]string{"env": "prod", "team": "infra", "service": "api"}, e.Fields["labels"],
+		"Labels should be merged into one object")
+
+	ll.Info().Flush("")
+	assert.Equal(t, map[string]string{"env": "prod", "team": "core"}, (<-ch).Fields["labels"],
+		"Labels of entries should not modify the logger")
+
+	l.WithLabels(nil).Info().Flush("")
+	assert.NotContains(t, (<-ch).Fields, "labels", "Empty labels should add nothing")
+}
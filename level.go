@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// ParseLevel returns the level with the name s, one of "debug", "info", "warn", "error", "fatal" and "panic". "warning"
+// is accepted for warn. Case and surrounding whitespace are ignored.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "fatal":
+		return FatalLevel, nil
+	case "panic":
+		return PanicLevel, nil
+	}
+	return 0, errors.NotValidf("level %q", s)
+}
+
+// ParseModuleLevels parses a comma-separated list of module=level pairs like "db=debug,http=warn", e.g. from an
+// environment variable, into a map from module to level. Empty entries are skipped. If an entry has no "=", an empty
+// module or an unknown level, the returned error names the entry. The package has no per-module filtering, the map is
+// meant to choose the level of the logger of each module.
+func ParseModuleLevels(s string) (map[string]Level, error) {
+	levels := make(map[string]Level)
+	for _, token := range strings.Split(s, ",") {
+		if strings.TrimSpace(token) == "" {
+			continue
+		}
+		i := strings.IndexByte(token, '=')
+		if i < 0 {
+			return nil, errors.NotValidf("module level %q without \"=\"", token)
+		}
+		module := strings.TrimSpace(token[:i])
+		if module == "" {
+			return nil, errors.NotValidf("module level %q without module", token)
+		}
+		lvl, err := ParseLevel(token[i+1:])
+		if err != nil {
+			return nil, errors.Annotatef(err, "module level %q", token)
+		}
+		levels[module] = lvl
+	}
+	return levels, nil
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Level
+	}{
+		{"debug", DebugLevel},
+		{"INFO", InfoLevel},
+		{" warn ", WarnLevel},
+		{"warning", WarnLevel},
+		{"error", ErrorLevel},
+		{"fatal", FatalLevel},
+		{"panic", PanicLevel},
+	}
+	for _, tt := range tests {
+		lvl, err := ParseLevel(tt.s)
+		assert.NoError(t, err, tt.s)
+		assert.Equal(t, tt.want, lvl, tt.s)
+	}
+	_, err := ParseLevel("verbose")
+	assert.True(t, errors.IsNotValid(err), "Unknown levels should fail")
+	assert.Contains(t, err.Error(), `"verbose"`, "Error should name the level")
+}
+
+func TestParseModuleLevels(t *testing.T) {
+	levels, err := ParseModuleLevels("db=debug, http = warn,,")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Level{"db": DebugLevel, "http": WarnLevel}, levels)
+
+	levels, err = ParseModuleLevels("")
+	assert.NoError(t, err)
+	assert.Empty(t, levels, "Empty specs should return an empty map")
+
+	for _, s := range []string{"db=debug,http", "db=debug,=info", "db=debug,http=loud"} {
+		_, err = ParseModuleLevels(s)
+		assert.True(t, errors.IsNotValid(errors.Cause(err)), "%s should fail", s)
+	}
+	_, err = ParseModuleLevels("db=debug,http=loud")
+	assert.Contains(t, err.Error(), `"http=loud"`, "Error should name the entry")
+	assert.Contains(t, err.Error(), `"loud"`, "Error should name the level")
+}
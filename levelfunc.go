@@ -0,0 +1,47 @@
+package logger
+
+// dLog wraps a Logger that logs entries at all levels so that the level of its entries is checked with a function on
+// Flush. See WithLevelFunc.
+type dLog struct {
+	wrapLog
+	f func() Level
+}
+
+var _ Logger = (*dLog)(nil)
+
+// newLevelFunc returns a Logger that checks the level of the entries of l with f on Flush
+func newLevelFunc(l Logger, f func() Level) Logger {
+	d := &dLog{f: f}
+	d.wrapLog = wrapLog{l, d}
+	return d
+}
+
+// with returns a logger with the same level function that wraps l
+func (d *dLog) with(l Logger) Logger {
+	return newLevelFunc(l, d.f)
+}
+
+// entry returns a new entry whose level is checked with the function of the logger
+func (d *dLog) entry(e Entry, lvl Level) Entry {
+	de := &dEntry{f: d.f}
+	de.wrapEntry = wrapEntry{e: e, lvl: lvl, self: de}
+	return de
+}
+
+// dEntry checks its level with the function of the logger on Flush
+type dEntry struct {
+	wrapEntry
+	f func() Level
+}
+
+var _ Entry = (*dEntry)(nil)
+
+// Flush writes the entry as a single log statement. Optionally, a message can be added which will
+// be included in the final log entry
+func (d *dEntry) Flush(msg string) {
+	if d.lvl > FatalLevel && d.lvl > validLevel(d.f()) {
+		d.e.Discard()
+		return
+	}
+	d.e.Flush(msg)
+}
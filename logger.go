@@ -1,7 +1,14 @@
 package logger
 
 import (
+	"context"
+	"database/sql"
 	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -9,6 +16,32 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	exitMu sync.RWMutex
+	// exit is called to exit the application after an entry at fatal level has been written
+	exit = os.Exit
+)
+
+// SetExitFunc replaces the function that exits the application after an entry at fatal level has been written, e.g.
+// to test that a program logs and exits correctly. nil restores os.Exit. It is safe for concurrent use and applies to
+// all loggers of this package, but not to calls of logrus' or zerolog's own Fatal methods.
+func SetExitFunc(f func(code int)) {
+	if f == nil {
+		f = os.Exit
+	}
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	exit = f
+}
+
+// exitFunc exits the application with the function set by SetExitFunc
+func exitFunc(code int) {
+	exitMu.RLock()
+	f := exit
+	exitMu.RUnlock()
+	f(code)
+}
+
 type Implementation int
 
 const (
@@ -38,29 +71,66 @@ const (
 	PanicLevel = 1
 )
 
-// FromLogrus creates a logger instance from an existing logrus logger
-func FromLogrus(l logrus.FieldLogger) Logger {
-	return &lLog{writer: l}
+// validLevel returns lvl if it is a defined level and InfoLevel otherwise
+func validLevel(lvl Level) Level {
+	switch lvl {
+	case DebugLevel, InfoLevel, WarnLevel, ErrorLevel, FatalLevel, PanicLevel:
+		return lvl
+	}
+	return InfoLevel
+}
+
+// FromLogrus creates a logger instance from an existing logrus logger. Both *logrus.Logger and *logrus.Entry can be
+// passed, the logger keeps its configured output, level, formatter and hooks.
+func FromLogrus(l logrus.FieldLogger, opts ...Option) Logger {
+	c := newConfig(opts)
+	return c.wrap(&lLog{writer: l, cfg: c}, DebugLevel)
 }
 
 // FromZerolog creates a logger instance from an existing zerolog logger
-func FromZerolog(l *zerolog.Logger) Logger {
-	return &zLog{writer: l}
+func FromZerolog(l *zerolog.Logger, opts ...Option) Logger {
+	c := newConfig(opts)
+	return c.wrap(&zLog{writer: l, cfg: c, keys: contextKeys(l)}, DebugLevel)
 }
 
-// New returns a logger. The logger will write to the writer specified and will use the log backend specified
-func New(w io.Writer, lvl Level, impl Implementation) Logger {
+// New returns a logger. The logger will write to the writer specified and will use the log backend specified.
+// Optional behaviour can be configured with options. Each entry is written with a single call to Write, and calls
+// to Write are serialized, so w doesn't need to be safe for concurrent use.
+func New(w io.Writer, lvl Level, impl Implementation, opts ...Option) Logger {
+	c := newConfig(opts)
+	lvl = c.threshold(lvl)
+	if c.console {
+		w = consoleWriter(w)
+	}
+	if c.audit {
+		w = newAuditWriter(w, c, impl)
+	}
+	if c.nestKey != "" && impl != GelfBackend {
+		w = &nestWriter{w, c.nestKey}
+	}
+	// logrus scrubs the fields of its entries on Flush
+	if len(c.scrub) > 0 && impl != LogrusBackend {
+		w = &scrubWriter{w, c.scrub}
+	}
+	// logrus serializes writes itself
+	if impl != LogrusBackend {
+		w = lockWriter(w)
+	}
 	var l Logger
 	// only one implementation, always go to default case
 	switch impl {
 	case LogrusBackend:
-		l = newLogrus(w, lvl)
+		l = newLogrus(w, lvl, c)
 	case GelfBackend:
-		l = newGelfLog(w, lvl)
+		l = newGelfLog(w, lvl, c)
 	case ZeroLogBackend:
 		fallthrough
 	default:
-		l = newZeroLog(w, lvl)
+		l = newZeroLog(w, lvl, c)
+	}
+	l = c.wrap(l, lvl)
+	if c.selfDiag {
+		diagnose(l, lvl, impl, c)
 	}
 	return l
 }
@@ -70,6 +140,27 @@ func New(w io.Writer, lvl Level, impl Implementation) Logger {
 type Logger interface {
 	// WithField returns a new Logger that always logs the specified field
 	WithField(key, value string) Logger
+	// WithAny returns a new Logger that always logs the specified field. Unlike WithField, the value can be of any type
+	WithAny(key string, value interface{}) Logger
+	// WithMessagePrefix returns a new Logger that prefixes the message of each entry with prefix and ": ", e.g. for
+	// consumers that grep for a tag instead of parsing fields. Empty messages are prefixed, too.
+	WithMessagePrefix(prefix string) Logger
+	// WithLevelRemap returns a new Logger that changes the level of its entries according to remap on Flush, e.g.
+	// {ErrorLevel: WarnLevel} to keep the errors of a noisy dependency from triggering alerts. The level check of the
+	// logger applies to the level before remapping: an entry is only written if its original level is enabled, and
+	// then it's written at the new level. An entry remapped to a level below the level of the logger is dropped by the
+	// logger, though. Entries at fatal and panic level are never remapped, and no level is remapped to them.
+	WithLevelRemap(remap map[Level]Level) Logger
+	// WithLabels returns a new Logger that adds labels to each entry like AddLabels. Labels of nested calls and of
+	// AddLabels are merged into one object, later labels take precedence.
+	WithLabels(labels map[string]string) Logger
+	// WithEnvironment returns a new Logger that always logs the deployment environment env like "prod" under the key
+	// "env", e.g. the result of DetectEnvironment. An empty env is logged as "unknown".
+	WithEnvironment(env string) Logger
+	// WithCorrelation returns a new Logger that logs the correlation id under the key "correlation_id", e.g. the result
+	// of ExtractCorrelation for an incoming request. InjectCorrelation sets the id in the headers of outgoing requests.
+	// An empty id returns the logger unchanged.
+	WithCorrelation(id string) Logger
 	// Level creates a new Entry with the specified Level
 	Level(Level) Entry
 	// Debug creates a new Entry with level Debug
@@ -80,10 +171,36 @@ type Logger interface {
 	Warn() Entry
 	// Error creates a new Entry with level Error
 	Error() Entry
-	// Fatal creates a new Entry with level Fatal. Executing a log at fatal level exits the application with exit code 1.
+	// Fatal creates a new Entry with level Fatal. Executing a log at fatal level exits the application with exit code 1,
+	// or the code set with FatalExitCode.
 	Fatal() Entry
 	// Panic creates a new Entry with level Panic. Executing a log at panic level will call panic().
 	Panic() Entry
+	// PipeWriter returns a writer that logs every line written to it as an entry at the specified level. The entries
+	// have the field "stream" set to stream. The last line is logged on Close even if it doesn't end with a newline.
+	PipeWriter(lvl Level, stream string) io.WriteCloser
+	// Operation logs the start of an operation at debug level and returns a function that logs its end together with
+	// the duration of the operation. The end is logged at info level, or at error level if a non-nil error is passed.
+	// Use it as "defer done(err)". Both entries have the same id under the key "operation_id", see WithIDGenerator.
+	Operation(name string) func(err error)
+	// NewTimer returns a Timer that starts now, e.g. "t := l.NewTimer(); parse(); t.Mark("parse")". Add the phases to an
+	// entry with Timer.AddTo.
+	NewTimer() *Timer
+	// StartHeartbeat logs msg at info level every interval with the time since StartHeartbeat was called under the key
+	// "uptime" and the number of goroutines under the key "goroutines", e.g. to show that a service is alive. It returns
+	// a function that stops the heartbeat and waits until an entry that is being written is done, calling it more than
+	// once is safe. Each call starts an independent heartbeat. If interval isn't greater than zero, nothing is logged
+	// and stop does nothing.
+	StartHeartbeat(interval time.Duration, msg string) (stop func())
+	// Go runs fn in a new goroutine that recovers from a panic of fn and logs it at error level with the panic value
+	// under the key "panic" and the stack under the key "stack", e.g. for background workers, whose panics would crash the
+	// process without a log. By default, the panic is swallowed and the goroutine ends. With the option RepanicInGo, the
+	// goroutine panics again after logging, which crashes the process.
+	Go(fn func())
+	// Sync flushes buffered entries to the destination without closing the logger. For loggers created by New with the
+	// zerolog or gelf backend, the writer is synced if it has a method "Sync() error", like *os.File. Sync does nothing
+	// for the logrus backend, for channel loggers and for loggers passed to FromZerolog.
+	Sync() error
 }
 
 // Entry is an interface for a log entry. A single entry always has defined a log level. Custom fields can be
@@ -92,11 +209,27 @@ type Entry interface {
 	// Flush writes the entry as a single log statement. Optionally, a message can be added which will
 	// be included in the final log entry
 	Flush(string)
+	// Discard abandons the entry without writing it, e.g. if it turns out to be unnecessary after fields have been
+	// added. Like after Flush, the entry must not be used afterwards.
+	Discard()
+	// Bytes returns the entry in the format of the logger as Flush would write it without a message, e.g. to embed it
+	// in a response. The entry isn't written and can still be flushed afterwards. The level of the logger isn't
+	// applied. Options that are applied on Flush, like ReportCaller, don't add their fields.
+	Bytes() ([]byte, error)
+	// At changes the level of the entry. The level is evaluated when the entry is flushed, so it can be decided after
+	// all fields have been added. Unknown levels are treated as InfoLevel.
+	At(Level) Entry
+	// NoTime removes the time field that is added to each entry automatically, e.g. to add the time of an event
+	// instead. The logrus formatter still writes its own timestamp.
+	NoTime() Entry
+	// Retention sets the retention class of the entry under the key "retention", e.g. "short", "long" or "audit", so
+	// that the log pipeline can route it to a storage tier. It overrides the class set with DefaultRetention.
+	Retention(class string) Entry
 
 	// AddFields adds a range of fields to the log statement
 	AddFields(map[string]interface{}) Entry
 	// AddErr adds an error to the log statement. The error will have the key "err". An error stack will be included
-	// under the key "err_stack"
+	// under the key "err_stack". Use the option ErrorKeys to change the keys.
 	AddErr(err error) Entry
 	// AddError adds an error to the log statement. An error stack will be included under the key "${key}_stack"
 	AddError(key string, val error) Entry
@@ -112,4 +245,149 @@ type Entry interface {
 	AddDur(key string, val time.Duration) Entry
 	// AddAny adds any value to the log statement.
 	AddAny(key string, val interface{}) Entry
+	// AddJSONRaw adds pre-serialized JSON to the log statement without encoding it again. If raw is not valid JSON,
+	// it is added as a string under the key "${key}_invalid"
+	AddJSONRaw(key string, raw []byte) Entry
+	// AddCount adds a count to the log statement. The number is stored under key, a human readable form like "3 items"
+	// is stored under the key "${key}_human"
+	AddCount(key string, n int, singular, plural string) Entry
+	// AddQuery adds a database query to the log statement. The statement, its arguments as JSON array, the number of
+	// rows and the duration are stored under the keys "db.sql", "db.args", "db.rows" and "db.duration"
+	AddQuery(sql string, args []interface{}, rows int, dur time.Duration) Entry
+	// AddTraceparent adds the trace id and span id of the W3C traceparent header in headers under the keys
+	// "${key}_trace_id" and "${key}_span_id". A missing or malformed header adds nothing.
+	AddTraceparent(key string, headers http.Header) Entry
+	// AddMetric adds a metric to the log statement. Name, value and tags are stored as a nested object under the key
+	// "metric", so that log collectors can route the statement to a metrics sink.
+	AddMetric(name string, value float64, tags map[string]string) Entry
+	// AddHex adds a byte slice to the log statement, encoded as hex string.
+	AddHex(key string, val []byte) Entry
+	// AddBase64 adds a byte slice to the log statement, encoded as base64 string with standard encoding.
+	AddBase64(key string, val []byte) Entry
+	// AddElapsed adds the duration since the specified time to the log statement.
+	AddElapsed(key string, since time.Time) Entry
+	// AddDurHuman adds a duration to the log statement as human readable string like "1.2s" or "3m04s". Use AddDur for
+	// values that should be queryable.
+	AddDurHuman(key string, d time.Duration) Entry
+	// AddErrN adds an error to the log statement like AddErr, but the error stack under the key "err_stack" is
+	// truncated to the first maxFrames frames. The error message is kept intact.
+	AddErrN(err error, maxFrames int) Entry
+	// AddSensitive adds the hex encoded SHA-256 hash of val under the key "${key}_hash" to the log statement. The raw
+	// value is never logged. Use the options SensitiveSalt and SensitiveHashLen to configure the hash.
+	AddSensitive(key string, val string) Entry
+	// AddBytesLen adds the length of a byte slice to the log statement instead of its content. A nil slice has length 0.
+	AddBytesLen(key string, val []byte) Entry
+	// AddStrLen adds the number of runes of a string to the log statement instead of the string itself.
+	AddStrLen(key string, val string) Entry
+	// AddJSONString adds a JSON encoded string to the log statement without encoding it again. If s is not valid JSON,
+	// it is added as a string under the key "${key}_invalid"
+	AddJSONString(key, s string) Entry
+	// AddRetry adds the state of a retry loop to the log statement. The attempt, the maximum number of attempts and the
+	// backoff before the next attempt are stored under the keys "retry.attempt", "retry.max" and "retry.backoff"
+	AddRetry(attempt, max int, backoff time.Duration) Entry
+	// AddDeadline adds the time remaining until the deadline of ctx to the log statement. It is negative if the deadline
+	// has passed. If ctx has no deadline, nothing is added.
+	AddDeadline(key string, ctx context.Context) Entry
+	// AddRequest adds the method, path, remote address, user agent and host of r to the log statement under the keys
+	// "http.method", "http.path", "http.remote_addr", "http.user_agent" and "http.host". Other headers are never logged.
+	AddRequest(r *http.Request) Entry
+	// AddResponse adds the status code, the size of the body and the duration of an HTTP response to the log statement
+	// under the keys "http.status", "http.bytes" and "http.duration". The class of the status like "5xx" is stored under
+	// the key "http.status_class".
+	AddResponse(status, bytes int, dur time.Duration) Entry
+	// AddSlice adds a slice or an array of any element type to the log statement as JSON array. Other values, or slices
+	// that can't be encoded as JSON, are added like with AddAny.
+	AddSlice(key string, vals interface{}) Entry
+	// AddBoolPtr adds the value of a bool pointer to the log statement. A nil pointer omits the field, or adds null
+	// with the option NilAsNull.
+	AddBoolPtr(key string, val *bool) Entry
+	// AddIntPtr adds the value of an integer pointer to the log statement. A nil pointer omits the field, or adds null
+	// with the option NilAsNull.
+	AddIntPtr(key string, val *int) Entry
+	// AddStrPtr adds the value of a string pointer to the log statement. A nil pointer omits the field, or adds null
+	// with the option NilAsNull.
+	AddStrPtr(key string, val *string) Entry
+	// AddNullString adds the value of a sql.NullString to the log statement, e.g. a column read
+	// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+	AddNullString(key string, val sql.NullString) Entry
+	// AddNullInt64 adds the value of a sql.NullInt64 to the log statement, e.g. a column read
+	// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+	AddNullInt64(key string, val sql.NullInt64) Entry
+	// AddNullFloat64 adds the value of a sql.NullFloat64 to the log statement, e.g. a column read
+	// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+	AddNullFloat64(key string, val sql.NullFloat64) Entry
+	// AddNullBool adds the value of a sql.NullBool to the log statement, e.g. a column read
+	// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+	AddNullBool(key string, val sql.NullBool) Entry
+	// AddCaller adds the location that called it as "file:line" under the key "caller" and the calling function under
+	// the key "caller_func" to the log statement. Unlike the option ReportCaller, it only applies to this entry.
+	AddCaller() Entry
+	// AddCallerSkip adds the caller like AddCaller, but skips n additional frames, e.g. in helpers that wrap an Entry.
+	AddCallerSkip(n int) Entry
+	// AddIntThreshold adds a value to the log statement like AddInt and additionally flagKey as true if the value exceeds
+	// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+	// FlagBelowThreshold.
+	AddIntThreshold(key string, val, threshold int, flagKey string) Entry
+	// AddDurThreshold adds a value to the log statement like AddDur and additionally flagKey as true if the value exceeds
+	// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+	// FlagBelowThreshold.
+	AddDurThreshold(key string, val, threshold time.Duration, flagKey string) Entry
+	// AddMoney adds an amount of money in minor units of an ISO 4217 currency, e.g. cents for "USD", to the log statement.
+	// The amount and the currency are stored under the keys "${key}.amount_minor" and "${key}.currency", the formatted
+	// amount like "$12.99" under the key "${key}.display". Unknown currencies, including an empty code, are formatted
+	// with two decimal places and flagged with true under the key "${key}.currency_unknown".
+	AddMoney(key string, minorUnits int64, currency string) Entry
+	// AddBigRat adds an arbitrary-precision rational number to the log statement as string, so that exact values like
+	// amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default.
+	// nil is added as "<nil>".
+	AddBigRat(key string, val *big.Rat) Entry
+	// AddBigFloat adds an arbitrary-precision floating-point number to the log statement as string, so that exact
+	// values like amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact
+	// by default. nil is added as "<nil>".
+	AddBigFloat(key string, val *big.Float) Entry
+	// AddErrChain adds the message of an error under key, the number of errors it wraps under the key "${key}_depth" and
+	// the type names of the errors in its chain, starting with err, under the key "${key}_types" to the log statement.
+	// Both the standard library's wrapping and juju/errors are followed. A nil error adds nothing.
+	AddErrChain(key string, err error) Entry
+	// AddInterval adds a time window to the log statement. Start and end are stored in UTC under the keys "${key}.start"
+	// and "${key}.end", the duration between them under the key "${key}.duration". If end is before start, the duration
+	// is negative.
+	AddInterval(key string, start, end time.Time) Entry
+	// AddValidationErrors adds the results of a failed validation to the log statement as nested object that maps each
+	// invalid field to its error message, e.g. {"email": "must not be empty"}. A nil or empty map adds nothing.
+	AddValidationErrors(key string, errs map[string]string) Entry
+	// AddGeo adds coordinates to the log statement as nested object with the keys "lat" and "lon", which Elasticsearch
+	// maps as geo_point. If the latitude isn't within [-90, 90] or the longitude isn't within [-180, 180], the
+	// coordinates are still added and "${key}_invalid" is set to true.
+	AddGeo(key string, lat, lon float64) Entry
+	// AddDiff adds the old and the new value of a change to the log statement as nested object with the keys "old" and
+	// "new". If both values are equal according to reflect.DeepEqual, nothing is added.
+	AddDiff(key string, oldVal, newVal interface{}) Entry
+	// AddRuntime adds diagnostics of the Go runtime to the log statement: the Go version, the operating system and the
+	// architecture under the keys "go.version", "go.os" and "go.arch", the number of goroutines under the key
+	// "go.goroutines" and the allocated heap in MiB under the key "go.heap_mb". Reading the heap stops the world, so use
+	// it for rare entries like crash reports, not in hot paths.
+	AddRuntime() Entry
+	// AddForm adds the values of the listed form fields to the log statement under the keys "form.${name}". Multiple
+	// values of a field are joined with commas. Fields that aren't listed are never added, e.g. passwords, and listed
+	// fields missing from values are skipped.
+	AddForm(values url.Values, keys ...string) Entry
+	// AddBackoff computes the wait before the next attempt of a retry loop and adds it to the log statement together with
+	// the attempt under the keys "backoff.wait" and "backoff.attempt". The wait doubles with each attempt, starting at
+	// base for attempt 1, and is capped at max. Half of it is random jitter, so that clients don't retry in lockstep.
+	// The wait is returned so that the caller can sleep on it.
+	AddBackoff(attempt int, base, max time.Duration) (Entry, time.Duration)
+	// AddLabels adds a set of labels like environment, team or service to the log statement as nested object under the
+	// key "labels". Label names that don't match the Prometheus label name syntax [a-zA-Z_][a-zA-Z0-9_]* are still added
+	// and listed under the key "labels_invalid". Labels of a logger created by WithLabels are merged with labels, with
+	// labels taking precedence. A nil or empty map adds nothing.
+	AddLabels(labels map[string]string) Entry
+	// AddPercent adds a fraction like a success ratio to the log statement under key and the same value as percentage
+	// string with one decimal under "${key}_pct", e.g. 0.873 as "87.3%", so that dashboards don't mix fractions and
+	// percentages. A fraction outside [0, 1] is clamped and "${key}_invalid" is set to true, NaN is clamped to 0.
+	AddPercent(key string, fraction float64) Entry
+	// AddKV adds alternating keys and values to the log statement, e.g. AddKV("user", id, "ok", true), like AddAny for
+	// each pair. It trades the type safety of the typed methods for brevity. Keys that aren't strings are formatted with
+	// fmt.Sprint. If the number of arguments is odd, the last key is added under "kv_warning" as missing its value.
+	AddKV(pairs ...interface{}) Entry
 }
@@ -1,8 +1,13 @@
 package logger
 
 import (
+	"errors"
+	"math"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -27,6 +32,15 @@ func tests() []struct {
 	}
 }
 
+// exitCode runs f and returns the code exitFunc was called with, or -1 if it wasn't called
+func exitCode(f func()) int {
+	code := -1
+	SetExitFunc(func(c int) { code = c })
+	defer SetExitFunc(nil)
+	f()
+	return code
+}
+
 func TestNew(t *testing.T) {
 	tests := []struct {
 		lvl    Level
@@ -60,3 +74,99 @@ func TestNew(t *testing.T) {
 		}
 	}
 }
+
+// syncBuffer counts the calls to Sync
+type syncBuffer struct {
+	strings.Builder
+	syncs int
+	err   error
+}
+
+func (s *syncBuffer) Sync() error {
+	s.syncs++
+	return s.err
+}
+
+func TestLogger_Sync(t *testing.T) {
+	for _, impl := range []Implementation{ZeroLogBackend, GelfBackend} {
+		var sb syncBuffer
+		l := New(&sb, DebugLevel, impl).WithField("key", "val")
+		assert.NoError(t, l.Sync(), "Sync should not fail")
+		assert.Equal(t, 1, sb.syncs, "Writer should be synced")
+		sb.err = errors.New("sync failed")
+		assert.EqualError(t, l.Sync(), "sync failed", "Error of the writer should be returned")
+	}
+
+	var sb syncBuffer
+	l := New(&sb, DebugLevel, LogrusBackend)
+	assert.NoError(t, l.Sync(), "Sync should not fail")
+	assert.Equal(t, 0, sb.syncs, "Sync should do nothing for logrus")
+	assert.NoError(t, New(&strings.Builder{}, DebugLevel, ZeroLogBackend).Sync(), "Writers without Sync should be ignored")
+}
+
+// outsideLogger implements Logger like a type outside of this package, e.g. a fake in a test
+type outsideLogger struct {
+	Logger
+}
+
+func TestLogger_Exported(t *testing.T) {
+	for _, typ := range []reflect.Type{reflect.TypeOf((*Logger)(nil)).Elem(), reflect.TypeOf((*Entry)(nil)).Elem()} {
+		for i := 0; i < typ.NumMethod(); i++ {
+			m := typ.Method(i)
+			assert.Empty(t, m.PkgPath, "%s.%s should be exported, so that other packages can implement it", typ.Name(), m.Name)
+		}
+	}
+
+	var sb strings.Builder
+	l := outsideLogger{New(&sb, DebugLevel, ZeroLogBackend)}
+	assert.Nil(t, configOf(l), "Loggers of other packages should have no options")
+	NewAllowlist(l, []string{"key"}).Info().AddStr("key", "val").AddErr(errors.New("failed")).Flush("")
+	assert.Contains(t, sb.String(), `"key":"val"`, "Decorators should wrap loggers of other packages")
+}
+
+func TestSetExitFunc(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	var code int
+	SetExitFunc(func(c int) { code = c })
+	defer SetExitFunc(nil)
+	l.Fatal().Flush("fatal")
+	assert.Equal(t, 1, code, "Exit function should be called")
+	assert.Contains(t, sb.String(), "fatal", "Entry should be written before exiting")
+}
+
+// wrapErr wraps an error like fmt.Errorf with %w
+type wrapErr struct {
+	msg string
+	err error
+}
+
+func (w *wrapErr) Error() string { return w.msg + ": " + w.err.Error() }
+
+func (w *wrapErr) Unwrap() error { return w.err }
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{0, 50 * time.Millisecond, 100 * time.Millisecond},
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{5, 800 * time.Millisecond, 1600 * time.Millisecond},
+		{10, 5 * time.Second, 10 * time.Second},
+		{1000, 5 * time.Second, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 10; i++ {
+			d := backoff(tt.attempt, 100*time.Millisecond, 10*time.Second)
+			assert.True(t, d >= tt.min && d <= tt.max, "attempt %d: %s not in [%s, %s]", tt.attempt, d, tt.min, tt.max)
+		}
+	}
+	assert.Equal(t, time.Duration(0), backoff(1, 0, time.Second), "A base of 0 should not wait")
+	assert.Equal(t, time.Duration(0), backoff(math.MaxInt32, 0, time.Second), "A base of 0 should not wait")
+	assert.Equal(t, time.Duration(0), backoff(math.MaxInt32, -time.Second, time.Second), "A negative base should not wait")
+	assert.Equal(t, time.Duration(0), backoff(3, time.Second, -time.Second), "A negative max should not wait")
+	d := backoff(100, time.Hour, time.Duration(math.MaxInt64))
+	assert.True(t, d > 0, "Waits should not overflow")
+}
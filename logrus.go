@@ -1,10 +1,21 @@
 package logger
 
 import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
-	"os"
+	"math/big"
+	"net/http"
+	"net/url"
+	"reflect"
+	"runtime"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/juju/errors"
 	"github.com/sirupsen/logrus"
@@ -28,21 +39,83 @@ func ltolr(level Level) logrus.Level {
 	panic(fmt.Sprintf("Can't map level %d to logrus level", level))
 }
 
-func newLogrus(w io.Writer, lvl Level) Logger {
+func newLogrus(w io.Writer, lvl Level, c *config) Logger {
 	l := logrus.New()
 	l.SetOutput(w)
 	l.SetLevel(ltolr(lvl))
-	return &lLog{l}
+	if c.formatter != nil {
+		l.SetFormatter(c.formatter)
+	}
+	for _, h := range c.hooks {
+		l.AddHook(h)
+	}
+	return &lLog{l, c}
+}
+
+// rawJSON is embedded as is by logrus' JSON formatter and printed as plain text by the text formatter
+type rawJSON json.RawMessage
+
+// MarshalJSON returns the raw bytes unchanged
+func (r rawJSON) MarshalJSON() ([]byte, error) {
+	return r, nil
+}
+
+// String returns the raw bytes as string
+func (r rawJSON) String() string {
+	return string(r)
 }
 
 type lLog struct {
 	writer logrus.FieldLogger
+	cfg    *config
 }
 
+var _ Logger = (*lLog)(nil)
+
 // WithField returns a new Logger that always logs the specified field
 func (l *lLog) WithField(key, value string) Logger {
 	writer := l.writer.WithField(key, value)
-	return &lLog{writer: writer}
+	return &lLog{writer: writer, cfg: l.cfg}
+}
+
+// WithAny returns a new Logger that always logs the specified field. Unlike WithField, the value can be of any type
+func (l *lLog) WithAny(key string, value interface{}) Logger {
+	writer := l.writer.WithField(key, value)
+	return &lLog{writer: writer, cfg: l.cfg}
+}
+
+// WithMessagePrefix returns a new Logger that prefixes the message of each entry with prefix and ": ", e.g. for
+// consumers that grep for a tag instead of parsing fields. Empty messages are prefixed, too.
+func (l *lLog) WithMessagePrefix(prefix string) Logger {
+	return newPrefix(l, prefix)
+}
+
+// WithLevelRemap returns a new Logger that changes the level of its entries according to remap on Flush, e.g.
+// {ErrorLevel: WarnLevel} to keep the errors of a noisy dependency from triggering alerts. The level check of the
+// logger applies to the level before remapping: an entry is only written if its original level is enabled, and
+// then it's written at the new level. An entry remapped to a level below the level of the logger is dropped by the
+// logger, though. Entries at fatal and panic level are never remapped, and no level is remapped to them.
+func (l *lLog) WithLevelRemap(remap map[Level]Level) Logger {
+	return newRemap(l, remap)
+}
+
+// WithLabels returns a new Logger that adds labels to each entry like AddLabels. Labels of nested calls and of
+// AddLabels are merged into one object, later labels take precedence.
+func (l *lLog) WithLabels(labels map[string]string) Logger {
+	return newLabels(l, labels)
+}
+
+// WithEnvironment returns a new Logger that always logs the deployment environment env like "prod" under the key
+// "env", e.g. the result of DetectEnvironment. An empty env is logged as "unknown".
+func (l *lLog) WithEnvironment(env string) Logger {
+	return l.WithField("env", environment(env))
+}
+
+// WithCorrelation returns a new Logger that logs the correlation id under the key "correlation_id", e.g. the result
+// of ExtractCorrelation for an incoming request. InjectCorrelation sets the id in the headers of outgoing requests.
+// An empty id returns the logger unchanged.
+func (l *lLog) WithCorrelation(id string) Logger {
+	return newCorrelation(l, id)
 }
 
 // Level creates a new Entry with the specified Level
@@ -67,61 +140,200 @@ func (l *lLog) Level(lvl Level) Entry {
 
 // Debug creates a new Entry with level Debug
 func (l *lLog) Debug() Entry {
-	return &lEntry{logrus.DebugLevel, l.writer.WithField("time", time.Now())}
+	if !l.cfg.sample(DebugLevel) {
+		return nopEntry{}
+	}
+	return l.cfg.sampled(&lEntry{logrus.DebugLevel, l.writer.WithField("time", l.cfg.now()), l.cfg}, DebugLevel)
 }
 
 // Info creates a new Entry with level Info
 func (l *lLog) Info() Entry {
-	return &lEntry{logrus.InfoLevel, l.writer.WithField("time", time.Now())}
+	if !l.cfg.sample(InfoLevel) {
+		return nopEntry{}
+	}
+	return l.cfg.sampled(&lEntry{logrus.InfoLevel, l.writer.WithField("time", l.cfg.now()), l.cfg}, InfoLevel)
 }
 
 // Warn creates a new Entry with level Warn
 func (l *lLog) Warn() Entry {
-	return &lEntry{logrus.WarnLevel, l.writer.WithField("time", time.Now())}
+	if !l.cfg.sample(WarnLevel) {
+		return nopEntry{}
+	}
+	return l.cfg.sampled(&lEntry{logrus.WarnLevel, l.writer.WithField("time", l.cfg.now()), l.cfg}, WarnLevel)
 }
 
 // Error creates a new Entry with level Error
 func (l *lLog) Error() Entry {
-	return &lEntry{logrus.ErrorLevel, l.writer.WithField("time", time.Now())}
+	if !l.cfg.sample(ErrorLevel) {
+		return nopEntry{}
+	}
+	return l.cfg.sampled(&lEntry{logrus.ErrorLevel, l.writer.WithField("time", l.cfg.now()), l.cfg}, ErrorLevel)
 }
 
-// Fatal creates a new Entry with level Fatal. Executing a log at fatal level exits the application with exit code 1.
+// Fatal creates a new Entry with level Fatal. Executing a log at fatal level exits the application with exit code 1,
+// or the code set with FatalExitCode.
 func (l *lLog) Fatal() Entry {
-	return &lEntry{logrus.FatalLevel, l.writer.WithField("time", time.Now())}
+	return &lEntry{logrus.FatalLevel, l.writer.WithField("time", l.cfg.now()), l.cfg}
+}
+
+// PipeWriter returns a writer that logs every line written to it as an entry at the specified level. The entries
+// have the field "stream" set to stream. The last line is logged on Close even if it doesn't end with a newline.
+func (l *lLog) PipeWriter(lvl Level, stream string) io.WriteCloser {
+	return newLineWriter(l, lvl, stream)
+}
+
+// Operation logs the start of an operation at debug level and returns a function that logs its end together with
+// the duration of the operation. The end is logged at info level, or at error level if a non-nil error is passed.
+// Use it as "defer done(err)". Both entries have the same id under the key "operation_id", see WithIDGenerator.
+func (l *lLog) Operation(name string) func(err error) {
+	return operation(l, name)
+}
+
+// NewTimer returns a Timer that starts now, e.g. "t := l.NewTimer(); parse(); t.Mark("parse")". Add the phases to an
+// entry with Timer.AddTo.
+func (l *lLog) NewTimer() *Timer {
+	return newTimer(l.cfg)
+}
+
+// StartHeartbeat logs msg at info level every interval with the time since StartHeartbeat was called under the key
+// "uptime" and the number of goroutines under the key "goroutines", e.g. to show that a service is alive. It returns
+// a function that stops the heartbeat and waits until an entry that is being written is done, calling it more than
+// once is safe. Each call starts an independent heartbeat. If interval isn't greater than zero, nothing is logged
+// and stop does nothing.
+func (l *lLog) StartHeartbeat(interval time.Duration, msg string) (stop func()) {
+	return heartbeat(l, interval, msg)
+}
+
+// Go runs fn in a new goroutine that recovers from a panic of fn and logs it at error level with the panic value
+// under the key "panic" and the stack under the key "stack", e.g. for background workers, whose panics would crash the
+// process without a log. By default, the panic is swallowed and the goroutine ends. With the option RepanicInGo, the
+// goroutine panics again after logging, which crashes the process.
+func (l *lLog) Go(fn func()) {
+	goSafe(l, fn)
+}
+
+// Sync flushes buffered entries to the destination without closing the logger. For loggers created by New with the
+// zerolog or gelf backend, the writer is synced if it has a method "Sync() error", like *os.File. Sync does nothing
+// for the logrus backend, for channel loggers and for loggers passed to FromZerolog.
+func (l *lLog) Sync() error {
+	return nil
+}
+
+// config returns the options of the logger
+func (l *lLog) config() *config {
+	return l.cfg
 }
 
 // Panic creates a new Entry with level Panic. Executing a log at panic level will call panic().
 func (l *lLog) Panic() Entry {
-	return &lEntry{logrus.PanicLevel, l.writer.WithField("time", time.Now())}
+	return &lEntry{logrus.PanicLevel, l.writer.WithField("time", l.cfg.now()), l.cfg}
 }
 
 type lEntry struct {
 	level logrus.Level
 	entry *logrus.Entry
+	cfg   *config
 }
 
+var _ Entry = (*lEntry)(nil)
+
 // Flush writes the entry as a single log statement. Optionally, a message can be added which will
 // be included in the final log entry
 func (l *lEntry) Flush(msg string) {
+	if l.cfg.reportCaller {
+		l.entry = l.entry.WithField("caller", caller(l.cfg.callerSkip))
+	}
+	if l.cfg.goroutineID {
+		l.entry = l.entry.WithField("goroutine", goroutineID())
+	}
+	if l.cfg.timeCapture == TimeAtFlush {
+		if _, ok := l.entry.Data["time"]; ok {
+			l.entry = l.entry.WithField("time", l.cfg.now())
+		}
+	}
+	if l.entry.Time.IsZero() {
+		l.entry.Time = timeNow(l.cfg)
+	}
+	l.defaultRetention()
+	if len(l.cfg.scrub) > 0 {
+		scrubFields(l.entry.Data, l.cfg.scrub)
+		msg, _ = scrub(msg, l.cfg.scrub)
+	}
+	if l.level == logrus.PanicLevel {
+		// logrus panics right after writing the entry
+		defer func() {
+			r := recover()
+			l.cfg.panicked(func() map[string]interface{} {
+				fs := make(map[string]interface{}, len(l.entry.Data))
+				for k, v := range l.entry.Data {
+					fs[k] = v
+				}
+				return fs
+			})
+			panic(r)
+		}()
+	}
 	l.entry.Logln(l.level, msg)
 	if l.level == logrus.FatalLevel {
-		os.Exit(1)
+		exitFunc(l.cfg.exitCode)
+	}
+}
+
+// Bytes returns the entry formatted by the formatter of the logger without a message, but doesn't write it. The level
+// of the logger isn't applied and hooks aren't fired. The entry can still be flushed afterwards.
+func (l *lEntry) Bytes() ([]byte, error) {
+	l.defaultRetention()
+	// format a copy, so that the entry isn't modified
+	e := *l.entry
+	if e.Time.IsZero() {
+		e.Time = timeNow(l.cfg)
 	}
+	e.Level = l.level
+	e.Message = ""
+	return e.Logger.Formatter.Format(&e)
+}
+
+// defaultRetention adds the retention class of DefaultRetention unless Retention has been called
+func (l *lEntry) defaultRetention() {
+	if l.cfg.retention == "" {
+		return
+	}
+	if _, ok := l.entry.Data["retention"]; !ok {
+		l.entry.Data["retention"] = l.cfg.retention
+	}
+}
+
+// Discard abandons the entry without writing it. The entry must not be used afterwards.
+func (l *lEntry) Discard() {}
+
+// At changes the level of the entry. The level is evaluated when the entry is flushed.
+func (l *lEntry) At(lvl Level) Entry {
+	l.level = ltolr(validLevel(lvl))
+	return l
+}
+
+// NoTime removes the time field that is added to each entry automatically, e.g. to add the time of an event instead.
+// The logrus formatter still writes its own timestamp.
+func (l *lEntry) NoTime() Entry {
+	// WithField copies the fields of the entry, so the fields of the parent logger are not affected
+	delete(l.entry.Data, "time")
+	return l
 }
 
 // AddFields adds a range of fields to the log statement
 func (l *lEntry) AddFields(fs map[string]interface{}) Entry {
+	fs = l.cfg.fields(fs)
 	l.entry = l.entry.WithFields(fs)
 	return l
 }
 
 // AddErr adds an error to the log statement. The error will have the key "err". An error stack will be included
-// under the key "err_stack"
+// under the key "err_stack". Use the option ErrorKeys to change the keys.
 func (l *lEntry) AddErr(err error) Entry {
 	msg := err.Error()
 	st := errors.ErrorStack(err)
-	l.entry = l.entry.WithField("err", msg)
-	l.entry = l.entry.WithField("err_stack", st)
+	l.entry = l.entry.WithField(l.cfg.errKey, msg)
+	l.entry = l.entry.WithField(l.cfg.errStackKey, st)
 	return l
 }
 
@@ -136,36 +348,470 @@ func (l *lEntry) AddError(key string, val error) Entry {
 
 // AddBool adds a bool value to the log statement.
 func (l *lEntry) AddBool(key string, val bool) Entry {
+	if l.cfg.omitEmpty && !val {
+		return l
+	}
 	l.entry = l.entry.WithField(key, val)
 	return l
 }
 
 // AddInt adds an integer value to the log statement.
 func (l *lEntry) AddInt(key string, val int) Entry {
+	if l.cfg.omitEmpty && val == 0 {
+		return l
+	}
+	if l.cfg.maxInt != 0 {
+		if s, ok := largeInt(val, l.cfg.maxInt); ok {
+			l.entry = l.entry.WithField(key, s)
+			return l
+		}
+	}
 	l.entry = l.entry.WithField(key, val)
 	return l
 }
 
 // AddStr adds a string value to the log statement.
 func (l *lEntry) AddStr(key string, val string) Entry {
+	if l.cfg.omitEmpty && val == "" {
+		return l
+	}
 	l.entry = l.entry.WithField(key, val)
 	return l
 }
 
 // AddTime adds a time value to the log statement.
 func (l *lEntry) AddTime(key string, val time.Time) Entry {
+	if l.cfg.omitEmpty && val.IsZero() {
+		return l
+	}
 	l.entry = l.entry.WithField(key, val)
 	return l
 }
 
 // AddDur adds a duration value to the log statement.
 func (l *lEntry) AddDur(key string, val time.Duration) Entry {
+	if l.cfg.omitEmpty && val == 0 {
+		return l
+	}
 	l.entry = l.entry.WithField(key, val)
 	return l
 }
 
 // AddAny adds any value to the log statement.
 func (l *lEntry) AddAny(key string, val interface{}) Entry {
-	l.entry = l.entry.WithField(key, val)
+	l.entry = l.entry.WithField(key, l.cfg.quote(val))
+	return l
+}
+
+// AddJSONRaw adds pre-serialized JSON to the log statement without encoding it again. If raw is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (l *lEntry) AddJSONRaw(key string, raw []byte) Entry {
+	if !json.Valid(raw) {
+		l.entry = l.entry.WithField(key+"_invalid", string(raw))
+		return l
+	}
+	l.entry = l.entry.WithField(key, rawJSON(raw))
+	return l
+}
+
+// AddCount adds a count to the log statement. The number is stored under key, a human readable form like "3 items"
+// is stored under the key "${key}_human"
+func (l *lEntry) AddCount(key string, n int, singular, plural string) Entry {
+	l.entry = l.entry.WithField(key, n)
+	l.entry = l.entry.WithField(key+"_human", pluralize(n, singular, plural))
+	return l
+}
+
+// AddQuery adds a database query to the log statement. The statement, its arguments as JSON array, the number of
+// rows and the duration are stored under the keys "db.sql", "db.args", "db.rows" and "db.duration"
+func (l *lEntry) AddQuery(sql string, args []interface{}, rows int, dur time.Duration) Entry {
+	l.entry = l.entry.WithField("db.sql", sql)
+	if b, err := json.Marshal(args); err == nil {
+		l.entry = l.entry.WithField("db.args", rawJSON(b))
+	} else {
+		l.entry = l.entry.WithField("db.args", args)
+	}
+	l.entry = l.entry.WithField("db.rows", rows)
+	l.entry = l.entry.WithField("db.duration", dur)
+	return l
+}
+
+// AddTraceparent adds the trace id and span id of the W3C traceparent header in headers under the keys
+// "${key}_trace_id" and "${key}_span_id". A missing or malformed header adds nothing.
+func (l *lEntry) AddTraceparent(key string, headers http.Header) Entry {
+	if traceID, spanID, ok := traceparent(headers); ok {
+		l.entry = l.entry.WithField(key+"_trace_id", traceID)
+		l.entry = l.entry.WithField(key+"_span_id", spanID)
+	}
+	return l
+}
+
+// AddMetric adds a metric to the log statement. Name, value and tags are stored as a nested object under the key
+// "metric", so that log collectors can route the statement to a metrics sink.
+func (l *lEntry) AddMetric(name string, value float64, tags map[string]string) Entry {
+	l.entry = l.entry.WithField("metric", metric{name, value, tags})
+	return l
+}
+
+// AddHex adds a byte slice to the log statement, encoded as hex string.
+func (l *lEntry) AddHex(key string, val []byte) Entry {
+	l.entry = l.entry.WithField(key, hex.EncodeToString(val))
+	return l
+}
+
+// AddBase64 adds a byte slice to the log statement, encoded as base64 string with standard encoding.
+func (l *lEntry) AddBase64(key string, val []byte) Entry {
+	l.entry = l.entry.WithField(key, base64.StdEncoding.EncodeToString(val))
+	return l
+}
+
+// AddElapsed adds the duration since the specified time to the log statement.
+func (l *lEntry) AddElapsed(key string, since time.Time) Entry {
+	return l.AddDur(key, timeNow(l.cfg).Sub(since))
+}
+
+// AddDurHuman adds a duration to the log statement as human readable string like "1.2s" or "3m04s". Use AddDur for
+// values that should be queryable.
+func (l *lEntry) AddDurHuman(key string, d time.Duration) Entry {
+	return l.AddStr(key, humanDur(d))
+}
+
+// AddErrN adds an error to the log statement like AddErr, but the error stack under the key "err_stack" is
+// truncated to the first maxFrames frames. The error message is kept intact.
+func (l *lEntry) AddErrN(err error, maxFrames int) Entry {
+	l.entry = l.entry.WithField(l.cfg.errKey, err.Error())
+	l.entry = l.entry.WithField(l.cfg.errStackKey, truncateStack(errors.ErrorStack(err), maxFrames))
+	return l
+}
+
+// AddSensitive adds the hex encoded SHA-256 hash of val under the key "${key}_hash" to the log statement. The raw
+// value is never logged. Use the options SensitiveSalt and SensitiveHashLen to configure the hash.
+func (l *lEntry) AddSensitive(key string, val string) Entry {
+	return l.AddStr(key+"_hash", l.cfg.hash(val))
+}
+
+// AddBytesLen adds the length of a byte slice to the log statement instead of its content. A nil slice has length 0.
+func (l *lEntry) AddBytesLen(key string, val []byte) Entry {
+	l.entry = l.entry.WithField(key, len(val))
+	return l
+}
+
+// AddStrLen adds the number of runes of a string to the log statement instead of the string itself.
+func (l *lEntry) AddStrLen(key string, val string) Entry {
+	l.entry = l.entry.WithField(key, utf8.RuneCountInString(val))
+	return l
+}
+
+// AddJSONString adds a JSON encoded string to the log statement without encoding it again. If s is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (l *lEntry) AddJSONString(key, s string) Entry {
+	return l.AddJSONRaw(key, []byte(s))
+}
+
+// AddRetry adds the state of a retry loop to the log statement. The attempt, the maximum number of attempts and the
+// backoff before the next attempt are stored under the keys "retry.attempt", "retry.max" and "retry.backoff"
+func (l *lEntry) AddRetry(attempt, max int, backoff time.Duration) Entry {
+	l.entry = l.entry.WithField("retry.attempt", attempt)
+	l.entry = l.entry.WithField("retry.max", max)
+	l.entry = l.entry.WithField("retry.backoff", backoff)
+	return l
+}
+
+// AddDeadline adds the time remaining until the deadline of ctx to the log statement. It is negative if the deadline
+// has passed. If ctx has no deadline, nothing is added.
+func (l *lEntry) AddDeadline(key string, ctx context.Context) Entry {
+	if d, ok := ctx.Deadline(); ok {
+		return l.AddDur(key, d.Sub(timeNow(l.cfg)))
+	}
+	return l
+}
+
+// AddRequest adds the method, path, remote address, user agent and host of r to the log statement under the keys
+// "http.method", "http.path", "http.remote_addr", "http.user_agent" and "http.host". Other headers are never logged.
+func (l *lEntry) AddRequest(r *http.Request) Entry {
+	return addRequest(l, r, l.cfg.forwardedFor)
+}
+
+// AddResponse adds the status code, the size of the body and the duration of an HTTP response to the log statement
+// under the keys "http.status", "http.bytes" and "http.duration". The class of the status like "5xx" is stored under
+// the key "http.status_class".
+func (l *lEntry) AddResponse(status, bytes int, dur time.Duration) Entry {
+	return addResponse(l, status, bytes, dur)
+}
+
+// AddSlice adds a slice or an array of any element type to the log statement as JSON array. Other values, or slices
+// that can't be encoded as JSON, are added like with AddAny.
+func (l *lEntry) AddSlice(key string, vals interface{}) Entry {
+	if b, ok := sliceJSON(vals); ok {
+		return l.AddJSONRaw(key, b)
+	}
+	return l.AddAny(key, vals)
+}
+
+// AddBoolPtr adds the value of a bool pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (l *lEntry) AddBoolPtr(key string, val *bool) Entry {
+	if val == nil {
+		if l.cfg.nilNull {
+			return l.AddAny(key, nil)
+		}
+		return l
+	}
+	return l.AddBool(key, *val)
+}
+
+// AddIntPtr adds the value of an integer pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (l *lEntry) AddIntPtr(key string, val *int) Entry {
+	if val == nil {
+		if l.cfg.nilNull {
+			return l.AddAny(key, nil)
+		}
+		return l
+	}
+	return l.AddInt(key, *val)
+}
+
+// AddStrPtr adds the value of a string pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (l *lEntry) AddStrPtr(key string, val *string) Entry {
+	if val == nil {
+		if l.cfg.nilNull {
+			return l.AddAny(key, nil)
+		}
+		return l
+	}
+	return l.AddStr(key, *val)
+}
+
+// AddCaller adds the location that called it as "file:line" under the key "caller" and the calling function under
+// the key "caller_func" to the log statement. Unlike the option ReportCaller, it only applies to this entry.
+func (l *lEntry) AddCaller() Entry {
+	return addCaller(l, 0)
+}
+
+// AddCallerSkip adds the caller like AddCaller, but skips n additional frames, e.g. in helpers that wrap an Entry.
+func (l *lEntry) AddCallerSkip(n int) Entry {
+	return addCaller(l, n)
+}
+
+// AddIntThreshold adds a value to the log statement like AddInt and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (l *lEntry) AddIntThreshold(key string, val, threshold int, flagKey string) Entry {
+	l.AddInt(key, val)
+	return thresholdFlag(l, flagKey, val > threshold, l.cfg.flagBelow)
+}
+
+// AddDurThreshold adds a value to the log statement like AddDur and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (l *lEntry) AddDurThreshold(key string, val, threshold time.Duration, flagKey string) Entry {
+	l.AddDur(key, val)
+	return thresholdFlag(l, flagKey, val > threshold, l.cfg.flagBelow)
+}
+
+// AddMoney adds an amount of money in minor units of an ISO 4217 currency, e.g. cents for "USD", to the log statement.
+// The amount and the currency are stored under the keys "${key}.amount_minor" and "${key}.currency", the formatted
+// amount like "$12.99" under the key "${key}.display". Unknown currencies, including an empty code, are formatted
+// with two decimal places and flagged with true under the key "${key}.currency_unknown".
+func (l *lEntry) AddMoney(key string, minorUnits int64, currency string) Entry {
+	currency = strings.ToUpper(currency)
+	l.entry = l.entry.WithField(key+".amount_minor", minorUnits)
+	l.entry = l.entry.WithField(key+".currency", currency)
+	display, known := formatMoney(minorUnits, currency)
+	l.entry = l.entry.WithField(key+".display", display)
+	if !known {
+		l.entry = l.entry.WithField(key+".currency_unknown", true)
+	}
 	return l
 }
+
+// AddErrChain adds the message of an error under key, the number of errors it wraps under the key "${key}_depth" and
+// the type names of the errors in its chain, starting with err, under the key "${key}_types" to the log statement.
+// Both the standard library's wrapping and juju/errors are followed. A nil error adds nothing.
+func (l *lEntry) AddErrChain(key string, err error) Entry {
+	if err == nil {
+		return l
+	}
+	depth, types := errChain(err)
+	l.entry = l.entry.WithField(key, err.Error())
+	l.entry = l.entry.WithField(key+"_depth", depth)
+	l.entry = l.entry.WithField(key+"_types", types)
+	return l
+}
+
+// AddInterval adds a time window to the log statement. Start and end are stored in UTC under the keys "${key}.start"
+// and "${key}.end", the duration between them under the key "${key}.duration". If end is before start, the duration
+// is negative.
+func (l *lEntry) AddInterval(key string, start, end time.Time) Entry {
+	l.entry = l.entry.WithField(key+".start", start.UTC())
+	l.entry = l.entry.WithField(key+".end", end.UTC())
+	l.entry = l.entry.WithField(key+".duration", end.Sub(start))
+	return l
+}
+
+// AddValidationErrors adds the results of a failed validation to the log statement as nested object that maps each
+// invalid field to its error message, e.g. {"email": "must not be empty"}. A nil or empty map adds nothing.
+func (l *lEntry) AddValidationErrors(key string, errs map[string]string) Entry {
+	if len(errs) == 0 {
+		return l
+	}
+	l.entry = l.entry.WithField(key, errs)
+	return l
+}
+
+// AddGeo adds coordinates to the log statement as nested object with the keys "lat" and "lon", which Elasticsearch
+// maps as geo_point. If the latitude isn't within [-90, 90] or the longitude isn't within [-180, 180], the
+// coordinates are still added and "${key}_invalid" is set to true.
+func (l *lEntry) AddGeo(key string, lat, lon float64) Entry {
+	p := geoPoint{lat, lon}
+	l.entry = l.entry.WithField(key, p)
+	if !p.valid() {
+		l.entry = l.entry.WithField(key+"_invalid", true)
+	}
+	return l
+}
+
+// Retention sets the retention class of the entry under the key "retention", e.g. "short", "long" or "audit", so
+// that the log pipeline can route it to a storage tier. It overrides the class set with DefaultRetention.
+func (l *lEntry) Retention(class string) Entry {
+	l.entry.Data["retention"] = class
+	return l
+}
+
+// AddDiff adds the old and the new value of a change to the log statement as nested object with the keys "old" and
+// "new". If both values are equal according to reflect.DeepEqual, nothing is added.
+func (l *lEntry) AddDiff(key string, oldVal, newVal interface{}) Entry {
+	if reflect.DeepEqual(oldVal, newVal) {
+		return l
+	}
+	l.entry = l.entry.WithField(key, diff{oldVal, newVal})
+	return l
+}
+
+// AddRuntime adds diagnostics of the Go runtime to the log statement: the Go version, the operating system and the
+// architecture under the keys "go.version", "go.os" and "go.arch", the number of goroutines under the key
+// "go.goroutines" and the allocated heap in MiB under the key "go.heap_mb". Reading the heap stops the world, so use
+// it for rare entries like crash reports, not in hot paths.
+func (l *lEntry) AddRuntime() Entry {
+	l.entry = l.entry.WithField("go.version", runtime.Version())
+	l.entry = l.entry.WithField("go.os", runtime.GOOS)
+	l.entry = l.entry.WithField("go.arch", runtime.GOARCH)
+	l.entry = l.entry.WithField("go.goroutines", runtime.NumGoroutine())
+	l.entry = l.entry.WithField("go.heap_mb", heapMB())
+	return l
+}
+
+// AddForm adds the values of the listed form fields to the log statement under the keys "form.${name}". Multiple
+// values of a field are joined with commas. Fields that aren't listed are never added, e.g. passwords, and listed
+// fields missing from values are skipped.
+func (l *lEntry) AddForm(values url.Values, keys ...string) Entry {
+	for _, k := range keys {
+		if vs, ok := values[k]; ok {
+			l.AddStr("form."+k, strings.Join(vs, ","))
+		}
+	}
+	return l
+}
+
+// AddBackoff computes the wait before the next attempt of a retry loop and adds it to the log statement together with
+// the attempt under the keys "backoff.wait" and "backoff.attempt". The wait doubles with each attempt, starting at
+// base for attempt 1, and is capped at max. Half of it is random jitter, so that clients don't retry in lockstep.
+// The wait is returned so that the caller can sleep on it.
+func (l *lEntry) AddBackoff(attempt int, base, max time.Duration) (Entry, time.Duration) {
+	d := backoff(attempt, base, max)
+	l.entry = l.entry.WithField("backoff.attempt", attempt)
+	l.entry = l.entry.WithField("backoff.wait", d)
+	return l, d
+}
+
+// AddLabels adds a set of labels like environment, team or service to the log statement as nested object under the
+// key "labels". Label names that don't match the Prometheus label name syntax [a-zA-Z_][a-zA-Z0-9_]* are still added
+// and listed under the key "labels_invalid". Labels of a logger created by WithLabels are merged with labels, with
+// labels taking precedence. A nil or empty map adds nothing.
+func (l *lEntry) AddLabels(labels map[string]string) Entry {
+	return addLabels(l, labels)
+}
+
+// AddPercent adds a fraction like a success ratio to the log statement under key and the same value as percentage
+// string with one decimal under "${key}_pct", e.g. 0.873 as "87.3%", so that dashboards don't mix fractions and
+// percentages. A fraction outside [0, 1] is clamped and "${key}_invalid" is set to true, NaN is clamped to 0.
+func (l *lEntry) AddPercent(key string, fraction float64) Entry {
+	f, pct, ok := percent(fraction)
+	l.entry = l.entry.WithField(key, f)
+	l.entry = l.entry.WithField(key+"_pct", pct)
+	if !ok {
+		l.entry = l.entry.WithField(key+"_invalid", true)
+	}
+	return l
+}
+
+// AddNullString adds the value of a sql.NullString to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (l *lEntry) AddNullString(key string, val sql.NullString) Entry {
+	if !val.Valid {
+		if l.cfg.nilNull {
+			return l.AddAny(key, nil)
+		}
+		return l
+	}
+	return l.AddStr(key, val.String)
+}
+
+// AddNullInt64 adds the value of a sql.NullInt64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (l *lEntry) AddNullInt64(key string, val sql.NullInt64) Entry {
+	if !val.Valid {
+		if l.cfg.nilNull {
+			return l.AddAny(key, nil)
+		}
+		return l
+	}
+	return l.AddAny(key, val.Int64)
+}
+
+// AddNullFloat64 adds the value of a sql.NullFloat64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (l *lEntry) AddNullFloat64(key string, val sql.NullFloat64) Entry {
+	if !val.Valid {
+		if l.cfg.nilNull {
+			return l.AddAny(key, nil)
+		}
+		return l
+	}
+	return l.AddAny(key, val.Float64)
+}
+
+// AddNullBool adds the value of a sql.NullBool to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (l *lEntry) AddNullBool(key string, val sql.NullBool) Entry {
+	if !val.Valid {
+		if l.cfg.nilNull {
+			return l.AddAny(key, nil)
+		}
+		return l
+	}
+	return l.AddBool(key, val.Bool)
+}
+
+// AddKV adds alternating keys and values to the log statement, e.g. AddKV("user", id, "ok", true), like AddAny for
+// each pair. It trades the type safety of the typed methods for brevity. Keys that aren't strings are formatted with
+// fmt.Sprint. If the number of arguments is odd, the last key is added under "kv_warning" as missing its value.
+func (l *lEntry) AddKV(pairs ...interface{}) Entry {
+	return addKV(l, pairs)
+}
+
+// AddBigRat adds an arbitrary-precision rational number to the log statement as string, so that exact values like
+// amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default. nil
+// is added as "<nil>".
+func (l *lEntry) AddBigRat(key string, val *big.Rat) Entry {
+	return l.AddStr(key, formatRat(val, l.cfg.bigPrec))
+}
+
+// AddBigFloat adds an arbitrary-precision floating-point number to the log statement as string, so that exact values
+// like amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default.
+// nil is added as "<nil>".
+func (l *lEntry) AddBigFloat(key string, val *big.Float) Entry {
+	return l.AddStr(key, formatBigFloat(val, l.cfg.bigPrec))
+}
@@ -1,15 +1,54 @@
 package logger
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
+type countHook struct {
+	fired int
+}
+
+func (h *countHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *countHook) Fire(*logrus.Entry) error {
+	h.fired++
+	return nil
+}
+
+func TestFromLogrus(t *testing.T) {
+	var sb strings.Builder
+	h := &countHook{}
+	ll := logrus.New()
+	ll.SetOutput(&sb)
+	ll.SetFormatter(&logrus.JSONFormatter{})
+	ll.SetLevel(logrus.WarnLevel)
+	ll.AddHook(h)
+	l := FromLogrus(ll)
+	l.Info().AddStr("ignored", "val").Flush("message")
+	l.Warn().AddStr("somekey", "someval").Flush("message")
+	s := sb.String()
+	assert.Contains(t, s, `"somekey":"someval"`, "Logger should use the configured formatter")
+	assert.NotContains(t, s, "ignored", "Logger should use the configured level")
+	assert.Equal(t, 1, h.fired, "Logger should fire the configured hooks")
+}
+
 func TestLLog_WithField(t *testing.T) {
 	var sb strings.Builder
 	l := New(&sb, DebugLevel, LogrusBackend).WithField("somekey", "someval")
@@ -19,6 +58,15 @@ func TestLLog_WithField(t *testing.T) {
 	assert.Contains(t, s, "someval", "Log message should contain value")
 }
 
+func TestLLog_WithAny(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend).WithAny("tenant_id", 42)
+	l.Debug().Flush("first")
+	l.Debug().Flush("second")
+	s := sb.String()
+	assert.Equal(t, 2, strings.Count(s, `tenant_id=42`), "Every log message should contain the field")
+}
+
 func TestLLog_Level(t *testing.T) {
 	for _, test := range tests() {
 		var sb strings.Builder
@@ -108,6 +156,25 @@ func TestLLog_Error(t *testing.T) {
 	}
 }
 
+func TestLLog_Fatal(t *testing.T) {
+	for _, test := range tests() {
+		var sb strings.Builder
+		l := New(&sb, test.lvl, LogrusBackend)
+		code := exitCode(func() { l.Fatal().AddAny(test.key, test.val).Flush("Additional Message") })
+		assert.Equal(t, 1, code, "Call to Fatal level should exit with code 1")
+		s := sb.String()
+		if test.lvl >= FatalLevel {
+			msg := fmt.Sprintf("Logger with level %d should print Fatal messages", test.lvl)
+			assert.Contains(t, s, test.key, msg)
+		} else {
+			msg := fmt.Sprintf("Logger with level %d should not print Fatal messages", test.lvl)
+			assert.NotContains(t, s, test.key, msg)
+		}
+	}
+	code := exitCode(func() { New(&strings.Builder{}, DebugLevel, LogrusBackend).Error().Flush("") })
+	assert.Equal(t, -1, code, "Call to Error level should not exit")
+}
+
 func TestLLog_Panic(t *testing.T) {
 	for _, test := range tests() {
 		var sb strings.Builder
@@ -230,3 +297,447 @@ func TestLEntry_AddTime(t *testing.T) {
 	s := sb.String()
 	assert.Contains(t, s, key, "Message should contain key")
 }
+
+func TestLEntry_AddJSONRaw(t *testing.T) {
+	key := "jsonkey"
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddJSONRaw(key, []byte(`{"inner":[1,2]}`)).AddJSONRaw("other", []byte("{no json")).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, key, "Message should contain key")
+	assert.Contains(t, s, "inner", "Message should contain value")
+	assert.Contains(t, s, "other_invalid", "Message should contain invalid key")
+}
+
+func TestLEntry_At(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, InfoLevel, LogrusBackend)
+	l.Debug().AddStr("raised", "val").At(WarnLevel).Flush("")
+	l.Warn().AddStr("lowered", "val").At(DebugLevel).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "raised", "Entry raised above the logger level should be printed")
+	assert.Contains(t, s, `level=warning`, "Entry should be printed at the new level")
+	assert.NotContains(t, s, "lowered", "Entry lowered below the logger level should not be printed")
+}
+
+func TestLEntry_AddCount(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddCount("items", 3, "item", "items").AddCount("files", 1, "file", "files").Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "items=3", "Message should contain count")
+	assert.Contains(t, s, "3 items", "Message should contain plural form")
+	assert.Contains(t, s, "1 file\"", "Message should contain singular form")
+}
+
+func BenchmarkLLog_Info(b *testing.B) {
+	l := New(ioutil.Discard, InfoLevel, LogrusBackend)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info().AddStr("str", "value").AddInt("int", i).AddBool("bool", true).AddDur("dur", time.Second).Flush("message")
+	}
+}
+
+func TestLEntry_AddQuery(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddQuery("SELECT * FROM users WHERE id = ?", []interface{}{42, "a"}, 7, time.Second).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "SELECT * FROM users", "Message should contain statement")
+	assert.Contains(t, s, `db.args="[42,\"a\"]"`, "Message should contain arguments as array")
+	assert.Contains(t, s, "db.rows=7", "Message should contain rows")
+	assert.Contains(t, s, "db.duration=1s", "Message should contain duration")
+}
+
+func TestLEntry_AddTraceparent(t *testing.T) {
+	h := http.Header{}
+	h.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddTraceparent("parent", h).AddTraceparent("missing", http.Header{}).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "parent_trace_id", "Message should contain trace id key")
+	assert.Contains(t, s, "4bf92f3577b34da6a3ce929d0e0e4736", "Message should contain trace id")
+	assert.Contains(t, s, "parent_span_id", "Message should contain span id key")
+	assert.Contains(t, s, "00f067aa0ba902b7", "Message should contain span id")
+	assert.NotContains(t, s, "missing", "Message should not contain missing header")
+}
+
+func TestLEntry_AddMetric(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddMetric("payment.amount", 1299, map[string]string{"currency": "USD"}).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "metric=", "Message should contain key")
+	assert.Contains(t, s, "payment.amount", "Message should contain name")
+	assert.Contains(t, s, "1299", "Message should contain value")
+	assert.Contains(t, s, "USD", "Message should contain tags")
+}
+
+func TestLEntry_AddHex(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddHex("hexkey", []byte{0x00, 0xff, 0x10}).AddHex("empty", nil).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `hexkey=00ff10`, "Message should contain hex value")
+	assert.Contains(t, s, `empty= `, "Message should contain empty value")
+}
+
+func TestLEntry_AddBase64(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddBase64("b64key", []byte("hi??>>")).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `b64key=aGk/Pz4+`, "Message should contain base64 value")
+}
+
+func TestLEntry_AddElapsed(t *testing.T) {
+	key := "elapsedkey"
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddElapsed(key, time.Now().Add(-time.Minute)).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `elapsedkey=1m0`, "Message should contain key")
+}
+
+func TestLEntry_NoTime(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().Flush("")
+	assert.Contains(t, sb.String(), `fields.time`, "Message should contain time by default")
+	sb.Reset()
+	l.Info().NoTime().Flush("")
+	assert.NotContains(t, sb.String(), `fields.time`, "Message should not contain time")
+}
+
+func TestLEntry_AddDurHuman(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddDurHuman("secs", 1200*time.Millisecond).AddDurHuman("mins", 3*time.Minute+4*time.Second).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `secs=1.2s`, "Message should contain seconds")
+	assert.Contains(t, s, `mins=3m04s`, "Message should contain minutes")
+}
+
+func TestLEntry_AddErrN(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	err := errors.Annotate(errors.Annotate(errors.New("asd"), "other err"), "third err")
+	l.Info().AddErrN(err, 1).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "err_stack", "Message should contain error stack")
+	assert.Contains(t, s, "...2 more", "Error stack should be truncated")
+	assert.Contains(t, s, "third err: other err: asd", "Message should contain full error mesage")
+}
+
+func TestLEntry_AddSensitive(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddSensitive("email", "a@b.c").Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `email_hash=d648b243a3e817eaa3309e00e183483f2867baadf522099f0c2121770536b25a`, "Message should contain hash")
+	assert.NotContains(t, s, "a@b.c", "Message should not contain raw value")
+}
+
+func TestLEntry_AddBytesLen(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddBytesLen("size", []byte("secret")).AddBytesLen("nil", nil).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `size=6`, "Message should contain length")
+	assert.Contains(t, s, `nil=0`, "Message should contain length of nil slice")
+	assert.NotContains(t, s, "secret", "Message should not contain content")
+}
+
+func TestLEntry_AddStrLen(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddStrLen("len", "héllo").Flush("")
+	assert.Contains(t, sb.String(), `len=5`, "Message should contain rune count")
+}
+
+func TestLEntry_AddJSONString(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddJSONString("jsonkey", `{"inner":[1,2]}`).AddJSONString("other", "{no json").Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "jsonkey", "Message should contain key")
+	assert.Contains(t, s, "inner", "Message should contain value")
+	assert.Contains(t, s, "other_invalid", "Message should contain invalid key")
+}
+
+func TestLEntry_AddRetry(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Warn().AddRetry(2, 5, 1500*time.Millisecond).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `retry.attempt=2`, "Message should contain attempt")
+	assert.Contains(t, s, `retry.max=5`, "Message should contain max")
+	assert.Contains(t, s, `retry.backoff=1.5s`, "Message should contain backoff")
+}
+
+func TestLEntry_AddDeadline(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	l.Info().AddDeadline("deadline", ctx).AddDeadline("none", context.Background()).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `deadline=`, "Message should contain remaining time")
+	assert.NotContains(t, s, "none", "Context without deadline should add nothing")
+}
+
+func TestLEntry_AddRequest(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	r := httptest.NewRequest("POST", "http://example.com/users?id=1", nil)
+	r.Header.Set("User-Agent", "curl/7.64")
+	r.Header.Set("Authorization", "Bearer secret")
+	l.Info().AddRequest(r).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `http.method=POST`, "Message should contain method")
+	assert.Contains(t, s, `http.path=/users`, "Message should contain path")
+	assert.Contains(t, s, `http.remote_addr="192.0.2.1:1234"`, "Message should contain remote address")
+	assert.Contains(t, s, `http.user_agent=curl/7.64`, "Message should contain user agent")
+	assert.Contains(t, s, `http.host=example.com`, "Message should contain host")
+	assert.NotContains(t, s, "secret", "Message should not contain authorization")
+}
+
+func TestLEntry_AddResponse(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddResponse(503, 42, 1500*time.Millisecond).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `http.status=503`, "Message should contain status")
+	assert.Contains(t, s, `http.status_class=5xx`, "Message should contain status class")
+	assert.Contains(t, s, `http.bytes=42`, "Message should contain size")
+	assert.Contains(t, s, `http.duration=1.5s`, "Message should contain duration")
+}
+
+func TestLEntry_AddSlice(t *testing.T) {
+	type item struct {
+		ID int `json:"id"`
+	}
+	var sb strings.Builder
+	l := FromLogrus(&logrus.Logger{Out: &sb, Formatter: &logrus.JSONFormatter{}, Level: logrus.DebugLevel})
+	l.Info().AddSlice("items", []item{{1}, {2}}).AddSlice("other", 5).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"items":[{"id":1},{"id":2}]`, "Message should contain JSON array")
+	assert.Contains(t, s, `"other":5`, "Message should contain other values")
+}
+
+func TestLEntry_AddPtr(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	b, i, s := true, 42, "val"
+	l.Info().AddBoolPtr("bool", &b).AddIntPtr("int", &i).AddStrPtr("str", &s).Flush("")
+	l.Info().AddBoolPtr("nilbool", nil).AddIntPtr("nilint", nil).AddStrPtr("nilstr", nil).Flush("")
+	out := sb.String()
+	assert.Contains(t, out, `bool=true`, "Message should contain bool")
+	assert.Contains(t, out, `int=42`, "Message should contain int")
+	assert.Contains(t, out, `str=val`, "Message should contain string")
+	assert.NotContains(t, out, "nil", "Nil pointers should be omitted")
+}
+
+func TestLEntry_AddThreshold(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddIntThreshold("rows", 5000, 1000, "large").AddDurThreshold("dur", time.Millisecond, time.Second, "slow").Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `rows=5000`, "Message should contain value")
+	assert.Contains(t, s, `large=true`, "Message should contain flag above threshold")
+	assert.Contains(t, s, "dur", "Message should contain value")
+	assert.NotContains(t, s, "slow", "Message should not contain flag below threshold")
+}
+
+func TestLEntry_Discard(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddStr("key", "val").Discard()
+	assert.Empty(t, sb.String(), "Discarded entry should not be written")
+}
+
+func TestLEntry_AddMoney(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddMoney("price", 1299, "usd").Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "price.amount_minor=1299", "Amount should be stored in minor units")
+	assert.Contains(t, s, "price.currency=USD", "Currency should be stored in upper case")
+	assert.Contains(t, s, "price.display=\"$12.99\"", "Formatted amount should be stored")
+	assert.NotContains(t, s, "currency_unknown", "Known currencies should not be flagged")
+
+	sb.Reset()
+	l.Info().AddMoney("price", 1299, "").Flush("")
+	s = sb.String()
+	assert.Contains(t, s, "price.display=12.99", "An empty currency should not add spaces")
+	assert.Contains(t, s, "price.currency_unknown=true", "An empty currency should be flagged")
+	sb.Reset()
+	l.Info().AddMoney("price", 1299, "xyz").Flush("")
+	assert.Contains(t, sb.String(), "price.currency_unknown=true", "Unlisted currencies should be flagged")
+}
+
+func TestLEntry_AddErrChain(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	err := &wrapErr{"outer", errors.Trace(fmt.Errorf("root"))}
+	l.Info().AddErrChain("chain", err).AddErrChain("nil", nil).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `chain="outer: root"`, "Entry should contain the message")
+	assert.Contains(t, s, "chain_depth=2", "Entry should contain the number of wrapped errors")
+	assert.Contains(t, s, `chain_types="[*logger.wrapErr *errors.Err *errors.errorString]"`,
+		"Entry should contain the types of the chain")
+	assert.NotContains(t, s, "nil", "Nil errors should add nothing")
+}
+
+func TestLEntry_AddInterval(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	start := time.Date(2019, 3, 1, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	l.Info().AddInterval("window", start, start.Add(-90*time.Minute)).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "window.start=\"2019-03-01 08:00:00 +0000 UTC\"", "Start should be stored in UTC")
+	assert.Contains(t, s, "window.end=\"2019-03-01 06:30:00 +0000 UTC\"", "End should be stored in UTC")
+	assert.Contains(t, s, "window.duration=-1h30m0s", "Duration should be negative if end is before start")
+}
+
+func TestLEntry_AddValidationErrors(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	errs := map[string]string{"email": "must not be empty", "name": "too long"}
+	l.Info().AddValidationErrors("validation", errs).AddValidationErrors("none", nil).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "validation=\"map[email:must not be empty name:too long]\"", "Errors should be added as map")
+	assert.NotContains(t, s, "none", "Empty maps should add nothing")
+}
+
+func TestLEntry_Bytes(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, WarnLevel, LogrusBackend)
+	e := l.Info().AddStr("key", "val")
+	b, err := e.Bytes()
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "val", "Bytes should contain the fields")
+	assert.True(t, strings.HasSuffix(string(b), "\n"), "Bytes should be formatted like a written entry")
+	assert.Empty(t, sb.String(), "Bytes should not write the entry")
+	e.At(ErrorLevel).Flush("message")
+	assert.Contains(t, sb.String(), "val", "Entry should be flushed after Bytes")
+}
+
+func TestLEntry_AddGeo(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddGeo("location", 52.52, 13.405).AddGeo("off", 91, 0).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `location="{52.52 13.405}"`, "Entry should contain the coordinates")
+	assert.NotContains(t, s, "location_invalid", "Valid coordinates should not be flagged")
+	assert.Contains(t, s, "off_invalid=true", "Invalid coordinates should be flagged")
+}
+
+func TestLEntry_Retention(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().Retention("audit").Flush("")
+	assert.Contains(t, sb.String(), "retention=audit", "Entry should contain the retention class")
+
+	sb.Reset()
+	l = New(&sb, DebugLevel, LogrusBackend, DefaultRetention("hot"))
+	l.Info().Flush("")
+	assert.Contains(t, sb.String(), "retention=hot", "Entry should contain the default retention class")
+	sb.Reset()
+	l.Info().Retention("audit").Flush("")
+	assert.Contains(t, sb.String(), "retention=audit", "Retention should override the default")
+	assert.NotContains(t, sb.String(), "hot", "Default should not be added if the entry sets a class")
+}
+
+func TestLEntry_AddDiff(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddDiff("timeout", 10, 30).AddDiff("hosts", []string{"a"}, nil).
+		AddDiff("unchanged", []string{"a"}, []string{"a"}).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `timeout="{10 30}"`, "Entry should contain the old and the new value")
+	assert.NotContains(t, s, "unchanged", "Equal values should add nothing")
+}
+
+func TestLEntry_AddRuntime(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddRuntime().Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "go.version="+runtime.Version(), "Entry should contain the Go version")
+	assert.Contains(t, s, "go.os="+runtime.GOOS, "Entry should contain the operating system")
+	assert.Contains(t, s, "go.arch="+runtime.GOARCH, "Entry should contain the architecture")
+	assert.Contains(t, s, "go.goroutines=", "Entry should contain the number of goroutines")
+	assert.Contains(t, s, "go.heap_mb=", "Entry should contain the heap")
+}
+
+func TestLEntry_AddForm(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	form := url.Values{"user": {"alice"}, "tags": {"a", "b"}, "password": {"secret"}}
+	l.Info().AddForm(form, "user", "tags", "missing").Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "form.user=alice", "Listed fields should be added")
+	assert.Contains(t, s, "form.tags=\"a,b\"", "Multiple values should be joined with commas")
+	assert.NotContains(t, s, "secret", "Fields that aren't listed should never be added")
+	assert.NotContains(t, s, "missing", "Missing fields should be skipped")
+}
+
+func TestLEntry_AddBackoff(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	e, d := l.Warn().AddBackoff(3, time.Second, time.Minute)
+	e.Flush("")
+	s := sb.String()
+	assert.True(t, d >= 2*time.Second && d <= 4*time.Second, "Wait should be 4s with jitter, got %s", d)
+	assert.Contains(t, s, `backoff.attempt=3`, "Message should contain attempt")
+	assert.Contains(t, s, `backoff.wait=`, "Message should contain wait")
+}
+
+func TestLEntry_AddLabels(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddLabels(map[string]string{"team": "core", "app-name": "api"}).AddLabels(nil).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "labels=\"map[app-name:api team:core]\"", "Labels should be added as nested object")
+	assert.Contains(t, s, "labels_invalid=\"[\\\"app-name\\\"]\"", "Invalid label names should be flagged")
+}
+
+func TestLEntry_AddPercent(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddPercent("ratio", 1).AddPercent("over", 3).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `ratio=1 ratio_pct="100.0%"`, "Entry should contain the fraction and the percentage")
+	assert.Contains(t, s, `over=1 over_invalid=true over_pct="100.0%"`, "Fractions above 1 should be clamped")
+}
+
+func TestLEntry_AddNull(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend, NilAsNull())
+	l.Info().AddNullBool("bool", sql.NullBool{Bool: true, Valid: true}).AddNullInt64("int", sql.NullInt64{}).Flush("")
+	out := sb.String()
+	assert.Contains(t, out, "bool=true", "Valid values should be added")
+	assert.Contains(t, out, `int="<nil>"`, "NULL values should be null with NilAsNull")
+}
+
+func TestLEntry_AddKV(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddKV("action", "login", "ok", true).Flush("")
+	out := sb.String()
+	assert.Contains(t, out, "action=login ", "Pairs should be added")
+	assert.Contains(t, out, "ok=true", "Pairs should be added")
+	assert.NotContains(t, out, "kv_warning", "An even count should not be flagged")
+}
+
+func TestLEntry_AddBig(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend)
+	l.Info().AddBigRat("rat", big.NewRat(7, 2)).AddBigFloat("float", big.NewFloat(2)).Flush("")
+	out := sb.String()
+	assert.Contains(t, out, "rat=7/2", "Values should be added as strings")
+	assert.Contains(t, out, "float=2", "Values should be added as strings")
+}
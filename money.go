@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// currency describes how amounts of an ISO 4217 currency are formatted
+type currency struct {
+	// exponent is the number of digits of the minor unit, e.g. 2 for cents
+	exponent int
+	// symbol is written before the amount. Currencies without symbol are written with their code after the amount.
+	symbol string
+}
+
+// currencies holds the ISO 4217 exponents of common currencies. Unknown currencies are formatted with exponent 2.
+var currencies = map[string]currency{
+	"AUD": {2, "A$"},
+	"BHD": {3, ""},
+	"BRL": {2, "R$"},
+	"CAD": {2, "CA$"},
+	"CHF": {2, ""},
+	"CLP": {0, ""},
+	"CNY": {2, "CN¥"},
+	"CZK": {2, ""},
+	"DKK": {2, ""},
+	"EUR": {2, "€"},
+	"GBP": {2, "£"},
+	"HKD": {2, "HK$"},
+	"HUF": {2, ""},
+	"IDR": {2, ""},
+	"ILS": {2, "₪"},
+	"INR": {2, "₹"},
+	"ISK": {0, ""},
+	"JOD": {3, ""},
+	"JPY": {0, "¥"},
+	"KRW": {0, "₩"},
+	"KWD": {3, ""},
+	"MXN": {2, "MX$"},
+	"NOK": {2, ""},
+	"NZD": {2, "NZ$"},
+	"OMR": {3, ""},
+	"PLN": {2, ""},
+	"SEK": {2, ""},
+	"SGD": {2, "S$"},
+	"TND": {3, ""},
+	"TRY": {2, ""},
+	"TWD": {2, "NT$"},
+	"UGX": {0, ""},
+	"USD": {2, "$"},
+	"VND": {0, "₫"},
+	"ZAR": {2, ""},
+}
+
+// formatMoney formats an amount in minor units of the currency code, e.g. "$12.99" for 1299 USD, "¥500" for 500 JPY
+// or "1.500 KWD" for 1500 KWD. It reports whether the code is known. Unknown codes are formatted with exponent 2 and
+// the code after the amount, an empty code is left out.
+func formatMoney(minorUnits int64, code string) (string, bool) {
+	cur, ok := currencies[code]
+	if !ok {
+		cur = currency{exponent: 2}
+	}
+	neg := minorUnits < 0
+	// format the absolute value as unsigned, -math.MinInt64 doesn't fit into int64
+	abs := uint64(minorUnits)
+	if neg {
+		abs = -abs
+	}
+	digits := strconv.FormatUint(abs, 10)
+	if cur.exponent > 0 {
+		if len(digits) <= cur.exponent {
+			digits = strings.Repeat("0", cur.exponent-len(digits)+1) + digits
+		}
+		i := len(digits) - cur.exponent
+		digits = digits[:i] + "." + digits[i:]
+	}
+	var s string
+	switch {
+	case cur.symbol != "":
+		s = cur.symbol + digits
+	case code != "":
+		s = digits + " " + code
+	default:
+		s = digits
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s, ok
+}
+
+// formatRat returns r with prec decimal places, rounded half away from zero, or as exact fraction like "1/3" or "5"
+// if prec is negative. A nil r is "<nil>".
+func formatRat(r *big.Rat, prec int) string {
+	switch {
+	case r == nil:
+		return "<nil>"
+	case prec < 0:
+		return r.RatString()
+	}
+	return r.FloatString(prec)
+}
+
+// formatBigFloat returns f with prec decimal places, or with the fewest digits that represent f exactly if prec is
+// negative. A nil f is "<nil>".
+func formatBigFloat(f *big.Float, prec int) string {
+	switch {
+	case f == nil:
+		return "<nil>"
+	case prec < 0:
+		return f.Text('g', -1)
+	}
+	return f.Text('f', prec)
+}
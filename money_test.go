@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		minor    int64
+		currency string
+		want     string
+	}{
+		{1299, "USD", "$12.99"},
+		{5, "EUR", "€0.05"},
+		{0, "GBP", "£0.00"},
+		{-1299, "USD", "-$12.99"},
+		{500, "JPY", "¥500"},
+		{1500, "KWD", "1.500 KWD"},
+		{1299, "CHF", "12.99 CHF"},
+		{1299, "XYZ", "12.99 XYZ"},
+		{-1299, "", "-12.99"},
+		{math.MinInt64, "USD", "-$92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		got, _ := formatMoney(tt.minor, tt.currency)
+		assert.Equal(t, tt.want, got, "%d %s", tt.minor, tt.currency)
+	}
+	_, known := formatMoney(1299, "USD")
+	assert.True(t, known, "Listed currencies should be known")
+	_, known = formatMoney(1299, "XYZ")
+	assert.False(t, known, "Unlisted currencies should be unknown")
+	_, known = formatMoney(1299, "")
+	assert.False(t, known, "An empty currency should be unknown")
+}
+
+func TestFormatRat(t *testing.T) {
+	assert.Equal(t, "1/3", formatRat(big.NewRat(1, 3), -1), "Rationals should be exact by default")
+	assert.Equal(t, "5", formatRat(big.NewRat(10, 2), -1), "Integers should be written without denominator")
+	assert.Equal(t, "0.33", formatRat(big.NewRat(1, 3), 2))
+	assert.Equal(t, "-0.67", formatRat(big.NewRat(-2, 3), 2), "Rationals should be rounded half away from zero")
+	assert.Equal(t, "1", formatRat(big.NewRat(1, 2), 0))
+	assert.Equal(t, "<nil>", formatRat(nil, 2))
+}
+
+func TestFormatBigFloat(t *testing.T) {
+	f, _ := new(big.Float).SetPrec(200).SetString("12345678901234567890.125")
+	assert.Equal(t, "1.2345678901234567890125e+19", formatBigFloat(f, -1), "Floats should be exact by default")
+	assert.Equal(t, "12345678901234567890.12", formatBigFloat(f, 2))
+	assert.Equal(t, "1.5", formatBigFloat(big.NewFloat(1.5), -1))
+	assert.Equal(t, "<nil>", formatBigFloat(nil, -1))
+}
@@ -1,6 +1,12 @@
 package logger
 
 import (
+	"context"
+	"database/sql"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -10,16 +16,68 @@ func NewMulti(ls ...Logger) Logger {
 	return &mLog{ls: ls}
 }
 
+// NewTee is an alias of NewMulti. Each entry is created on all loggers, fields are added to all of them and Flush
+// flushes them together, so loggers with independent configurations can be used as a single one.
+func NewTee(ls ...Logger) Logger {
+	return NewMulti(ls...)
+}
+
 type mLog struct {
 	ls []Logger
 }
 
+var _ Logger = (*mLog)(nil)
+
 // WithField returns a new Logger that always logs the specified field
 func (m *mLog) WithField(key, value string) Logger {
+	ls := make([]Logger, len(m.ls))
 	for i := range m.ls {
-		m.ls[i] = m.ls[i].WithField(key, value)
+		ls[i] = m.ls[i].WithField(key, value)
 	}
-	return m
+	return &mLog{ls: ls}
+}
+
+// WithAny returns a new Logger that always logs the specified field. Unlike WithField, the value can be of any type
+func (m *mLog) WithAny(key string, value interface{}) Logger {
+	ls := make([]Logger, len(m.ls))
+	for i := range m.ls {
+		ls[i] = m.ls[i].WithAny(key, value)
+	}
+	return &mLog{ls: ls}
+}
+
+// WithMessagePrefix returns a new Logger that prefixes the message of each entry with prefix and ": ", e.g. for
+// consumers that grep for a tag instead of parsing fields. Empty messages are prefixed, too.
+func (m *mLog) WithMessagePrefix(prefix string) Logger {
+	return newPrefix(m, prefix)
+}
+
+// WithLevelRemap returns a new Logger that changes the level of its entries according to remap on Flush, e.g.
+// {ErrorLevel: WarnLevel} to keep the errors of a noisy dependency from triggering alerts. The level check of the
+// logger applies to the level before remapping: an entry is only written if its original level is enabled, and
+// then it's written at the new level. An entry remapped to a level below the level of the logger is dropped by the
+// logger, though. Entries at fatal and panic level are never remapped, and no level is remapped to them.
+func (m *mLog) WithLevelRemap(remap map[Level]Level) Logger {
+	return newRemap(m, remap)
+}
+
+// WithLabels returns a new Logger that adds labels to each entry like AddLabels. Labels of nested calls and of
+// AddLabels are merged into one object, later labels take precedence.
+func (m *mLog) WithLabels(labels map[string]string) Logger {
+	return newLabels(m, labels)
+}
+
+// WithEnvironment returns a new Logger that always logs the deployment environment env like "prod" under the key
+// "env", e.g. the result of DetectEnvironment. An empty env is logged as "unknown".
+func (m *mLog) WithEnvironment(env string) Logger {
+	return m.WithField("env", environment(env))
+}
+
+// WithCorrelation returns a new Logger that logs the correlation id under the key "correlation_id", e.g. the result
+// of ExtractCorrelation for an incoming request. InjectCorrelation sets the id in the headers of outgoing requests.
+// An empty id returns the logger unchanged.
+func (m *mLog) WithCorrelation(id string) Logger {
+	return newCorrelation(m, id)
 }
 
 // Level creates a new Entry with the specified Level
@@ -67,7 +125,8 @@ func (m *mLog) Error() Entry {
 	return &e
 }
 
-// Fatal creates a new Entry with level Fatal. Executing a log at fatal level exits the application with exit code 1.
+// Fatal creates a new Entry with level Fatal. Executing a log at fatal level exits the application with exit code 1,
+// or the code set with FatalExitCode.
 func (m *mLog) Fatal() Entry {
 	e := mEntry{make([]Entry, len(m.ls))}
 	for i := range m.ls {
@@ -76,6 +135,60 @@ func (m *mLog) Fatal() Entry {
 	return &e
 }
 
+// PipeWriter returns a writer that logs every line written to it as an entry at the specified level. The entries
+// have the field "stream" set to stream. The last line is logged on Close even if it doesn't end with a newline.
+func (m *mLog) PipeWriter(lvl Level, stream string) io.WriteCloser {
+	return newLineWriter(m, lvl, stream)
+}
+
+// Operation logs the start of an operation at debug level and returns a function that logs its end together with
+// the duration of the operation. The end is logged at info level, or at error level if a non-nil error is passed.
+// Use it as "defer done(err)". Both entries have the same id under the key "operation_id", see WithIDGenerator.
+func (m *mLog) Operation(name string) func(err error) {
+	return operation(m, name)
+}
+
+// NewTimer returns a Timer that starts now, e.g. "t := l.NewTimer(); parse(); t.Mark("parse")". Add the phases to an
+// entry with Timer.AddTo.
+func (m *mLog) NewTimer() *Timer {
+	return newTimer(m.config())
+}
+
+// StartHeartbeat logs msg at info level every interval with the time since StartHeartbeat was called under the key
+// "uptime" and the number of goroutines under the key "goroutines", e.g. to show that a service is alive. It returns
+// a function that stops the heartbeat and waits until an entry that is being written is done, calling it more than
+// once is safe. Each call starts an independent heartbeat. If interval isn't greater than zero, nothing is logged
+// and stop does nothing.
+func (m *mLog) StartHeartbeat(interval time.Duration, msg string) (stop func()) {
+	return heartbeat(m, interval, msg)
+}
+
+// Go runs fn in a new goroutine that recovers from a panic of fn and logs it at error level with the panic value
+// under the key "panic" and the stack under the key "stack", e.g. for background workers, whose panics would crash the
+// process without a log. By default, the panic is swallowed and the goroutine ends. With the option RepanicInGo, the
+// goroutine panics again after logging, which crashes the process.
+func (m *mLog) Go(fn func()) {
+	goSafe(m, fn)
+}
+
+// Sync flushes buffered entries to the destination without closing the logger. For loggers created by New with the
+// zerolog or gelf backend, the writer is synced if it has a method "Sync() error", like *os.File. Sync does nothing
+// for the logrus backend, for channel loggers and for loggers passed to FromZerolog.
+func (m *mLog) Sync() error {
+	var err error
+	for _, l := range m.ls {
+		if e := l.Sync(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// config returns nil, the loggers of a multi logger have their own options
+func (m *mLog) config() *config {
+	return nil
+}
+
 // Panic creates a new Entry with level Panic. Executing a log at panic level will call panic().
 func (m *mLog) Panic() Entry {
 	e := mEntry{make([]Entry, len(m.ls))}
@@ -89,6 +202,8 @@ type mEntry struct {
 	es []Entry
 }
 
+var _ Entry = (*mEntry)(nil)
+
 // Flush writes the entry as a single log statement. Optionally, a message can be added which will
 // be included in the final log entry
 func (m *mEntry) Flush(msg string) {
@@ -109,6 +224,42 @@ func (m *mEntry) Flush(msg string) {
 	}
 }
 
+// Bytes returns the entry in the format of each logger, one after another. It returns the first error.
+func (m *mEntry) Bytes() ([]byte, error) {
+	var res []byte
+	for _, e := range m.es {
+		b, err := e.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, b...)
+	}
+	return res, nil
+}
+
+// Discard abandons the entry without writing it. The entry must not be used afterwards.
+func (m *mEntry) Discard() {
+	for _, e := range m.es {
+		e.Discard()
+	}
+}
+
+// At changes the level of the entry. The level is evaluated when the entry is flushed.
+func (m *mEntry) At(lvl Level) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].At(lvl)
+	}
+	return m
+}
+
+// NoTime removes the time field that is added to each entry automatically, e.g. to add the time of an event instead.
+func (m *mEntry) NoTime() Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].NoTime()
+	}
+	return m
+}
+
 // AddFields adds a range of fields to the log statement
 func (m *mEntry) AddFields(fields map[string]interface{}) Entry {
 	for i := range m.es {
@@ -118,7 +269,7 @@ func (m *mEntry) AddFields(fields map[string]interface{}) Entry {
 }
 
 // AddErr adds an error to the log statement. The error will have the key "err". An error stack will be included
-// under the key "err_stack"
+// under the key "err_stack". Use the option ErrorKeys to change the keys.
 func (m *mEntry) AddErr(err error) Entry {
 	for i := range m.es {
 		m.es[i] = m.es[i].AddErr(err)
@@ -181,3 +332,423 @@ func (m *mEntry) AddAny(key string, val interface{}) Entry {
 	}
 	return m
 }
+
+// AddJSONRaw adds pre-serialized JSON to the log statement without encoding it again. If raw is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (m *mEntry) AddJSONRaw(key string, raw []byte) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddJSONRaw(key, raw)
+	}
+	return m
+}
+
+// AddCount adds a count to the log statement. The number is stored under key, a human readable form like "3 items"
+// is stored under the key "${key}_human"
+func (m *mEntry) AddCount(key string, n int, singular, plural string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddCount(key, n, singular, plural)
+	}
+	return m
+}
+
+// AddQuery adds a database query to the log statement. The statement, its arguments as JSON array, the number of
+// rows and the duration are stored under the keys "db.sql", "db.args", "db.rows" and "db.duration"
+func (m *mEntry) AddQuery(sql string, args []interface{}, rows int, dur time.Duration) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddQuery(sql, args, rows, dur)
+	}
+	return m
+}
+
+// AddTraceparent adds the trace id and span id of the W3C traceparent header in headers under the keys
+// "${key}_trace_id" and "${key}_span_id". A missing or malformed header adds nothing.
+func (m *mEntry) AddTraceparent(key string, headers http.Header) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddTraceparent(key, headers)
+	}
+	return m
+}
+
+// AddMetric adds a metric to the log statement. Name, value and tags are stored as a nested object under the key
+// "metric", so that log collectors can route the statement to a metrics sink.
+func (m *mEntry) AddMetric(name string, value float64, tags map[string]string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddMetric(name, value, tags)
+	}
+	return m
+}
+
+// AddHex adds a byte slice to the log statement, encoded as hex string.
+func (m *mEntry) AddHex(key string, val []byte) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddHex(key, val)
+	}
+	return m
+}
+
+// AddBase64 adds a byte slice to the log statement, encoded as base64 string with standard encoding.
+func (m *mEntry) AddBase64(key string, val []byte) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddBase64(key, val)
+	}
+	return m
+}
+
+// AddElapsed adds the duration since the specified time to the log statement.
+func (m *mEntry) AddElapsed(key string, since time.Time) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddElapsed(key, since)
+	}
+	return m
+}
+
+// AddDurHuman adds a duration to the log statement as human readable string like "1.2s" or "3m04s". Use AddDur for
+// values that should be queryable.
+func (m *mEntry) AddDurHuman(key string, d time.Duration) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddDurHuman(key, d)
+	}
+	return m
+}
+
+// AddErrN adds an error to the log statement like AddErr, but the error stack under the key "err_stack" is
+// truncated to the first maxFrames frames. The error message is kept intact.
+func (m *mEntry) AddErrN(err error, maxFrames int) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddErrN(err, maxFrames)
+	}
+	return m
+}
+
+// AddSensitive adds the hex encoded SHA-256 hash of val under the key "${key}_hash" to the log statement. The raw
+// value is never logged. Use the options SensitiveSalt and SensitiveHashLen to configure the hash.
+func (m *mEntry) AddSensitive(key string, val string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddSensitive(key, val)
+	}
+	return m
+}
+
+// AddBytesLen adds the length of a byte slice to the log statement instead of its content. A nil slice has length 0.
+func (m *mEntry) AddBytesLen(key string, val []byte) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddBytesLen(key, val)
+	}
+	return m
+}
+
+// AddStrLen adds the number of runes of a string to the log statement instead of the string itself.
+func (m *mEntry) AddStrLen(key string, val string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddStrLen(key, val)
+	}
+	return m
+}
+
+// AddJSONString adds a JSON encoded string to the log statement without encoding it again. If s is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (m *mEntry) AddJSONString(key, s string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddJSONString(key, s)
+	}
+	return m
+}
+
+// AddRetry adds the state of a retry loop to the log statement. The attempt, the maximum number of attempts and the
+// backoff before the next attempt are stored under the keys "retry.attempt", "retry.max" and "retry.backoff"
+func (m *mEntry) AddRetry(attempt, max int, backoff time.Duration) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddRetry(attempt, max, backoff)
+	}
+	return m
+}
+
+// AddDeadline adds the time remaining until the deadline of ctx to the log statement. It is negative if the deadline
+// has passed. If ctx has no deadline, nothing is added.
+func (m *mEntry) AddDeadline(key string, ctx context.Context) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddDeadline(key, ctx)
+	}
+	return m
+}
+
+// AddRequest adds the method, path, remote address, user agent and host of r to the log statement under the keys
+// "http.method", "http.path", "http.remote_addr", "http.user_agent" and "http.host". Other headers are never logged.
+func (m *mEntry) AddRequest(r *http.Request) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddRequest(r)
+	}
+	return m
+}
+
+// AddResponse adds the status code, the size of the body and the duration of an HTTP response to the log statement
+// under the keys "http.status", "http.bytes" and "http.duration". The class of the status like "5xx" is stored under
+// the key "http.status_class".
+func (m *mEntry) AddResponse(status, bytes int, dur time.Duration) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddResponse(status, bytes, dur)
+	}
+	return m
+}
+
+// AddSlice adds a slice or an array of any element type to the log statement as JSON array. Other values, or slices
+// that can't be encoded as JSON, are added like with AddAny.
+func (m *mEntry) AddSlice(key string, vals interface{}) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddSlice(key, vals)
+	}
+	return m
+}
+
+// AddBoolPtr adds the value of a bool pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (m *mEntry) AddBoolPtr(key string, val *bool) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddBoolPtr(key, val)
+	}
+	return m
+}
+
+// AddIntPtr adds the value of an integer pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (m *mEntry) AddIntPtr(key string, val *int) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddIntPtr(key, val)
+	}
+	return m
+}
+
+// AddStrPtr adds the value of a string pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (m *mEntry) AddStrPtr(key string, val *string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddStrPtr(key, val)
+	}
+	return m
+}
+
+// AddCaller adds the location that called it as "file:line" under the key "caller" and the calling function under
+// the key "caller_func" to the log statement. Unlike the option ReportCaller, it only applies to this entry.
+func (m *mEntry) AddCaller() Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddCaller()
+	}
+	return m
+}
+
+// AddCallerSkip adds the caller like AddCaller, but skips n additional frames, e.g. in helpers that wrap an Entry.
+func (m *mEntry) AddCallerSkip(n int) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddCallerSkip(n)
+	}
+	return m
+}
+
+// AddIntThreshold adds a value to the log statement like AddInt and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (m *mEntry) AddIntThreshold(key string, val, threshold int, flagKey string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddIntThreshold(key, val, threshold, flagKey)
+	}
+	return m
+}
+
+// AddDurThreshold adds a value to the log statement like AddDur and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (m *mEntry) AddDurThreshold(key string, val, threshold time.Duration, flagKey string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddDurThreshold(key, val, threshold, flagKey)
+	}
+	return m
+}
+
+// AddMoney adds an amount of money in minor units of an ISO 4217 currency, e.g. cents for "USD", to the log statement.
+// The amount and the currency are stored under the keys "${key}.amount_minor" and "${key}.currency", the formatted
+// amount like "$12.99" under the key "${key}.display". Unknown currencies, including an empty code, are formatted
+// with two decimal places and flagged with true under the key "${key}.currency_unknown".
+func (m *mEntry) AddMoney(key string, minorUnits int64, currency string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddMoney(key, minorUnits, currency)
+	}
+	return m
+}
+
+// AddErrChain adds the message of an error under key, the number of errors it wraps under the key "${key}_depth" and
+// the type names of the errors in its chain, starting with err, under the key "${key}_types" to the log statement.
+// Both the standard library's wrapping and juju/errors are followed. A nil error adds nothing.
+func (m *mEntry) AddErrChain(key string, err error) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddErrChain(key, err)
+	}
+	return m
+}
+
+// AddInterval adds a time window to the log statement. Start and end are stored in UTC under the keys "${key}.start"
+// and "${key}.end", the duration between them under the key "${key}.duration". If end is before start, the duration
+// is negative.
+func (m *mEntry) AddInterval(key string, start, end time.Time) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddInterval(key, start, end)
+	}
+	return m
+}
+
+// AddValidationErrors adds the results of a failed validation to the log statement as nested object that maps each
+// invalid field to its error message, e.g. {"email": "must not be empty"}. A nil or empty map adds nothing.
+func (m *mEntry) AddValidationErrors(key string, errs map[string]string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddValidationErrors(key, errs)
+	}
+	return m
+}
+
+// AddGeo adds coordinates to the log statement as nested object with the keys "lat" and "lon", which Elasticsearch
+// maps as geo_point. If the latitude isn't within [-90, 90] or the longitude isn't within [-180, 180], the
+// coordinates are still added and "${key}_invalid" is set to true.
+func (m *mEntry) AddGeo(key string, lat, lon float64) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddGeo(key, lat, lon)
+	}
+	return m
+}
+
+// Retention sets the retention class of the entry under the key "retention", e.g. "short", "long" or "audit", so
+// that the log pipeline can route it to a storage tier. It overrides the class set with DefaultRetention.
+func (m *mEntry) Retention(class string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].Retention(class)
+	}
+	return m
+}
+
+// AddDiff adds the old and the new value of a change to the log statement as nested object with the keys "old" and
+// "new". If both values are equal according to reflect.DeepEqual, nothing is added.
+func (m *mEntry) AddDiff(key string, oldVal, newVal interface{}) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddDiff(key, oldVal, newVal)
+	}
+	return m
+}
+
+// AddRuntime adds diagnostics of the Go runtime to the log statement: the Go version, the operating system and the
+// architecture under the keys "go.version", "go.os" and "go.arch", the number of goroutines under the key
+// "go.goroutines" and the allocated heap in MiB under the key "go.heap_mb". Reading the heap stops the world, so use
+// it for rare entries like crash reports, not in hot paths.
+func (m *mEntry) AddRuntime() Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddRuntime()
+	}
+	return m
+}
+
+// AddForm adds the values of the listed form fields to the log statement under the keys "form.${name}". Multiple
+// values of a field are joined with commas. Fields that aren't listed are never added, e.g. passwords, and listed
+// fields missing from values are skipped.
+func (m *mEntry) AddForm(values url.Values, keys ...string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddForm(values, keys...)
+	}
+	return m
+}
+
+// AddBackoff computes the wait before the next attempt of a retry loop and adds it to the log statement together with
+// the attempt under the keys "backoff.wait" and "backoff.attempt". The wait doubles with each attempt, starting at
+// base for attempt 1, and is capped at max. Half of it is random jitter, so that clients don't retry in lockstep.
+// The wait is returned so that the caller can sleep on it.
+func (m *mEntry) AddBackoff(attempt int, base, max time.Duration) (Entry, time.Duration) {
+	// the wait is computed once, so that all loggers log the same wait
+	d := backoff(attempt, base, max)
+	for i := range m.es {
+		m.es[i] = m.es[i].AddInt("backoff.attempt", attempt).AddDur("backoff.wait", d)
+	}
+	return m, d
+}
+
+// AddLabels adds a set of labels like environment, team or service to the log statement as nested object under the
+// key "labels". Label names that don't match the Prometheus label name syntax [a-zA-Z_][a-zA-Z0-9_]* are still added
+// and listed under the key "labels_invalid". Labels of a logger created by WithLabels are merged with labels, with
+// labels taking precedence. A nil or empty map adds nothing.
+func (m *mEntry) AddLabels(labels map[string]string) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddLabels(labels)
+	}
+	return m
+}
+
+// AddPercent adds a fraction like a success ratio to the log statement under key and the same value as percentage
+// string with one decimal under "${key}_pct", e.g. 0.873 as "87.3%", so that dashboards don't mix fractions and
+// percentages. A fraction outside [0, 1] is clamped and "${key}_invalid" is set to true, NaN is clamped to 0.
+func (m *mEntry) AddPercent(key string, fraction float64) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddPercent(key, fraction)
+	}
+	return m
+}
+
+// AddNullString adds the value of a sql.NullString to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (m *mEntry) AddNullString(key string, val sql.NullString) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddNullString(key, val)
+	}
+	return m
+}
+
+// AddNullInt64 adds the value of a sql.NullInt64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (m *mEntry) AddNullInt64(key string, val sql.NullInt64) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddNullInt64(key, val)
+	}
+	return m
+}
+
+// AddNullFloat64 adds the value of a sql.NullFloat64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (m *mEntry) AddNullFloat64(key string, val sql.NullFloat64) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddNullFloat64(key, val)
+	}
+	return m
+}
+
+// AddNullBool adds the value of a sql.NullBool to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (m *mEntry) AddNullBool(key string, val sql.NullBool) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddNullBool(key, val)
+	}
+	return m
+}
+
+// AddKV adds alternating keys and values to the log statement, e.g. AddKV("user", id, "ok", true), like AddAny for
+// each pair. It trades the type safety of the typed methods for brevity. Keys that aren't strings are formatted with
+// fmt.Sprint. If the number of arguments is odd, the last key is added under "kv_warning" as missing its value.
+func (m *mEntry) AddKV(pairs ...interface{}) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddKV(pairs...)
+	}
+	return m
+}
+
+// AddBigRat adds an arbitrary-precision rational number to the log statement as string, so that exact values like
+// amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default. nil
+// is added as "<nil>".
+func (m *mEntry) AddBigRat(key string, val *big.Rat) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddBigRat(key, val)
+	}
+	return m
+}
+
+// AddBigFloat adds an arbitrary-precision floating-point number to the log statement as string, so that exact values
+// like amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default.
+// nil is added as "<nil>".
+func (m *mEntry) AddBigFloat(key string, val *big.Float) Entry {
+	for i := range m.es {
+		m.es[i] = m.es[i].AddBigFloat(key, val)
+	}
+	return m
+}
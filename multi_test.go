@@ -1,7 +1,11 @@
 package logger
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -20,7 +24,7 @@ func multiLogger(lvl Level) (Logger, []strings.Builder) {
 
 func TestMLog_WithField(t *testing.T) {
 	l, sbs := multiLogger(DebugLevel)
-	l.WithField("somekey", "someval")
+	l = l.WithField("somekey", "someval")
 	l.Debug().AddStr("otherkey", "otherval").Flush("message")
 	for _, sb := range sbs {
 		s := sb.String()
@@ -29,6 +33,17 @@ func TestMLog_WithField(t *testing.T) {
 	}
 }
 
+func TestMLog_WithAny(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l = l.WithAny("tenant_id", 42)
+	l.Debug().Flush("message")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "tenant_id", "Log message should contain key")
+		assert.Contains(t, s, "42", "Log message should contain value")
+	}
+}
+
 func TestMLog_Level(t *testing.T) {
 	for _, test := range tests() {
 		l, sbs := multiLogger(DebugLevel)
@@ -124,6 +139,24 @@ func TestMLog_Error(t *testing.T) {
 	}
 }
 
+func TestMLog_Fatal(t *testing.T) {
+	for _, test := range tests() {
+		l, sbs := multiLogger(test.lvl)
+		code := exitCode(func() { l.Fatal().AddAny(test.key, test.val).Flush("Additional Message") })
+		assert.Equal(t, 1, code, "Call to Fatal level should exit with code 1")
+		for _, sb := range sbs {
+			s := sb.String()
+			if test.lvl >= FatalLevel {
+				msg := fmt.Sprintf("Logger with level %d should print Fatal messages", test.lvl)
+				assert.Contains(t, s, test.key, msg)
+			} else {
+				msg := fmt.Sprintf("Logger with level %d should not print Fatal messages", test.lvl)
+				assert.NotContains(t, s, test.key, msg)
+			}
+		}
+	}
+}
+
 func TestMLog_Panic(t *testing.T) {
 	for _, test := range tests() {
 		l, sbs := multiLogger(test.lvl)
@@ -256,3 +289,297 @@ func TestMEntry_AddTime(t *testing.T) {
 		assert.Contains(t, s, key, "Message should contain key")
 	}
 }
+
+func TestMEntry_AddJSONRaw(t *testing.T) {
+	key := "jsonkey"
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddJSONRaw(key, []byte(`{"inner":[1,2]}`)).AddJSONRaw("other", []byte("{no json")).Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, key, "Message should contain key")
+		assert.Contains(t, s, "inner", "Message should contain value")
+		assert.Contains(t, s, "other_invalid", "Message should contain invalid key")
+	}
+}
+
+func TestMEntry_At(t *testing.T) {
+	l, sbs := multiLogger(InfoLevel)
+	l.Debug().AddStr("raised", "val").At(WarnLevel).Flush("")
+	l.Warn().AddStr("lowered", "val").At(DebugLevel).Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "raised", "Entry raised above the logger level should be printed")
+		assert.NotContains(t, s, "lowered", "Entry lowered below the logger level should not be printed")
+	}
+}
+
+func TestMEntry_AddCount(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddCount("items", 3, "item", "items").Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "items_human", "Message should contain key")
+		assert.Contains(t, s, "3 items", "Message should contain plural form")
+	}
+}
+
+func TestMEntry_AddQuery(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddQuery("SELECT 1", nil, 1, time.Second).Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "SELECT 1", "Message should contain statement")
+		assert.Contains(t, s, "db.args", "Message should contain arguments")
+		assert.Contains(t, s, "db.rows", "Message should contain rows")
+		assert.Contains(t, s, "db.duration", "Message should contain duration")
+	}
+}
+
+func TestMEntry_AddTraceparent(t *testing.T) {
+	h := http.Header{}
+	h.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddTraceparent("parent", h).Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "4bf92f3577b34da6a3ce929d0e0e4736", "Message should contain trace id")
+		assert.Contains(t, s, "00f067aa0ba902b7", "Message should contain span id")
+	}
+}
+
+func TestMEntry_AddMetric(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddMetric("payment.amount", 1299, nil).Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "metric", "Message should contain key")
+		assert.Contains(t, s, "payment.amount", "Message should contain name")
+	}
+}
+
+func TestMEntry_AddHex(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddHex("hexkey", []byte{0x00, 0xff, 0x10}).Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "hexkey", "Message should contain key")
+		assert.Contains(t, s, "00ff10", "Message should contain value")
+	}
+}
+
+func TestMEntry_AddBase64(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddBase64("b64key", []byte("hi??>>")).Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "b64key", "Message should contain key")
+		assert.Contains(t, s, "aGk/Pz4+", "Message should contain value")
+	}
+}
+
+func TestMLog_PipeWriter(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	w := l.PipeWriter(InfoLevel, "child.stderr")
+	fmt.Fprint(w, "line\n")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "child.stderr", "Message should contain stream")
+		assert.Contains(t, s, "line", "Message should contain line")
+	}
+}
+
+func TestMEntry_AddElapsed(t *testing.T) {
+	key := "elapsedkey"
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddElapsed(key, time.Now()).Flush("")
+	for _, sb := range sbs {
+		assert.Contains(t, sb.String(), key, "Message should contain key")
+	}
+}
+
+func TestMLog_Operation(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Operation("import")(nil)
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "operation started", "Start of the operation should be logged")
+		assert.Contains(t, s, "operation finished", "End of the operation should be logged")
+	}
+}
+
+func TestMEntry_NoTime(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().NoTime().Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.NotContains(t, s, `"time"`, "Message should not contain time")
+		assert.NotContains(t, s, "fields.time", "Message should not contain time")
+	}
+}
+
+func TestMEntry_AddDurHuman(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddDurHuman("secs", 1200*time.Millisecond).Flush("")
+	for _, sb := range sbs {
+		assert.Contains(t, sb.String(), "1.2s", "Message should contain duration")
+	}
+}
+
+func TestMEntry_AddErrN(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddErrN(errors.Annotate(errors.New("asd"), "other err"), 0).Flush("")
+	for _, sb := range sbs {
+		assert.Contains(t, sb.String(), "...2 more", "Error stack should be truncated")
+	}
+}
+
+func TestMLog_WithField_Parent(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.WithField("somekey", "someval")
+	l.Debug().Flush("message")
+	for _, sb := range sbs {
+		assert.NotContains(t, sb.String(), "somekey", "Parent logger should not be modified")
+	}
+}
+
+func TestMLog_Sync(t *testing.T) {
+	var sb0, sb1 syncBuffer
+	sb0.err = errors.New("sync failed")
+	l := NewMulti(New(&sb0, DebugLevel, ZeroLogBackend), New(&sb1, DebugLevel, GelfBackend))
+	assert.EqualError(t, l.Sync(), "sync failed", "First error should be returned")
+	assert.Equal(t, 1, sb1.syncs, "All loggers should be synced")
+}
+
+func TestMEntry_AddSensitive(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddSensitive("email", "a@b.c").Flush("")
+	for _, sb := range sbs {
+		assert.Contains(t, sb.String(), "email_hash", "Message should contain hash")
+		assert.NotContains(t, sb.String(), "a@b.c", "Message should not contain raw value")
+	}
+}
+
+func TestMEntry_AddBytesLen(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddBytesLen("size", []byte("secret")).AddStrLen("len", "héllo").Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "6", "Message should contain length")
+		assert.Contains(t, s, "5", "Message should contain rune count")
+		assert.NotContains(t, s, "secret", "Message should not contain content")
+	}
+}
+
+func TestNewTee(t *testing.T) {
+	var sb0, sb1 strings.Builder
+	l := NewTee(New(&sb0, DebugLevel, ZeroLogBackend), New(&sb1, WarnLevel, LogrusBackend))
+	l = l.WithField("request_id", "req1")
+	l.Info().AddStr("infokey", "val").Flush("")
+	l.Warn().AddStr("warnkey", "val").Flush("")
+	assert.Contains(t, sb0.String(), "infokey", "First logger should log info")
+	assert.Contains(t, sb0.String(), "warnkey", "First logger should log warn")
+	assert.NotContains(t, sb1.String(), "infokey", "Second logger should not log info")
+	assert.Contains(t, sb1.String(), "warnkey", "Second logger should log warn")
+	assert.Contains(t, sb1.String(), "request_id=req1", "Fields should be added to all loggers")
+}
+
+func TestMEntry_AddJSONString(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddJSONString("jsonkey", `{"inner":[1,2]}`).AddJSONString("other", "{no json").Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "inner", "Message should contain value")
+		assert.Contains(t, s, "other_invalid", "Message should contain invalid key")
+	}
+}
+
+func TestMEntry_AddRetry(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Warn().AddRetry(2, 5, 1500*time.Millisecond).Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "retry.attempt", "Message should contain attempt")
+		assert.Contains(t, s, "retry.max", "Message should contain max")
+		assert.Contains(t, s, "retry.backoff", "Message should contain backoff")
+	}
+}
+
+func TestMEntry_AddDeadline(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	l.Info().AddDeadline("deadline", ctx).Flush("")
+	for _, sb := range sbs {
+		assert.Contains(t, sb.String(), "deadline", "Message should contain remaining time")
+	}
+}
+
+func TestMEntry_AddRequest(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddRequest(httptest.NewRequest("POST", "/users", nil)).Flush("")
+	for _, sb := range sbs {
+		s := sb.String()
+		assert.Contains(t, s, "http.method", "Message should contain method")
+		assert.Contains(t, s, "/users", "Message should contain path")
+	}
+}
+
+func TestMEntry_AddResponse(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddResponse(404, 42, time.Millisecond).Flush("")
+	for _, sb := range sbs {
+		assert.Contains(t, sb.String(), "4xx", "Message should contain status class")
+	}
+}
+
+func TestMEntry_AddSlice(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddSlice("items", []string{"first", "second"}).Flush("")
+	for _, sb := range sbs {
+		assert.Contains(t, sb.String(), "second", "Message should contain slice")
+	}
+}
+
+func TestMEntry_AddPtr(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	i := 42
+	l.Info().AddIntPtr("int", &i).AddStrPtr("nilstr", nil).Flush("")
+	for _, sb := range sbs {
+		assert.Contains(t, sb.String(), "42", "Message should contain int")
+		assert.NotContains(t, sb.String(), "nilstr", "Nil pointers should be omitted")
+	}
+}
+
+func TestMEntry_AddCaller(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	_, file, line, _ := runtime.Caller(0)
+	l.Info().AddCaller().Flush("")
+	for _, sb := range sbs {
+		assert.Contains(t, sb.String(), fmt.Sprintf("%s:%d", file, line+1), "Multi logger should skip its own frames")
+	}
+}
+
+func TestMEntry_AddThreshold(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddDurThreshold("dur", time.Minute, time.Second, "slow").Flush("")
+	for _, sb := range sbs {
+		assert.Contains(t, sb.String(), "slow", "Message should contain flag above threshold")
+	}
+}
+
+func TestMEntry_Discard(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.Info().AddStr("key", "val").Discard()
+	for _, sb := range sbs {
+		assert.Empty(t, sb.String(), "Discarded entry should not be written")
+	}
+}
+
+func TestMEntry_AddBackoff(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 2)
+	m := NewMulti(l, l)
+	e, d := m.Warn().AddBackoff(2, time.Second, time.Minute)
+	e.Flush("")
+	assert.Equal(t, d, (<-ch).Fields["backoff.wait"], "All loggers should log the returned wait")
+	assert.Equal(t, d, (<-ch).Fields["backoff.wait"], "All loggers should log the returned wait")
+}
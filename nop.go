@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// nopEntry is an Entry that is never written, e.g. because it has been dropped by sampling. All methods do nothing.
+type nopEntry struct{}
+
+var _ Entry = nopEntry{}
+
+func (n nopEntry) Flush(string) {}
+
+func (n nopEntry) Discard() {}
+
+func (n nopEntry) Bytes() ([]byte, error) { return nil, nil }
+
+func (n nopEntry) At(Level) Entry { return n }
+
+func (n nopEntry) NoTime() Entry { return n }
+
+func (n nopEntry) AddFields(map[string]interface{}) Entry { return n }
+
+func (n nopEntry) AddErr(error) Entry { return n }
+
+func (n nopEntry) AddError(string, error) Entry { return n }
+
+func (n nopEntry) AddBool(string, bool) Entry { return n }
+
+func (n nopEntry) AddInt(string, int) Entry { return n }
+
+func (n nopEntry) AddStr(string, string) Entry { return n }
+
+func (n nopEntry) AddTime(string, time.Time) Entry { return n }
+
+func (n nopEntry) AddDur(string, time.Duration) Entry { return n }
+
+func (n nopEntry) AddAny(string, interface{}) Entry { return n }
+
+func (n nopEntry) AddJSONRaw(string, []byte) Entry { return n }
+
+func (n nopEntry) AddCount(string, int, string, string) Entry { return n }
+
+func (n nopEntry) AddQuery(string, []interface{}, int, time.Duration) Entry { return n }
+
+func (n nopEntry) AddTraceparent(string, http.Header) Entry { return n }
+
+func (n nopEntry) AddMetric(string, float64, map[string]string) Entry { return n }
+
+func (n nopEntry) AddHex(string, []byte) Entry { return n }
+
+func (n nopEntry) AddBase64(string, []byte) Entry { return n }
+
+func (n nopEntry) AddElapsed(string, time.Time) Entry { return n }
+
+func (n nopEntry) AddDurHuman(string, time.Duration) Entry { return n }
+
+func (n nopEntry) AddErrN(error, int) Entry { return n }
+
+func (n nopEntry) AddSensitive(string, string) Entry { return n }
+
+func (n nopEntry) AddBytesLen(string, []byte) Entry { return n }
+
+func (n nopEntry) AddStrLen(string, string) Entry { return n }
+
+func (n nopEntry) AddJSONString(string, string) Entry { return n }
+
+func (n nopEntry) AddRetry(int, int, time.Duration) Entry { return n }
+
+func (n nopEntry) AddDeadline(string, context.Context) Entry { return n }
+
+func (n nopEntry) AddRequest(*http.Request) Entry { return n }
+
+func (n nopEntry) AddResponse(int, int, time.Duration) Entry { return n }
+
+func (n nopEntry) AddSlice(string, interface{}) Entry { return n }
+
+func (n nopEntry) AddBoolPtr(string, *bool) Entry { return n }
+
+func (n nopEntry) AddIntPtr(string, *int) Entry { return n }
+
+func (n nopEntry) AddStrPtr(string, *string) Entry { return n }
+
+func (n nopEntry) AddCaller() Entry { return n }
+
+func (n nopEntry) AddCallerSkip(int) Entry { return n }
+
+func (n nopEntry) AddIntThreshold(string, int, int, string) Entry { return n }
+
+func (n nopEntry) AddDurThreshold(string, time.Duration, time.Duration, string) Entry { return n }
+
+func (n nopEntry) AddMoney(key string, minorUnits int64, currency string) Entry { return n }
+
+func (n nopEntry) AddErrChain(key string, err error) Entry { return n }
+
+func (n nopEntry) AddInterval(key string, start, end time.Time) Entry { return n }
+
+func (n nopEntry) AddValidationErrors(key string, errs map[string]string) Entry { return n }
+
+func (n nopEntry) AddGeo(key string, lat, lon float64) Entry { return n }
+
+func (n nopEntry) Retention(class string) Entry { return n }
+
+func (n nopEntry) AddDiff(key string, oldVal, newVal interface{}) Entry { return n }
+
+func (n nopEntry) AddRuntime() Entry { return n }
+
+func (n nopEntry) AddForm(values url.Values, keys ...string) Entry { return n }
+
+func (n nopEntry) AddBackoff(attempt int, base, max time.Duration) (Entry, time.Duration) {
+	return n, backoff(attempt, base, max)
+}
+
+func (n nopEntry) AddLabels(labels map[string]string) Entry { return n }
+
+func (n nopEntry) AddPercent(key string, fraction float64) Entry { return n }
+
+func (n nopEntry) AddNullString(string, sql.NullString) Entry { return n }
+
+func (n nopEntry) AddNullInt64(string, sql.NullInt64) Entry { return n }
+
+func (n nopEntry) AddNullFloat64(string, sql.NullFloat64) Entry { return n }
+
+func (n nopEntry) AddNullBool(string, sql.NullBool) Entry { return n }
+
+func (n nopEntry) AddKV(...interface{}) Entry { return n }
+
+func (n nopEntry) AddBigRat(string, *big.Rat) Entry { return n }
+
+func (n nopEntry) AddBigFloat(string, *big.Float) Entry { return n }
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"reflect"
+	"sync"
+)
+
+// TestSink records the entries of a logger created by NewObserved. It is safe for concurrent use.
+type TestSink struct {
+	mu      sync.Mutex
+	entries []CapturedEntry
+}
+
+func (s *TestSink) deliver(e CapturedEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, e)
+}
+
+// Entries returns all recorded entries in the order they were flushed
+func (s *TestSink) Entries() []CapturedEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]CapturedEntry, len(s.entries))
+	copy(res, s.entries)
+	return res
+}
+
+// Len returns the number of recorded entries
+func (s *TestSink) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Reset removes all recorded entries
+func (s *TestSink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+}
+
+// FilterMessage returns the recorded entries with message msg
+func (s *TestSink) FilterMessage(msg string) []CapturedEntry {
+	return s.filter(func(e CapturedEntry) bool { return e.Message == msg })
+}
+
+// FilterField returns the recorded entries that have the field key with value val. Values are compared with
+// reflect.DeepEqual, so val has to have the type the field was added with, e.g. int for AddInt or []int for a slice
+// added with AddAny.
+func (s *TestSink) FilterField(key string, val interface{}) []CapturedEntry {
+	return s.filter(func(e CapturedEntry) bool {
+		v, ok := e.Fields[key]
+		return ok && reflect.DeepEqual(v, val)
+	})
+}
+
+func (s *TestSink) filter(f func(e CapturedEntry) bool) []CapturedEntry {
+	var res []CapturedEntry
+	for _, e := range s.Entries() {
+		if f(e) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// NewObserved returns a logger that writes to backing and records every entry in the returned TestSink, so tests
+// can assert on the entries while the output of backing is still visible. Entries are recorded at all levels with
+// their fields as added, e.g. an int for AddInt, independent of the level of backing.
+func NewObserved(backing Logger, opts ...Option) (Logger, *TestSink) {
+	s := &TestSink{}
+	c := newConfig(opts)
+	return NewTee(backing, c.wrap(&cLog{sink: s, level: DebugLevel, cfg: c}, DebugLevel)), s
+}
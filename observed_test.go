@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewObserved(t *testing.T) {
+	var sb strings.Builder
+	l, sink := NewObserved(New(&sb, InfoLevel, ZeroLogBackend))
+	l = l.WithField("request_id", "req1")
+	l.Debug().Flush("debug")
+	l.Info().AddInt("status", 200).AddDur("dur", time.Second).Flush("done")
+	l.Warn().AddInt("status", 500).Flush("done")
+
+	s := sb.String()
+	assert.NotContains(t, s, "debug", "Backing logger should keep its level")
+	assert.Equal(t, 2, strings.Count(s, "done"), "Backing logger should write the entries")
+
+	assert.Equal(t, 3, sink.Len(), "All entries should be recorded")
+	es := sink.FilterMessage("done")
+	assert.Len(t, es, 2, "Entries should be filtered by message")
+	assert.Equal(t, Level(InfoLevel), es[0].Level, "Entries should be recorded in order")
+	assert.Equal(t, "req1", es[0].Fields["request_id"], "Fields of the logger should be recorded")
+	assert.Equal(t, time.Second, es[0].Fields["dur"], "Fields should keep their type")
+	assert.Len(t, sink.FilterField("status", 500), 1, "Entries should be filtered by field")
+
+	l.Info().AddAny("ids", []int{1, 2}).Flush("slice")
+	assert.NotPanics(t, func() { sink.FilterField("ids", []int{1, 2}) }, "Uncomparable values should not panic")
+	assert.Len(t, sink.FilterField("ids", []int{1, 2}), 1, "Slices should be compared by their elements")
+	assert.Empty(t, sink.FilterField("ids", []int{1}), "Different slices should not match")
+
+	sink.Reset()
+	assert.Empty(t, sink.Entries(), "Reset should remove all entries")
+}
@@ -0,0 +1,18 @@
+package logger
+
+// operation logs the start of the operation name at debug level and returns a function that logs its end
+func operation(l Logger, name string) func(err error) {
+	c := configOf(l)
+	start := timeNow(c)
+	id := newID(c)
+	l.Debug().AddStr("operation", name).AddStr("operation_id", id).Flush("operation started")
+	return func(err error) {
+		d := timeNow(c).Sub(start)
+		if err != nil {
+			l.Error().AddStr("operation", name).AddStr("operation_id", id).AddDur("duration", d).AddErr(err).
+				Flush("operation failed")
+			return
+		}
+		l.Info().AddStr("operation", name).AddStr("operation_id", id).AddDur("duration", d).Flush("operation finished")
+	}
+}
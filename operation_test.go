@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperation(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl)
+		done := l.Operation("import")
+		s := sb.String()
+		assert.Contains(t, s, "operation started", "Start of the operation should be logged")
+		assert.Contains(t, s, "import", "Start should contain the operation")
+		sb.Reset()
+		done(nil)
+		s = sb.String()
+		assert.Contains(t, s, "operation finished", "End of the operation should be logged")
+		assert.Contains(t, s, "import", "End should contain the operation")
+		assert.Contains(t, s, "duration", "End should contain the duration")
+
+		sb.Reset()
+		l.Operation("export")(errors.New("disk full"))
+		s = sb.String()
+		assert.Contains(t, s, "operation failed", "Failure of the operation should be logged")
+		assert.Contains(t, s, "disk full", "Failure should contain the error")
+	}
+}
+
+func TestOperation_ID(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		n := 0
+		gen := func() string {
+			n++
+			return fmt.Sprintf("op-%d", n)
+		}
+		l := New(&sb, DebugLevel, impl, WithIDGenerator(gen))
+		l.Operation("import")(nil)
+		l.Operation("export")(errors.New("disk full"))
+		lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+		if !assert.Len(t, lines, 4, "Start and end of each operation should be logged") {
+			continue
+		}
+		assert.Contains(t, lines[0], "op-1", "Start should contain the id")
+		assert.Contains(t, lines[1], "op-1", "End should contain the id of the start")
+		assert.Contains(t, lines[2], "op-2", "Each operation should have its own id")
+		assert.Contains(t, lines[3], "op-2", "Failure should contain the id of the start")
+	}
+
+	var sb strings.Builder
+	New(&sb, DebugLevel, ZeroLogBackend).Operation("import")(nil)
+	assert.Regexp(t, `"operation_id":"[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}"`, sb.String(),
+		"Id should default to a random UUID")
+}
@@ -0,0 +1,734 @@
+package logger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Option configures optional behaviour of a Logger
+type Option func(*config)
+
+// config holds the options of a logger. It is shared by all loggers derived from the same logger.
+type config struct {
+	// debugSampleRate is the probability with which entries at debug level are kept
+	debugSampleRate float64
+	// levelSampling is the probability with which entries are kept by level, see LevelSampling
+	levelSampling map[Level]float64
+	// reportCaller adds the caller of Flush to each entry
+	reportCaller bool
+	// goroutineID adds the id of the goroutine that called Flush to each entry
+	goroutineID bool
+	// timeFormat is the encoding of the automatic time field
+	timeFormat TimeFormat
+	// timeCapture is the moment at which the time field is taken, see WithTimeCapture
+	timeCapture TimeCapture
+	// ctxExtractor returns the fields FromContext adds to the logger
+	ctxExtractor func(context.Context) map[string]interface{}
+	// flagBelow adds the flag of AddIntThreshold and AddDurThreshold as false below the threshold
+	flagBelow bool
+	// formatter replaces the formatter of the logrus backend
+	formatter logrus.Formatter
+	// retention is the retention class of entries that don't set one, see DefaultRetention
+	retention string
+	// hooks are added to the logrus backend
+	hooks []logrus.Hook
+	// nilNull logs nil pointers of AddBoolPtr, AddIntPtr and AddStrPtr and NULL values of AddNullString etc. as null
+	// instead of omitting them
+	nilNull bool
+	// callerSkip is the number of frames to skip after leaving this package when resolving the caller
+	callerSkip int
+	// omitEmpty drops fields with zero values
+	omitEmpty bool
+	// guardReuse makes entries panic when they are used after Flush
+	guardReuse bool
+	// burstWindow summarizes repeated entries, see BurstSummary. 0 disables it.
+	burstWindow time.Duration
+	// nestKey is the key of the object that holds the fields, see NestFields. "" disables it.
+	nestKey string
+	// cardinalityLimit is the number of distinct values of cardinalityKeys, see CardinalityGuard. 0 disables it.
+	cardinalityLimit int
+	cardinalityKeys  []string
+	// level is the level of the logger, set by wrap
+	level Level
+	// byteRate limits the bytes written per second, see WithByteRateLimit. 0 disables it.
+	byteRate int
+	// collapseRepeats collapses consecutive identical entries, see CollapseRepeats
+	collapseRepeats bool
+	// maxInt is the largest absolute value of integers that are written as numbers. 0 writes all integers as numbers.
+	maxInt uint64
+	// salt is prepended to sensitive values before hashing
+	salt string
+	// hashLen is the number of hex characters of sensitive hashes that are kept. 0 keeps the whole hash.
+	hashLen int
+	// console wraps the writer passed to New for consoles, see ConsoleWriter
+	console bool
+	// exitCode is the code the application exits with after an entry at fatal level
+	exitCode int
+	// levelFunc returns the level of the logger on each Flush, see WithLevelFunc
+	levelFunc func() Level
+	// repanicGo makes goroutines started by Go panic again after logging a panic
+	repanicGo bool
+	// buildInfo adds the fields of WithBuildInfo to the logger
+	buildInfo bool
+	// bigPrec is the number of decimal places of AddBigRat and AddBigFloat, -1 for exact values
+	bigPrec int
+	// scrub are the patterns that are replaced in string values, see ScrubPatterns
+	scrub []*regexp.Regexp
+	// onPanic is called with the fields of an entry at panic level before the panic, see OnPanic
+	onPanic func(fields map[string]interface{})
+	// forwardedFor makes AddRequest use the header X-Forwarded-For for the remote address
+	forwardedFor bool
+	// errKey and errStackKey are the keys of AddErr and AddErrN
+	errKey      string
+	errStackKey string
+	// ecs makes the zerolog backend write the base fields of the Elastic Common Schema, see NewECS
+	ecs bool
+	// ecsLevel is the level of the logger created by NewECS
+	ecsLevel Level
+	// clock returns the current time, see WithClock. nil uses time.Now.
+	clock func() time.Time
+	// selfDiag makes New log the effective configuration, see WithSelfDiagnostic
+	selfDiag bool
+	// idGen generates the ids of Operation and NewCloudEvents, see WithIDGenerator. nil generates UUIDs.
+	idGen func() string
+	// audit adds a sequence number to each entry, see AuditMode
+	audit bool
+	// auditStart is the sequence number of the first entry in audit mode
+	auditStart uint64
+	// auditChain adds a hash chain to each entry in audit mode
+	auditChain bool
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{debugSampleRate: 1, exitCode: 1, errKey: "err", errStackKey: "err_stack", auditStart: 1, bigPrec: -1}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// sampled adds the field "sample_rate" to e, an entry at level lvl that has been kept by sample, if entries at
+// level lvl are sampled. Consumers counting entries can scale the count by 1/sample_rate.
+func (c *config) sampled(e Entry, lvl Level) Entry {
+	if p := c.sampleRate(lvl); p < 1 {
+		return e.AddAny("sample_rate", p)
+	}
+	return e
+}
+
+// sampleRate returns the probability that an entry at level lvl is kept
+func (c *config) sampleRate(lvl Level) float64 {
+	p := 1.0
+	if lvl == DebugLevel {
+		p = c.debugSampleRate
+	}
+	if c.levelSampling != nil {
+		if q, ok := c.levelSampling[lvl]; ok {
+			p = q
+		}
+	}
+	return p
+}
+
+// sample decides whether an entry at level lvl should be kept
+func (c *config) sample(lvl Level) bool {
+	p := c.sampleRate(lvl)
+	if p >= 1 {
+		return true
+	}
+	if rand.Float64() < p {
+		return true
+	}
+	drop()
+	return false
+}
+
+// nonEmpty returns fs without zero values if the option OmitEmpty is set
+func (c *config) nonEmpty(fs map[string]interface{}) map[string]interface{} {
+	if !c.omitEmpty {
+		return fs
+	}
+	res := make(map[string]interface{}, len(fs))
+	for k, v := range fs {
+		if !isZero(v) {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// fields returns fs with the options OmitEmpty and QuoteLargeInts applied
+func (c *config) fields(fs map[string]interface{}) map[string]interface{} {
+	fs = c.nonEmpty(fs)
+	if c.maxInt == 0 {
+		return fs
+	}
+	res := make(map[string]interface{}, len(fs))
+	for k, v := range fs {
+		res[k] = c.quote(v)
+	}
+	return res
+}
+
+// quote returns val as string if it is an integer exceeding the threshold of QuoteLargeInts and val otherwise
+func (c *config) quote(val interface{}) interface{} {
+	if c.maxInt == 0 {
+		return val
+	}
+	if s, ok := largeInt(val, c.maxInt); ok {
+		return s
+	}
+	return val
+}
+
+// hash returns the hex encoded SHA-256 hash of val with the salt of SensitiveSalt, truncated to SensitiveHashLen
+func (c *config) hash(val string) string {
+	h := sha256.New()
+	h.Write([]byte(c.salt))
+	h.Write([]byte(val))
+	s := hex.EncodeToString(h.Sum(nil))
+	if c.hashLen > 0 && c.hashLen < len(s) {
+		s = s[:c.hashLen]
+	}
+	return s
+}
+
+// TimeFormat is the encoding of the time field that is added to each entry automatically
+type TimeFormat int
+
+const (
+	// DefaultTime encodes the time like the backend does by default: zerolog writes Unix seconds, logrus formats the
+	// time with its formatter
+	DefaultTime TimeFormat = iota
+	// EpochMillis encodes the time as milliseconds since the Unix epoch
+	EpochMillis
+	// EpochNanos encodes the time as nanoseconds since the Unix epoch
+	EpochNanos
+)
+
+// WithTimeFormat sets the encoding of the time field that is added to each entry automatically. It applies to the
+// zerolog and logrus backends, the timestamp of GELF is always in seconds as required by the format.
+func WithTimeFormat(f TimeFormat) Option {
+	return func(c *config) {
+		c.timeFormat = f
+	}
+}
+
+// TimeCapture is the moment at which the time field that is added to each entry automatically is taken
+type TimeCapture int
+
+const (
+	// TimeAtCreation takes the time when the entry is created by Level, Debug, Info etc., which is when the event
+	// happened if the entry is built before a long operation and flushed after it. This is the default.
+	TimeAtCreation TimeCapture = iota
+	// TimeAtFlush takes the time when the entry is flushed, which is when the entry is written, e.g. for audit logs
+	// whose timestamps must be in the order of the entries.
+	TimeAtFlush
+)
+
+// WithTimeCapture sets the moment at which the time field that is added to each entry automatically is taken. The
+// default is TimeAtCreation. Both are the same for entries that are flushed right after they have been created.
+func WithTimeCapture(tc TimeCapture) Option {
+	return func(c *config) {
+		c.timeCapture = tc
+	}
+}
+
+// stamp returns the time of an entry created at created
+func (c *config) stamp(created time.Time) time.Time {
+	if c.timeCapture == TimeAtFlush {
+		return timeNow(c)
+	}
+	return created
+}
+
+// epoch returns t as integer in the format of WithTimeFormat. It returns false for DefaultTime.
+func (c *config) epoch(t time.Time) (int64, bool) {
+	switch c.timeFormat {
+	case EpochMillis:
+		return t.UnixNano() / int64(time.Millisecond), true
+	case EpochNanos:
+		return t.UnixNano(), true
+	}
+	return 0, false
+}
+
+// now returns the current time in the format of WithTimeFormat for backends that encode time.Time themselves
+func (c *config) now() interface{} {
+	t := timeNow(c)
+	if ts, ok := c.epoch(t); ok {
+		return ts
+	}
+	return t
+}
+
+// DebugSampleRate keeps each entry at debug level with probability p, where p is between 0.0 and 1.0. The decision
+// is made when the entry is created, so fields are never added to dropped entries. Other levels are not affected. Kept
+// entries have the field "sample_rate" set to p if p is below 1, so that consumers can scale counts by 1/p.
+func DebugSampleRate(p float64) Option {
+	if p < 0 {
+		p = 0
+	}
+	return func(c *config) {
+		c.debugSampleRate = p
+	}
+}
+
+// LevelSampling keeps each entry with the probability p[lvl] of its level, where the probabilities are between 0.0 and
+// 1.0, e.g. {DebugLevel: 0.01, InfoLevel: 0.5} to reduce the volume of verbose levels. Levels that are missing are
+// always kept, a probability for debug level overrides DebugSampleRate. Like with DebugSampleRate, the decision is
+// made when the entry is created, and kept entries of sampled levels have the field "sample_rate". Entries at fatal and
+// panic level are never dropped.
+func LevelSampling(p map[Level]float64) Option {
+	// copy the map, so that later changes by the caller don't race with logging
+	m := make(map[Level]float64, len(p))
+	for lvl, q := range p {
+		if lvl == FatalLevel || lvl == PanicLevel {
+			continue
+		}
+		if q < 0 {
+			q = 0
+		}
+		m[lvl] = q
+	}
+	return func(c *config) {
+		c.levelSampling = m
+	}
+}
+
+// ReportCaller adds the location that flushed an entry as "file:line" under the key "caller". By default, the caller
+// is the first function outside of this package, no matter how many of this package's functions are in between.
+func ReportCaller() Option {
+	return func(c *config) {
+		c.reportCaller = true
+	}
+}
+
+// WithGoroutineID adds the id of the goroutine that flushed an entry under the key "goroutine", to tell apart the
+// entries of concurrent workers. Reading the id takes about a microsecond per entry.
+func WithGoroutineID() Option {
+	return func(c *config) {
+		c.goroutineID = true
+	}
+}
+
+// NilAsNull makes AddBoolPtr, AddIntPtr and AddStrPtr log nil pointers as null, and AddNullString, AddNullInt64,
+// AddNullFloat64 and AddNullBool NULL values. By default, the field is omitted.
+func NilAsNull() Option {
+	return func(c *config) {
+		c.nilNull = true
+	}
+}
+
+// WithCallerSkip skips n additional frames when resolving the caller for ReportCaller. Use it in packages that wrap a
+// Logger, so that the caller of the wrapper is reported instead of the wrapper itself.
+func WithCallerSkip(n int) Option {
+	return func(c *config) {
+		c.callerSkip = n
+	}
+}
+
+// OmitEmpty drops fields with zero values (empty string, 0, false, zero time or duration) added with AddStr, AddInt,
+// AddBool, AddTime, AddDur and AddFields. Values added with AddAny are always logged, use it for zero values that are
+// meaningful, e.g. a count of 0.
+func OmitEmpty() Option {
+	return func(c *config) {
+		c.omitEmpty = true
+	}
+}
+
+// GuardReuse makes entries panic when any of their methods is called after Flush. Reusing a flushed entry, e.g. one
+// captured in a closure, corrupts the log output. The guard is meant to find such bugs during development and tests,
+// it adds a little overhead to every call.
+func GuardReuse() Option {
+	return func(c *config) {
+		c.guardReuse = true
+	}
+}
+
+// BurstSummary writes only the first of repeated entries with the same level and message, e.g. a flood of identical
+// errors during an outage. When no such entry has been flushed for window, a summary "...and N more in the last X"
+// with the fields "burst_message" and "burst_count" is written at the same level. Only the fields of the first entry
+// are written. Entries at fatal and panic level are never summarized.
+func BurstSummary(window time.Duration) Option {
+	return func(c *config) {
+		c.burstWindow = window
+	}
+}
+
+// CollapseRepeats writes consecutive identical entries only once, like syslog does for a component stuck in a loop.
+// Entries are identical if they have the same level, message and fields, added with the same methods in the same
+// order. Repeats are counted instead of written. The count is written as "last message repeated N times" with the
+// field "repeat_count" when a different entry is flushed or 30 seconds after the first repeat. The state is shared by
+// the loggers derived with WithField and WithAny. Entries at fatal and panic level are never collapsed.
+func CollapseRepeats() Option {
+	return func(c *config) {
+		c.collapseRepeats = true
+	}
+}
+
+// WithByteRateLimit limits the size of the entries written per second to bytesPerSec, e.g. to cap the costs of log
+// ingestion that is billed by volume during a flood of entries. The limit is a token bucket that holds the bytes of
+// one second. Entries that don't fit are dropped, their number is logged under the key "ratelimit_dropped" with the
+// next entry that is written. Entries below error level have to leave a reserve in the bucket for more severe
+// entries: entries at warn level a quarter of the capacity, entries at lower levels half of it. Entries at fatal and
+// panic level are never dropped. The size of an entry is the length of its formatted fields and its message, which
+// means each entry is formatted twice, see Entry.Bytes.
+func WithByteRateLimit(bytesPerSec int) Option {
+	return func(c *config) {
+		c.byteRate = bytesPerSec
+	}
+}
+
+// NestFields moves all fields of an entry into a nested object under key when the entry is written, so that only
+// "time", "level", "msg" and "message" remain at the top level, e.g. for log schemas that reserve top level names.
+// A field named "level" then can't be confused with the level of the entry. It applies to entries that are written
+// as JSON objects by the zerolog backend and by the logrus backend with a JSON formatter, and to fields added by
+// options like WithGoroutineID. It doesn't apply to the gelf backend, as GELF doesn't allow nested additional fields,
+// nor to AuditMode, whose fields stay at the top level.
+func NestFields(key string) Option {
+	return func(c *config) {
+		c.nestKey = key
+	}
+}
+
+// CardinalityGuard tracks the distinct values of the fields with the listed keys, e.g. a user id or a full URL, which
+// can degrade the indexes of a log store. When a key has more than limit distinct values, a warning "field exceeds
+// cardinality limit" with the fields "cardinality_key" and "cardinality_limit" is logged once, and the key isn't
+// tracked anymore. At most limit values are kept per key. Values added with WithField, WithAny, AddStr, AddInt, AddAny
+// and AddFields are tracked, compared by their fmt.Sprint representation. The fields are still written.
+func CardinalityGuard(limit int, keys ...string) Option {
+	return func(c *config) {
+		c.cardinalityLimit = limit
+		c.cardinalityKeys = keys
+	}
+}
+
+// WithIDGenerator sets the function that generates the correlation ids of Operation and the event ids of
+// NewCloudEvents, e.g. to use ULIDs that sort by time or a shorter scheme. The default generates random UUIDs (version
+// 4). f is called concurrently if the logger is used concurrently, it has to be safe for concurrent use.
+func WithIDGenerator(f func() string) Option {
+	return func(c *config) {
+		c.idGen = f
+	}
+}
+
+// WithClock sets the function that returns the current time for the time field of each entry and for durations the
+// package measures itself, like AddElapsed and Operation, e.g. to pin the time in golden-file tests. The default is
+// time.Now. BurstSummary always uses the real time, as it relies on timers.
+func WithClock(f func() time.Time) Option {
+	return func(c *config) {
+		c.clock = f
+	}
+}
+
+// configOf returns the options of l, or nil if it has none, e.g. a multi logger or a Logger implemented outside of
+// this package
+func configOf(l Logger) *config {
+	if c, ok := l.(interface{ config() *config }); ok {
+		return c.config()
+	}
+	return nil
+}
+
+// timeNow returns the current time from the clock of c, or time.Now if c is nil or has no clock
+func timeNow(c *config) time.Time {
+	if c == nil || c.clock == nil {
+		return time.Now()
+	}
+	return c.clock()
+}
+
+// newID returns a new id from the generator of c, or a random UUID if c is nil or has no generator
+func newID(c *config) string {
+	if c == nil || c.idGen == nil {
+		return uuid()
+	}
+	return c.idGen()
+}
+
+// WithSelfDiagnostic makes New log the effective configuration of the logger right after creating it: level, backend,
+// sampling and enabled options under keys starting with "logger.". The entry is written at info level, or at the level
+// of the logger if it is more severe, so that it isn't filtered.
+func WithSelfDiagnostic() Option {
+	return func(c *config) {
+		c.selfDiag = true
+	}
+}
+
+// wrap applies the options that decorate a Logger that logs entries at level lvl or above. The level of loggers
+// passed to FromLogrus and FromZerolog is unknown, DebugLevel is passed for them.
+func (c *config) wrap(l Logger, lvl Level) Logger {
+	c.level = lvl
+	if c.buildInfo {
+		l = withBuildInfo(l)
+	}
+	if c.levelFunc != nil {
+		l = newLevelFunc(l, c.levelFunc)
+	}
+	if c.guardReuse {
+		l = newGuard(l)
+	}
+	if c.burstWindow > 0 {
+		l = newBurstSummary(l, newBurst(c.burstWindow))
+	}
+	if c.collapseRepeats {
+		l = newCollapse(l, &collapse{}, nil)
+	}
+	if c.cardinalityLimit > 0 {
+		l = newCardinalityGuard(l, newCardinality(c.cardinalityLimit, c.cardinalityKeys))
+	}
+	if c.byteRate > 0 {
+		l = newByteRate(l, newByteBucket(c.byteRate))
+	}
+	return l
+}
+
+// MaxSafeInteger is the largest integer that can be represented exactly by a float64 and hence by JavaScript
+const MaxSafeInteger = 1<<53 - 1
+
+// QuoteLargeInts writes integers whose absolute value exceeds threshold as strings, so that 64 bit ids survive log
+// viewers that parse JSON numbers as float64. Use MaxSafeInteger as threshold for JavaScript based viewers. It applies
+// to AddInt, AddAny and AddFields with any integer type or json.Number.
+func QuoteLargeInts(threshold uint64) Option {
+	return func(c *config) {
+		c.maxInt = threshold
+	}
+}
+
+// SensitiveSalt prepends salt to values added with AddSensitive before hashing, so that the hashes can't be reversed
+// with precomputed tables. Keep the salt secret and stable, otherwise hashes of the same value don't match.
+func SensitiveSalt(salt string) Option {
+	return func(c *config) {
+		c.salt = salt
+	}
+}
+
+// SensitiveHashLen truncates the hex encoded hashes of AddSensitive to n characters. A shorter hash is still good
+// enough to group by, but reveals less. n <= 0 keeps the whole hash.
+func SensitiveHashLen(n int) Option {
+	return func(c *config) {
+		c.hashLen = n
+	}
+}
+
+// ConsoleWriter adapts the writer passed to New for consoles. On Windows, output is buffered until the end of a line
+// and line endings are converted to CRLF, each complete line is written at once. On other platforms, the writer is
+// used as is. The option has no effect for FromLogrus and FromZerolog.
+func ConsoleWriter() Option {
+	return func(c *config) {
+		c.console = true
+	}
+}
+
+// FatalExitCode sets the code the application exits with after an entry at fatal level has been written. The default
+// is 1.
+func FatalExitCode(n int) Option {
+	return func(c *config) {
+		c.exitCode = n
+	}
+}
+
+// WithLevelFunc makes the logger call f on each Flush to get its level, instead of using the level passed to the
+// constructor, e.g. if the level depends on configuration that changes at runtime. f is called once per entry from
+// the goroutine that flushes it, it must be cheap and safe for concurrent use. Entries at fatal and panic level are
+// always written. Since the level isn't known in advance, entries are built at all levels and only discarded on
+// Flush. The option has no effect for loggers passed to FromLogrus and FromZerolog beyond their own level.
+func WithLevelFunc(f func() Level) Option {
+	return func(c *config) {
+		c.levelFunc = f
+	}
+}
+
+// threshold returns the level a logger created with lvl is built with: DebugLevel if the level is checked by the
+// function of WithLevelFunc, otherwise lvl
+func (c *config) threshold(lvl Level) Level {
+	if c.levelFunc != nil {
+		return DebugLevel
+	}
+	return lvl
+}
+
+// enabled reports whether an entry at level lvl is written by a logger with config c, which may be nil
+func (c *config) enabled(lvl Level) bool {
+	switch {
+	case c == nil:
+		return true
+	case c.levelFunc != nil:
+		return lvl <= FatalLevel || lvl <= validLevel(c.levelFunc())
+	}
+	return lvl <= c.level
+}
+
+// RepanicInGo makes goroutines started by Logger.Go panic again after they logged a panic of their function, which
+// crashes the process like an unrecovered panic. By default, the panic is swallowed and the goroutine ends.
+func RepanicInGo() Option {
+	return func(c *config) {
+		c.repanicGo = true
+	}
+}
+
+// WithBuildInfo adds the revision and the time of the build to each entry under the keys "build.revision" and
+// "build.time", e.g. to correlate logs with releases. The values are taken from BuildRevision and BuildTime if they
+// are set with ldflags, otherwise from the VCS information Go embeds in binaries since Go 1.18. The revision has the
+// suffix "-dirty" if the working tree had uncommitted changes. Without VCS information, the version of the main module
+// is used as revision if it's known. Fields that are unknown, e.g. with go run, are omitted.
+func WithBuildInfo() Option {
+	return func(c *config) {
+		c.buildInfo = true
+	}
+}
+
+// BigPrecision makes AddBigRat and AddBigFloat write their values with places decimal places, rounded half away from
+// zero for big.Rat and to nearest even for big.Float, e.g. 2 for "0.33" instead of "1/3". By default, the values are
+// written exactly: big.Rat as fraction like "1/3" or as integer like "5", big.Float with the fewest digits that
+// represent it exactly. A negative value restores the default.
+func BigPrecision(places int) Option {
+	return func(c *config) {
+		if places < 0 {
+			places = -1
+		}
+		c.bigPrec = places
+	}
+}
+
+// ScrubPatterns replaces the matches of patterns in all string values of an entry with ScrubReplacement when it is
+// written, e.g. a card number in an error message, which redacting by key can't catch. PIIPatterns returns patterns of
+// common personally identifiable information. The message is scrubbed, too, but not the keys, and values that aren't
+// strings, like integers, aren't scrubbed. For the zerolog and gelf backends, nested values are scrubbed as well, for
+// the logrus backend and channel loggers only strings at the top level. Each pattern is applied to each string, so
+// keep the patterns few and simple. Multiple calls add patterns.
+func ScrubPatterns(patterns ...*regexp.Regexp) Option {
+	return func(c *config) {
+		c.scrub = append(c.scrub, patterns...)
+	}
+}
+
+// OnPanic sets a function that is called with the fields of an entry at panic level after the entry has been
+// written, right before the panic propagates, e.g. to flush buffers or notify someone before the stack unwinds. It is
+// only called for entries at panic level, not for panics of the application. The fields are a copy and contain the
+// fields the backend adds, like the time, without the message.
+func OnPanic(f func(fields map[string]interface{})) Option {
+	return func(c *config) {
+		c.onPanic = f
+	}
+}
+
+// panicked calls the function set by OnPanic with the fields returned by fields. The fields are only collected if a
+// function is set.
+func (c *config) panicked(fields func() map[string]interface{}) {
+	if c.onPanic != nil {
+		c.onPanic(fields())
+	}
+}
+
+// jsonFields decodes the JSON object b into a map. The prefix is removed from the keys that have it, e.g. the
+// underscore of GELF's additional fields.
+func jsonFields(b []byte, prefix string) map[string]interface{} {
+	fs := map[string]interface{}{}
+	_ = json.Unmarshal(b, &fs)
+	for k, v := range fs {
+		if prefix != "" && strings.HasPrefix(k, prefix) {
+			delete(fs, k)
+			fs[strings.TrimPrefix(k, prefix)] = v
+		}
+	}
+	return fs
+}
+
+// TrustForwardedFor makes AddRequest log the first address of the header X-Forwarded-For as remote address if it is
+// set. Only use it behind a proxy that sets the header, clients can send arbitrary values.
+func TrustForwardedFor() Option {
+	return func(c *config) {
+		c.forwardedFor = true
+	}
+}
+
+// ErrorKeys sets the keys AddErr and AddErrN store the error message and the error stack under, e.g.
+// "error.message" and "error.stack_trace" for the Elastic Common Schema. The defaults are "err" and "err_stack".
+func ErrorKeys(key, stackKey string) Option {
+	return func(c *config) {
+		c.errKey = key
+		c.errStackKey = stackKey
+	}
+}
+
+// WithContextExtractor registers a function that returns fields from a context, e.g. a request id. FromContext adds
+// the fields f returns for its context to the logger it returns, so handlers don't need to add them one by one.
+func WithContextExtractor(f func(ctx context.Context) map[string]interface{}) Option {
+	return func(c *config) {
+		c.ctxExtractor = f
+	}
+}
+
+// FlagBelowThreshold makes AddIntThreshold and AddDurThreshold add their flag as false if the value doesn't exceed
+// the threshold. By default, the flag is omitted.
+func FlagBelowThreshold() Option {
+	return func(c *config) {
+		c.flagBelow = true
+	}
+}
+
+// WithFormatter sets the formatter of loggers created by New with the logrus backend, e.g. a custom formatter for a
+// proprietary schema. If the option is passed more than once, the last formatter wins. Loggers passed to FromLogrus
+// keep their own formatter.
+func WithFormatter(f logrus.Formatter) Option {
+	return func(c *config) {
+		c.formatter = f
+	}
+}
+
+// AuditMode adds the field "seq" with a sequence number to each entry written by the logger, so that gaps reveal
+// dropped or removed entries. The sequence starts at 1, or the number set with AuditStartSeq, and is shared by all
+// loggers derived from the logger. Use AuditHashChain for tamper evidence. With the gelf backend, the audit fields
+// have the prefix "_" of additional fields, e.g. "_seq". The option applies to loggers created by New, not to
+// FromLogrus and FromZerolog.
+func AuditMode() Option {
+	return func(c *config) {
+		c.audit = true
+	}
+}
+
+// AuditStartSeq sets the sequence number of the first entry in audit mode, e.g. to continue the sequence of a log
+// after a restart. It implies AuditMode.
+func AuditStartSeq(n uint64) Option {
+	return func(c *config) {
+		c.audit = true
+		c.auditStart = n
+	}
+}
+
+// AuditHashChain adds the fields "prev_hash" and "hash" to each entry in audit mode. hash is the hex encoded SHA-256
+// hash of the written entry up to the field hash, including "seq" and "prev_hash". prev_hash is the hash of the
+// previous entry, empty for the first one. Modifying, removing or reordering entries breaks the chain. It implies
+// AuditMode.
+func AuditHashChain() Option {
+	return func(c *config) {
+		c.audit = true
+		c.auditChain = true
+	}
+}
+
+// WithHook adds a hook to loggers created by New with the logrus backend, e.g. one returned by NewQueueHook. The option
+// can be passed more than once to add several hooks. Loggers passed to FromLogrus keep their own hooks.
+func WithHook(h logrus.Hook) Option {
+	return func(c *config) {
+		c.hooks = append(c.hooks, h)
+	}
+}
+
+// DefaultRetention sets the retention class of entries that don't set one with Entry.Retention, e.g. "short" for
+// a service whose logs are only kept in the hot tier.
+func DefaultRetention(class string) Option {
+	return func(c *config) {
+		c.retention = class
+	}
+}
@@ -0,0 +1,687 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func backends() []Implementation {
+	return []Implementation{ZeroLogBackend, LogrusBackend, GelfBackend}
+}
+
+func TestDebugSampleRate(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl, DebugSampleRate(0))
+		l.Debug().AddStr("debugkey", "val").Flush("")
+		l.Level(DebugLevel).AddStr("debugkey", "val").Flush("")
+		l.Info().AddStr("infokey", "val").Flush("")
+		s := sb.String()
+		assert.NotContains(t, s, "debugkey", "Debug entries should be dropped")
+		assert.Contains(t, s, "infokey", "Info entries should not be sampled")
+
+		sb.Reset()
+		l = New(&sb, DebugLevel, impl, DebugSampleRate(0.5))
+		for i := 0; i < 1000; i++ {
+			l.Debug().Flush("")
+		}
+		n := strings.Count(sb.String(), "\n")
+		assert.InDelta(t, 500, n, 150, "About half of the debug entries should be kept")
+
+		before := DroppedCount()
+		l = New(&sb, DebugLevel, impl, DebugSampleRate(0))
+		l.Debug().Flush("")
+		assert.Equal(t, before+1, DroppedCount(), "Dropped entries should be counted")
+	}
+}
+
+func TestLevelSampling(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl, DebugSampleRate(1),
+			LevelSampling(map[Level]float64{DebugLevel: 0, InfoLevel: 0.5, WarnLevel: 0, FatalLevel: 0}))
+		l.Debug().AddStr("debugkey", "val").Flush("")
+		l.Warn().AddStr("warnkey", "val").Flush("")
+		l.Level(WarnLevel).AddStr("warnkey", "val").Flush("")
+		l.Error().AddStr("errorkey", "val").Flush("")
+		s := sb.String()
+		assert.NotContains(t, s, "debugkey", "Debug entries should be dropped")
+		assert.NotContains(t, s, "warnkey", "Warn entries should be dropped")
+		assert.Contains(t, s, "errorkey", "Levels without probability should be kept")
+		assert.NotContains(t, s, "sample_rate", "Entries of levels that aren't sampled should have no sample rate")
+		assert.Equal(t, 1, exitCode(func() { l.Fatal().Flush("") }), "Fatal entries should never be dropped")
+
+		sb.Reset()
+		for i := 0; i < 1000; i++ {
+			l.Info().Flush("")
+		}
+		n := strings.Count(sb.String(), "\n")
+		assert.InDelta(t, 500, n, 150, "About half of the info entries should be kept")
+		assert.Equal(t, n, strings.Count(sb.String(), "sample_rate"), "Kept entries should have the sample rate")
+		assert.Contains(t, sb.String(), "0.5", "Kept entries should have the sample rate")
+	}
+}
+
+func TestWithSelfDiagnostic(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, WarnLevel, impl, WithSelfDiagnostic(), OmitEmpty(), DebugSampleRate(0.5),
+			LevelSampling(map[Level]float64{InfoLevel: 0.1}))
+		s := sb.String()
+		assert.Contains(t, s, "logger configured", "Configuration should be logged on creation")
+		assert.Contains(t, s, "warn", "Configuration should be logged at the level of the logger")
+		assert.Contains(t, s, "logger.backend", "Configuration should contain the backend")
+		assert.Contains(t, s, "logger.level_sampling", "Configuration should contain the sampling")
+		assert.Contains(t, s, "OmitEmpty", "Configuration should contain the enabled options")
+		assert.NotContains(t, s, "ReportCaller", "Configuration should not contain disabled options")
+
+		sb.Reset()
+		l = New(&sb, DebugLevel, impl)
+		assert.Empty(t, sb.String(), "Configuration should only be logged with the option")
+		l.Info().Flush("")
+	}
+
+	var sb strings.Builder
+	New(&sb, DebugLevel, ZeroLogBackend, WithSelfDiagnostic(), WithTimeFormat(EpochMillis))
+	var m map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(sb.String()), &m))
+	assert.Equal(t, "info", m["level"], "Configuration should be logged at info level")
+	assert.Equal(t, "debug", m["logger.level"], "Configuration should contain the level")
+	assert.Equal(t, "zerolog", m["logger.backend"], "Configuration should contain the backend")
+	assert.Equal(t, "epoch_millis", m["logger.time_format"], "Configuration should contain the time format")
+	assert.Equal(t, []interface{}{}, m["logger.options"], "Configuration should contain the enabled options")
+}
+
+func TestReportCaller(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl, ReportCaller())
+		_, file, line, _ := runtime.Caller(0)
+		l.Level(InfoLevel).Flush("")
+		assert.Contains(t, sb.String(), fmt.Sprintf("%s:%d", file, line+1), "Entry should contain the caller")
+
+		sb.Reset()
+		l = New(&sb, DebugLevel, impl, ReportCaller(), WithCallerSkip(1))
+		wrapper := func() { l.Info().Flush("") }
+		_, file, line, _ = runtime.Caller(0)
+		wrapper()
+		assert.Contains(t, sb.String(), fmt.Sprintf("%s:%d", file, line+1), "Entry should contain the caller of the wrapper")
+
+		sb.Reset()
+		l = New(&sb, DebugLevel, impl)
+		l.Info().Flush("")
+		assert.NotContains(t, sb.String(), "caller", "Entry should not contain the caller by default")
+	}
+}
+
+func TestReportCaller_Multi(t *testing.T) {
+	var sb strings.Builder
+	l := NewMulti(New(&sb, DebugLevel, ZeroLogBackend, ReportCaller()))
+	_, file, line, _ := runtime.Caller(0)
+	l.Info().Flush("")
+	assert.Contains(t, sb.String(), fmt.Sprintf("%s:%d", file, line+1), "Entry should contain the caller")
+}
+
+func TestOmitEmpty(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl, OmitEmpty())
+		l.Info().
+			AddStr("emptystr", "").AddInt("emptyint", 0).AddBool("emptybool", false).
+			AddTime("emptytime", time.Time{}).AddDur("emptydur", 0).
+			AddFields(map[string]interface{}{"emptyfield": "", "field": "val"}).
+			AddStr("str", "val").AddInt("int", 1).AddBool("bool", true).
+			AddTime("time", time.Now()).AddDur("dur", time.Second).
+			AddAny("forced", 0).
+			Flush("")
+		s := sb.String()
+		assert.NotContains(t, s, "empty", "Zero values should be omitted")
+		for _, key := range []string{"field", "str", "int", "bool", "time", "dur", "forced"} {
+			assert.Contains(t, s, key, "Non-zero values and AddAny should not be omitted")
+		}
+
+		sb.Reset()
+		l = New(&sb, DebugLevel, impl)
+		l.Info().AddStr("emptystr", "").Flush("")
+		assert.Contains(t, sb.String(), "emptystr", "Zero values should be logged by default")
+	}
+}
+
+func TestQuoteLargeInts(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend, QuoteLargeInts(MaxSafeInteger))
+	l.Info().
+		AddInt("small", 42).AddInt("large", 1<<60).AddInt("negative", -(1<<60)).
+		AddAny("uint", uint64(1<<63)).AddAny("number", json.Number("123456789012345678901234")).
+		AddAny("float", 1e20).AddAny("str", "1").
+		AddFields(map[string]interface{}{"field": int64(1 << 54)}).
+		Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"small":42`, "Small integers should be numbers")
+	assert.Contains(t, s, `"large":"1152921504606846976"`, "Large integers should be strings")
+	assert.Contains(t, s, `"negative":"-1152921504606846976"`, "Large negative integers should be strings")
+	assert.Contains(t, s, `"uint":"9223372036854775808"`, "Large unsigned integers should be strings")
+	assert.Contains(t, s, `"number":"123456789012345678901234"`, "Large json numbers should be strings")
+	assert.Contains(t, s, `"float":100000000000000000000`, "Floats should not be quoted")
+	assert.Contains(t, s, `"field":"18014398509481984"`, "Large integers in fields should be strings")
+
+	ll := logrus.New()
+	ll.SetOutput(&sb)
+	ll.SetFormatter(&logrus.JSONFormatter{})
+	loggers := []func(...Option) Logger{
+		func(opts ...Option) Logger { return New(&sb, DebugLevel, ZeroLogBackend, opts...) },
+		func(opts ...Option) Logger { return New(&sb, DebugLevel, GelfBackend, opts...) },
+		func(opts ...Option) Logger { return FromLogrus(ll, opts...) },
+	}
+	for _, newLogger := range loggers {
+		sb.Reset()
+		newLogger(QuoteLargeInts(MaxSafeInteger)).Info().AddInt("large", 1<<60).Flush("")
+		assert.Contains(t, sb.String(), `"1152921504606846976"`, "Large integers should be strings")
+		sb.Reset()
+		newLogger().Info().AddInt("large", 1<<60).Flush("")
+		assert.NotContains(t, sb.String(), `"1152921504606846976"`, "Large integers should be numbers by default")
+	}
+}
+
+func TestLargeInt(t *testing.T) {
+	s, ok := largeInt(int64(-1<<63), MaxSafeInteger)
+	assert.True(t, ok, "Minimal int64 should be quoted")
+	assert.Equal(t, "-9223372036854775808", s)
+	_, ok = largeInt(int64(MaxSafeInteger), MaxSafeInteger)
+	assert.False(t, ok, "Integer at the threshold should not be quoted")
+	_, ok = largeInt(json.Number("1.5e300"), MaxSafeInteger)
+	assert.False(t, ok, "Non integer json numbers should not be quoted")
+}
+
+func TestSensitiveSalt(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		New(&sb, DebugLevel, impl, SensitiveSalt("pepper"), SensitiveHashLen(8)).Info().AddSensitive("email", "a@b.c").Flush("")
+		s := sb.String()
+		assert.Contains(t, s, "email_hash", "Message should contain hash")
+		assert.Contains(t, s, "0430ccee", "Hash should be salted and truncated")
+		assert.NotContains(t, s, "0430ccee2", "Hash should be truncated")
+	}
+}
+
+func TestFatalExitCode(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl)
+		assert.Equal(t, 1, exitCode(func() { l.Fatal().Flush("") }), "Default exit code should be 1")
+		l = New(&sb, DebugLevel, impl, FatalExitCode(3))
+		assert.Equal(t, 3, exitCode(func() { l.Fatal().Flush("") }), "Configured exit code should be used")
+		assert.Equal(t, 3, exitCode(func() { l.Info().At(FatalLevel).Flush("") }), "Configured exit code should be used")
+	}
+	l, _ := NewChannel(DebugLevel, 1, FatalExitCode(3))
+	assert.Equal(t, 3, exitCode(func() { l.Fatal().Flush("") }), "Configured exit code should be used")
+}
+
+func TestTrustForwardedFor(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	for _, impl := range backends() {
+		var sb strings.Builder
+		New(&sb, DebugLevel, impl).Info().AddRequest(r).Flush("")
+		assert.Contains(t, sb.String(), "192.0.2.1:1234", "Remote address should be the connection by default")
+		sb.Reset()
+		New(&sb, DebugLevel, impl, TrustForwardedFor()).Info().AddRequest(r).Flush("")
+		assert.Contains(t, sb.String(), "203.0.113.7", "Remote address should be taken from X-Forwarded-For")
+		assert.NotContains(t, sb.String(), "10.0.0.1", "Only the client address should be logged")
+	}
+}
+
+func TestErrorKeys(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl, ErrorKeys("error.message", "error.stack_trace"))
+		l.Info().AddErr(errors.New("failed")).Flush("")
+		l.Info().AddErrN(errors.New("failed"), 1).Flush("")
+		s := sb.String()
+		assert.Equal(t, 2, strings.Count(s, "error.message"), "Error should be stored under the configured key")
+		assert.Equal(t, 2, strings.Count(s, "error.stack_trace"), "Stack should be stored under the configured key")
+		assert.NotContains(t, s, "err_stack", "Default key should not be used")
+
+		sb.Reset()
+		NewAllowlist(l, []string{"error.message", "error.stack_trace"}).Info().AddErr(errors.New("failed")).Flush("")
+		assert.Contains(t, sb.String(), "error.message", "Allowlist should use the configured keys")
+	}
+	l, ch := NewChannel(DebugLevel, 1, ErrorKeys("error.message", "error.stack_trace"))
+	l.Info().AddErr(errors.New("failed")).Flush("")
+	e := <-ch
+	assert.Equal(t, "failed", e.Fields["error.message"], "Error should be stored under the configured key")
+	assert.Contains(t, e.Fields, "error.stack_trace", "Stack should be stored under the configured key")
+}
+
+func TestWithGoroutineID(t *testing.T) {
+	id := goroutineID()
+	assert.NotZero(t, id, "Goroutine id should be parsed")
+	done := make(chan uint64)
+	go func() { done <- goroutineID() }()
+	assert.NotEqual(t, id, <-done, "Goroutines should have different ids")
+
+	for _, impl := range backends() {
+		var sb strings.Builder
+		New(&sb, DebugLevel, impl, WithGoroutineID()).Info().Flush("")
+		assert.Contains(t, sb.String(), "goroutine", "Entry should contain the goroutine id")
+		assert.Contains(t, sb.String(), strconv.FormatUint(id, 10), "Entry should contain the goroutine id")
+	}
+	l, ch := NewChannel(DebugLevel, 1, WithGoroutineID())
+	l.Info().Flush("")
+	assert.Equal(t, id, (<-ch).Fields["goroutine"], "Entry should contain the goroutine id")
+}
+
+func TestNilAsNull(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		New(&sb, DebugLevel, impl, NilAsNull()).Info().AddBoolPtr("nilbool", nil).AddIntPtr("nilint", nil).AddStrPtr("nilstr", nil).Flush("")
+		s := sb.String()
+		assert.Contains(t, s, "nilbool", "Nil pointers should be logged")
+		assert.Contains(t, s, "nilint", "Nil pointers should be logged")
+		assert.Contains(t, s, "nilstr", "Nil pointers should be logged")
+	}
+	l, ch := NewChannel(DebugLevel, 1, NilAsNull())
+	l.Info().AddStrPtr("nilstr", nil).Flush("")
+	e := <-ch
+	assert.Contains(t, e.Fields, "nilstr", "Nil pointers should be logged")
+	assert.Nil(t, e.Fields["nilstr"], "Nil pointers should be logged as null")
+}
+
+func TestBurstSummary(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 10, BurstSummary(50*time.Millisecond))
+	for i := 0; i < 5; i++ {
+		l.Error().AddInt("i", i).Flush("connection refused")
+	}
+	l.Warn().Flush("connection refused")
+	l.Error().Flush("other")
+
+	e := <-ch
+	assert.Equal(t, "connection refused", e.Message, "First entry should be written")
+	assert.Equal(t, 0, e.Fields["i"], "First entry should be written in full")
+	assert.Equal(t, Level(WarnLevel), (<-ch).Level, "Entries are grouped by level")
+	assert.Equal(t, "other", (<-ch).Message, "Entries are grouped by message")
+
+	select {
+	case e = <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("Summary should be written after the window")
+	}
+	assert.Equal(t, Level(ErrorLevel), e.Level, "Summary should have the level of the burst")
+	assert.Contains(t, e.Message, "...and 4 more in the last", "Summary should contain the count")
+	assert.Equal(t, 4, e.Fields["burst_count"], "Summary should contain the count")
+	assert.Equal(t, "connection refused", e.Fields["burst_message"], "Summary should contain the message")
+
+	l.Error().Flush("connection refused")
+	assert.Equal(t, "connection refused", (<-ch).Message, "A new burst should start after the window")
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, ch, "Bursts without repetitions should not be summarized")
+}
+
+func TestBurstSummary_DisabledLevel(t *testing.T) {
+	l, ch := NewChannel(InfoLevel, 10, BurstSummary(time.Second))
+	for i := 0; i < 5; i++ {
+		l.Debug().Flush("poll")
+	}
+	b := l.(*bLog).b
+	b.mu.Lock()
+	assert.Empty(t, b.active, "Entries at disabled levels should not be tracked")
+	b.mu.Unlock()
+	assert.Empty(t, ch)
+}
+
+func TestCollapseRepeats(t *testing.T) {
+	timeout := collapseTimeout
+	defer func() { collapseTimeout = timeout }()
+	collapseTimeout = 50 * time.Millisecond
+
+	l, ch := NewChannel(DebugLevel, 10, CollapseRepeats())
+	l = l.WithField("component", "poller")
+	for i := 0; i < 4; i++ {
+		l.Error().AddInt("attempt", 1).Flush("connection refused")
+	}
+	l.Error().AddInt("attempt", 2).Flush("connection refused")
+	l.Warn().AddInt("attempt", 2).Flush("connection refused")
+	l.WithField("component", "other").Warn().AddInt("attempt", 2).Flush("connection refused")
+
+	e := <-ch
+	assert.Equal(t, "connection refused", e.Message, "First entry should be written")
+	e = <-ch
+	assert.Equal(t, "last message repeated 3 times", e.Message, "Summary should be written before a different entry")
+	assert.Equal(t, Level(ErrorLevel), e.Level, "Summary should have the level of the repeated entry")
+	assert.Equal(t, 3, e.Fields["repeat_count"], "Summary should contain the count")
+	assert.Equal(t, "poller", e.Fields["component"], "Summary should contain the fields of the logger")
+	assert.Equal(t, 2, (<-ch).Fields["attempt"], "Entries with different fields should not be collapsed")
+	assert.Equal(t, Level(WarnLevel), (<-ch).Level, "Entries with different levels should not be collapsed")
+	assert.Equal(t, "other", (<-ch).Fields["component"], "Entries of loggers with different fields should not be collapsed")
+	assert.Empty(t, ch)
+
+	l.Info().Flush("tick")
+	l.Info().Flush("tick")
+	l.Info().Flush("tick")
+	assert.Equal(t, "tick", (<-ch).Message)
+	select {
+	case e = <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("Summary should be written after the timeout")
+	}
+	assert.Equal(t, 2, e.Fields["repeat_count"], "Summary should contain the count")
+	l.Info().Flush("tick")
+	time.Sleep(100 * time.Millisecond)
+	e = <-ch
+	assert.Equal(t, 1, e.Fields["repeat_count"], "Repeats should still be collapsed after the timeout")
+	assert.Empty(t, ch)
+
+	assert.Panics(t, func() { l.Panic().Flush("boom") })
+	assert.Panics(t, func() { l.Panic().Flush("boom") })
+	assert.Equal(t, "boom", (<-ch).Message)
+	assert.Equal(t, "boom", (<-ch).Message, "Entries at panic level should never be collapsed")
+}
+
+func TestCollapseRepeats_DisabledLevel(t *testing.T) {
+	l, ch := NewChannel(InfoLevel, 10, CollapseRepeats())
+	for i := 0; i < 3; i++ {
+		l.Info().Flush("tick")
+		l.Debug().Flush("poll")
+	}
+	assert.Equal(t, "tick", (<-ch).Message)
+	assert.Empty(t, ch, "Entries at disabled levels should not interrupt collapsing")
+	c := l.(*rLog).c
+	c.mu.Lock()
+	assert.Equal(t, 2, c.count, "Repeats around entries at disabled levels should be counted")
+	c.mu.Unlock()
+}
+
+func TestWithByteRateLimit(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, InfoLevel, ZeroLogBackend, WithByteRateLimit(1000))
+	for i := 0; i < 100; i++ {
+		l.Debug().AddStr("pad", strings.Repeat("d", 100)).Flush("")
+	}
+	l.Info().AddStr("pad", strings.Repeat("i", 100)).Flush("")
+	assert.Contains(t, sb.String(), "iii", "Entries below the level of the logger should not take from the budget")
+
+	d := DroppedCount()
+	for i := 0; i < 10; i++ {
+		l.Info().AddStr("pad", strings.Repeat("i", 100)).Flush("")
+	}
+	n := strings.Count(sb.String(), "\n")
+	assert.Equal(t, d+uint64(11-n), DroppedCount(), "Entries dropped by the rate limit should be counted")
+	assert.True(t, n < 6, "Info entries should leave half of the budget, got %d lines", n)
+	l.Warn().AddStr("pad", strings.Repeat("w", 100)).Flush("")
+	assert.Equal(t, n+1, strings.Count(sb.String(), "\n"), "Warn entries should use the reserve")
+	assert.Contains(t, sb.String(), `"ratelimit_dropped":`, "Dropped entries should be counted")
+
+	for i := 0; i < 10; i++ {
+		l.Error().AddStr("pad", strings.Repeat("e", 100)).Flush("")
+	}
+	n = strings.Count(sb.String(), "\n")
+	assert.True(t, n < 11, "Error entries should be dropped when the bucket is empty, got %d lines", n)
+	exitCode(func() { l.Fatal().Flush("fatal") })
+	assert.Contains(t, sb.String(), "fatal", "Fatal entries should never be dropped")
+}
+
+func TestWithByteRateLimit_LevelFunc(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend, WithLevelFunc(func() Level { return InfoLevel }), WithByteRateLimit(200))
+	d := DroppedCount()
+	for i := 0; i < 10; i++ {
+		l.Debug().AddStr("pad", strings.Repeat("d", 50)).Flush("")
+	}
+	l.Info().Flush("info")
+	assert.Equal(t, d, DroppedCount(), "Entries filtered by the level func should not be counted as dropped")
+	assert.Contains(t, sb.String(), `"info"`, "Entries filtered by the level func should not take from the budget")
+}
+
+func TestNestFields(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend, NestFields("fields"), AuditMode())
+	l.WithField("service", "api").Info().AddStr("level", "user").AddInt("n", 1).Flush("msg")
+	assert.Regexp(t, `^\{"level":"info","time":\d+,"message":"msg","fields":\{"service":"api","level":"user","n":1\},"seq":1\}\n$`,
+		sb.String(), "Fields should be nested")
+
+	sb.Reset()
+	l.Info().Flush("")
+	assert.NotContains(t, sb.String(), "fields", "Entries without fields should have no nested object")
+
+	sb.Reset()
+	l = New(&sb, DebugLevel, LogrusBackend, NestFields("fields"), WithFormatter(&logrus.JSONFormatter{}))
+	l.Info().AddStr("key", "val").Flush("msg")
+	assert.Regexp(t, `"fields":\{.*"key":"val"\}\}`, sb.String(), "Fields of logrus' JSON formatter should be nested")
+
+	sb.Reset()
+	l = New(&sb, DebugLevel, LogrusBackend, NestFields("fields"))
+	l.Info().AddStr("key", "val").Flush("msg")
+	assert.Contains(t, sb.String(), "key=val", "Text output should be unchanged")
+
+	sb.Reset()
+	l = New(&sb, DebugLevel, GelfBackend, NestFields("fields"))
+	l.Info().AddStr("key", "val").Flush("msg")
+	assert.Contains(t, sb.String(), `"_key":"val"`, "GELF output should be unchanged")
+}
+
+func TestCardinalityGuard(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 20, CardinalityGuard(3, "user", "url"))
+	l.WithField("user", "u0").Info().Flush("")
+	for i := 0; i < 3; i++ {
+		l.Info().AddStr("user", "u1").AddStr("other", strconv.Itoa(i)).Flush("")
+	}
+	l.Info().AddInt("user", 7).Flush("")
+	assert.Len(t, ch, 5, "No warning should be logged within the limit")
+	l.Info().AddFields(map[string]interface{}{"user": "u2"}).Flush("")
+	l.Info().AddAny("user", "u3").Flush("")
+
+	var warnings []CapturedEntry
+	for len(ch) > 0 {
+		if e := <-ch; e.Message == "field exceeds cardinality limit" {
+			warnings = append(warnings, e)
+		}
+	}
+	if assert.Len(t, warnings, 1, "The warning should be logged once") {
+		assert.Equal(t, Level(WarnLevel), warnings[0].Level)
+		assert.Equal(t, "user", warnings[0].Fields["cardinality_key"])
+		assert.Equal(t, 3, warnings[0].Fields["cardinality_limit"])
+	}
+}
+
+// logWithHelper adds the caller of the helper to e
+func logWithHelper(e Entry) {
+	e.AddCallerSkip(1).Flush("")
+}
+
+func TestEntry_AddCaller(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl)
+		_, file, line, _ := runtime.Caller(0)
+		l.Info().AddCaller().Flush("")
+		s := sb.String()
+		assert.Contains(t, s, fmt.Sprintf("%s:%d", file, line+1), "Entry should contain the caller")
+		assert.Contains(t, s, "TestEntry_AddCaller", "Entry should contain the calling function")
+
+		sb.Reset()
+		_, _, line, _ = runtime.Caller(0)
+		logWithHelper(l.Info())
+		assert.Contains(t, sb.String(), fmt.Sprintf("%s:%d", file, line+1), "Helper should be skipped")
+	}
+}
+
+func TestWithTimeFormat(t *testing.T) {
+	for _, f := range []TimeFormat{EpochMillis, EpochNanos} {
+		for _, impl := range []Implementation{ZeroLogBackend, LogrusBackend} {
+			var sb strings.Builder
+			before := time.Now().UnixNano()
+			New(&sb, DebugLevel, impl, WithTimeFormat(f)).Info().Flush("")
+			after := time.Now().UnixNano()
+			if f == EpochMillis {
+				before, after = before/int64(time.Millisecond), after/int64(time.Millisecond)
+			}
+			s := sb.String()
+			ts := regexp.MustCompile(`time"?[:=](\d+)`).FindStringSubmatch(s)
+			if assert.Len(t, ts, 2, "Entry should contain an integer time: %s", s) {
+				n, _ := strconv.ParseInt(ts[1], 10, 64)
+				assert.True(t, n >= before && n <= after, "Time should be in the configured unit")
+			}
+		}
+	}
+}
+
+func TestFlagBelowThreshold(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 1, FlagBelowThreshold())
+	l.Info().AddIntThreshold("rows", 10, 1000, "large").Flush("")
+	e := <-ch
+	assert.Equal(t, 10, e.Fields["rows"], "Entry should contain value")
+	assert.Equal(t, false, e.Fields["large"], "Entry should contain flag below threshold")
+}
+
+func TestWithFormatter(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, LogrusBackend, WithFormatter(&logrus.TextFormatter{}), WithFormatter(&logrus.JSONFormatter{}))
+	l.Info().AddStr("key", "val").Flush("message")
+	var m map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(sb.String()), &m), "Last formatter should be used")
+	assert.Equal(t, "val", m["key"], "Entry should contain the field")
+	assert.Equal(t, "message", m["msg"], "Entry should contain the message")
+}
+
+func TestWithClock(t *testing.T) {
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl, WithClock(clock), WithTimeFormat(EpochMillis))
+		l.Info().AddElapsed("elapsed", now.Add(-time.Second)).Flush("")
+		s := sb.String()
+		switch impl {
+		case GelfBackend:
+			assert.Contains(t, s, `"timestamp":1551441600`, "Time should be taken from the clock")
+			assert.Contains(t, s, `"_elapsed":1000`, "Elapsed time should be measured with the clock")
+		case LogrusBackend:
+			assert.Contains(t, s, `time="2019-03-01T12:00:00Z"`, "Time of logrus should be taken from the clock")
+			assert.Contains(t, s, "fields.time=1551441600000", "Time should be taken from the clock")
+			assert.Contains(t, s, "elapsed=1s", "Elapsed time should be measured with the clock")
+		default:
+			assert.Contains(t, s, `"time":1551441600000`, "Time should be taken from the clock")
+			assert.Contains(t, s, `"elapsed":1000`, "Elapsed time should be measured with the clock")
+		}
+	}
+
+	var sb strings.Builder
+	NewECS(&sb, DebugLevel, WithClock(clock)).Info().Flush("")
+	assert.Contains(t, sb.String(), `"@timestamp":"2019-03-01T12:00:00Z"`, "ECS time should be taken from the clock")
+
+	l, ch := NewChannel(DebugLevel, 1, WithClock(clock))
+	l.Info().Flush("")
+	assert.Equal(t, now, (<-ch).Time, "Captured time should be taken from the clock")
+}
+
+func TestWithTimeCapture(t *testing.T) {
+	created := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	flushed := created.Add(time.Minute)
+	for _, tc := range []TimeCapture{TimeAtCreation, TimeAtFlush} {
+		want := created
+		if tc == TimeAtFlush {
+			want = flushed
+		}
+		for _, impl := range backends() {
+			now := created
+			clock := func() time.Time { return now }
+			var sb strings.Builder
+			l := New(&sb, DebugLevel, impl, WithClock(clock), WithTimeFormat(EpochMillis), WithTimeCapture(tc))
+			e := l.Info()
+			now = flushed
+			e.Flush("")
+			s := sb.String()
+			switch impl {
+			case GelfBackend:
+				assert.Contains(t, s, fmt.Sprintf(`"timestamp":%d`, want.Unix()), implName(impl))
+			case LogrusBackend:
+				assert.Contains(t, s, fmt.Sprintf("fields.time=%d", want.Unix()*1000), implName(impl))
+			default:
+				assert.Contains(t, s, fmt.Sprintf(`"time":%d`, want.Unix()*1000), implName(impl))
+			}
+		}
+
+		now := created
+		clock := func() time.Time { return now }
+		var sb strings.Builder
+		e := NewECS(&sb, DebugLevel, WithClock(clock), WithTimeCapture(tc)).Info()
+		now = flushed
+		e.Flush("")
+		assert.Contains(t, sb.String(), want.Format(time.RFC3339), "ECS time should be taken at the capture point")
+
+		now = created
+		l, ch := NewChannel(DebugLevel, 1, WithClock(clock), WithTimeCapture(tc))
+		e = l.Info()
+		now = flushed
+		e.Flush("")
+		assert.Equal(t, want, (<-ch).Time, "Captured time should be taken at the capture point")
+	}
+}
+
+func TestOnPanic(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		var fields map[string]interface{}
+		var written bool
+		l := New(&sb, DebugLevel, impl, OnPanic(func(fs map[string]interface{}) {
+			fields = fs
+			written = sb.Len() > 0
+		}))
+		assert.Panics(t, func() { l.Panic().AddStr("key", "val").Flush("msg") }, implName(impl))
+		assert.Equal(t, "val", fields["key"], implName(impl))
+		assert.True(t, written, "The handler should be called after the entry has been written")
+
+		fields = nil
+		l.Error().AddStr("key", "val").Flush("msg")
+		assert.Nil(t, fields, "The handler should only be called at panic level")
+	}
+
+	var fields map[string]interface{}
+	l, ch := NewChannel(DebugLevel, 1, OnPanic(func(fs map[string]interface{}) { fields = fs }))
+	assert.Panics(t, func() { l.Panic().AddInt("key", 1).Flush("msg") })
+	assert.Equal(t, 1, fields["key"])
+	assert.Equal(t, "msg", (<-ch).Message)
+}
+
+func TestWithLevelFunc(t *testing.T) {
+	var lvl atomic.Value
+	f := func() Level { return lvl.Load().(Level) }
+	for _, impl := range backends() {
+		lvl.Store(Level(WarnLevel))
+		var sb strings.Builder
+		l := New(&sb, ErrorLevel, impl, WithLevelFunc(f)).WithField("logger", "dynamic")
+		l.Info().Flush("info1")
+		l.Warn().Flush("warn1")
+		e := l.Debug()
+		lvl.Store(Level(DebugLevel))
+		e.Flush("debug1")
+		l.Info().At(ErrorLevel).Flush("raised")
+		lvl.Store(Level(ErrorLevel))
+		l.Warn().Flush("warn2")
+		l.Error().At(DebugLevel).Flush("lowered")
+		s := sb.String()
+		assert.NotContains(t, s, "info1", implName(impl))
+		assert.Contains(t, s, "warn1", "The level of the function should be used instead of the level of New")
+		assert.Contains(t, s, "debug1", "The level should be checked on Flush")
+		assert.Contains(t, s, "raised", implName(impl))
+		assert.NotContains(t, s, "warn2", "Changes of the level should apply immediately")
+		assert.NotContains(t, s, "lowered", "The level of At should be checked")
+	}
+
+	l, ch := NewChannel(InfoLevel, 1, WithLevelFunc(func() Level { return ErrorLevel }))
+	l.WithLevelRemap(map[Level]Level{WarnLevel: ErrorLevel}).Warn().Flush("remapped")
+	l.Error().Flush("error")
+	assert.Equal(t, "error", (<-ch).Message, "The level check should apply before remapping")
+}
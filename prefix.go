@@ -0,0 +1,42 @@
+package logger
+
+// pLog wraps a Logger so that the messages of its entries are prefixed with a tag. See Logger.WithMessagePrefix.
+type pLog struct {
+	wrapLog
+	prefix string
+}
+
+var _ Logger = (*pLog)(nil)
+
+// newPrefix returns a Logger that prefixes the messages of the entries of l with prefix
+func newPrefix(l Logger, prefix string) Logger {
+	p := &pLog{prefix: prefix}
+	p.wrapLog = wrapLog{l, p}
+	return p
+}
+
+// with returns a logger with the same prefix that wraps l
+func (p *pLog) with(l Logger) Logger {
+	return newPrefix(l, p.prefix)
+}
+
+// entry returns a new entry that prefixes the message of e
+func (p *pLog) entry(e Entry, lvl Level) Entry {
+	pe := &pEntry{prefix: p.prefix}
+	pe.wrapEntry = wrapEntry{e: e, lvl: lvl, self: pe}
+	return pe
+}
+
+// pEntry prefixes the message on Flush
+type pEntry struct {
+	wrapEntry
+	prefix string
+}
+
+var _ Entry = (*pEntry)(nil)
+
+// Flush writes the entry as a single log statement. Optionally, a message can be added which will
+// be included in the final log entry
+func (p *pEntry) Flush(msg string) {
+	p.e.Flush(p.prefix + ": " + msg)
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMessagePrefix(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl).WithMessagePrefix("billing")
+		l.Info().AddStr("key", "val").Flush("charged")
+		s := sb.String()
+		assert.Contains(t, s, "billing: charged", "Message should be prefixed")
+		assert.Contains(t, s, "val", "Fields should be kept")
+
+		sb.Reset()
+		l.WithField("key", "val").Info().Flush("")
+		assert.Contains(t, sb.String(), "billing: ", "Empty messages should be prefixed")
+
+		sb.Reset()
+		l.WithMessagePrefix("invoice").Warn().Flush("sent")
+		assert.Contains(t, sb.String(), "billing: invoice: sent", "Prefixes should be nested")
+	}
+}
+
+func TestWithMessagePrefix_Multi(t *testing.T) {
+	l, sbs := multiLogger(DebugLevel)
+	l.WithMessagePrefix("billing").Info().Flush("charged")
+	for _, sb := range sbs {
+		assert.Contains(t, sb.String(), "billing: charged", "Message should be prefixed for each logger")
+	}
+}
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// byteBucket is the token bucket of a logger with the option WithByteRateLimit. It holds up to rate bytes and is
+// refilled with rate bytes per second.
+type byteBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+	// dropped counts the entries dropped since the last entry that was written
+	dropped int
+}
+
+func newByteBucket(bytesPerSec int) *byteBucket {
+	rate := float64(bytesPerSec)
+	return &byteBucket{rate: rate, tokens: rate, last: time.Now()}
+}
+
+// take reports whether an entry with level lvl and size bytes fits into the bucket and takes its bytes if it does.
+// Entries at warn level have to leave a quarter of the capacity, entries at lower levels half of it. Entries at
+// fatal and panic level always fit. If the entry fits, the number of entries dropped before it is returned and reset.
+func (b *byteBucket) take(lvl Level, size int) (bool, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+	var reserve float64
+	if lvl == WarnLevel {
+		reserve = b.rate / 4
+	} else if lvl > WarnLevel {
+		reserve = b.rate / 2
+	}
+	fatal := lvl == FatalLevel || lvl == PanicLevel
+	if !fatal && b.tokens-float64(size) < reserve {
+		b.dropped++
+		return false, 0
+	}
+	b.tokens -= float64(size)
+	if b.tokens < 0 {
+		b.tokens = 0
+	}
+	dropped := b.dropped
+	b.dropped = 0
+	return true, dropped
+}
+
+// vLog wraps a Logger so that entries are dropped when they exceed the byte budget. See WithByteRateLimit.
+type vLog struct {
+	wrapLog
+	lim *byteBucket
+	// cfg is the config of the wrapped logger, entries below its level don't take from the bucket. It's nil for
+	// loggers without a config, like multi loggers.
+	cfg *config
+}
+
+var _ Logger = (*vLog)(nil)
+
+// newByteRate returns a Logger that drops the entries of l that exceed the budget of lim
+func newByteRate(l Logger, lim *byteBucket) Logger {
+	v := &vLog{lim: lim, cfg: configOf(l)}
+	v.wrapLog = wrapLog{l, v}
+	return v
+}
+
+// with returns a logger that shares the budget and wraps l
+func (v *vLog) with(l Logger) Logger {
+	return newByteRate(l, v.lim)
+}
+
+// entry returns a new entry that takes from the budget on Flush
+func (v *vLog) entry(e Entry, lvl Level) Entry {
+	ve := &vEntry{lim: v.lim, cfg: v.cfg}
+	ve.wrapEntry = wrapEntry{e: e, lvl: lvl, self: ve}
+	return ve
+}
+
+// vEntry remembers its level and drops the entry on Flush if it exceeds the byte budget
+type vEntry struct {
+	wrapEntry
+	lim *byteBucket
+	cfg *config
+}
+
+var _ Entry = (*vEntry)(nil)
+
+// Flush writes the entry as a single log statement. Optionally, a message can be added which will
+// be included in the final log entry
+func (v *vEntry) Flush(msg string) {
+	if !v.cfg.enabled(v.lvl) {
+		// the entry isn't written, it must not take from the budget
+		v.e.Flush(msg)
+		return
+	}
+	b, _ := v.e.Bytes()
+	ok, dropped := v.lim.take(v.lvl, len(b)+len(msg))
+	if !ok {
+		drop()
+		v.e.Discard()
+		return
+	}
+	if dropped > 0 {
+		v.e = v.e.AddInt("ratelimit_dropped", dropped)
+	}
+	v.e.Flush(msg)
+}
@@ -0,0 +1,65 @@
+package logger
+
+// xLog wraps a Logger so that the levels of its entries are remapped. See Logger.WithLevelRemap.
+type xLog struct {
+	wrapLog
+	remap map[Level]Level
+	// cfg is the config of the wrapped logger, entries below its level aren't remapped. It's nil for loggers without a
+	// config, like multi loggers.
+	cfg *config
+}
+
+// newRemap returns a Logger that remaps the levels of the entries of l according to remap. The map is copied,
+// remapping from or to fatal and panic level is ignored.
+func newRemap(l Logger, remap map[Level]Level) Logger {
+	m := make(map[Level]Level, len(remap))
+	for from, to := range remap {
+		from, to = validLevel(from), validLevel(to)
+		if from != FatalLevel && from != PanicLevel && to != FatalLevel && to != PanicLevel {
+			m[from] = to
+		}
+	}
+	x := &xLog{remap: m, cfg: configOf(l)}
+	x.wrapLog = wrapLog{l, x}
+	return x
+}
+
+var _ Logger = (*xLog)(nil)
+
+// with returns a logger with the same remapping that wraps l
+func (x *xLog) with(l Logger) Logger {
+	n := &xLog{remap: x.remap, cfg: x.cfg}
+	n.wrapLog = wrapLog{l, n}
+	return n
+}
+
+// entry returns a new entry whose level is remapped on Flush
+func (x *xLog) entry(e Entry, lvl Level) Entry {
+	xe := &xEntry{x: x}
+	xe.wrapEntry = wrapEntry{e: e, lvl: lvl, self: xe}
+	return xe
+}
+
+// xEntry remembers its level and remaps it on Flush
+type xEntry struct {
+	wrapEntry
+	x *xLog
+}
+
+var _ Entry = (*xEntry)(nil)
+
+// Flush writes the entry as a single log statement. Optionally, a message can be added which will
+// be included in the final log entry
+func (x *xEntry) Flush(msg string) {
+	to, ok := x.x.remap[x.lvl]
+	if !ok {
+		x.e.Flush(msg)
+		return
+	}
+	if !x.x.cfg.enabled(x.lvl) {
+		// the level check applies to the level before remapping
+		x.e.Discard()
+		return
+	}
+	x.e.At(to).Flush(msg)
+}
@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLevelRemap(t *testing.T) {
+	l, ch := NewChannel(InfoLevel, 10)
+	r := l.WithLevelRemap(map[Level]Level{ErrorLevel: WarnLevel, DebugLevel: InfoLevel, FatalLevel: ErrorLevel})
+	r.Error().Flush("demoted")
+	e := <-ch
+	assert.Equal(t, "demoted", e.Message)
+	assert.Equal(t, Level(WarnLevel), e.Level, "Level should be remapped")
+
+	r.Debug().Flush("promoted")
+	r.Warn().Flush("kept")
+	e = <-ch
+	assert.Equal(t, "kept", e.Message, "Entries below the level of the logger should not be remapped and written")
+	assert.Equal(t, Level(WarnLevel), e.Level, "Levels that aren't in the map should be kept")
+
+	r.WithField("key", "val").Info().At(ErrorLevel).Flush("at")
+	e = <-ch
+	assert.Equal(t, Level(WarnLevel), e.Level, "Level changed with At should be remapped")
+	assert.Equal(t, "val", e.Fields["key"], "Fields of the logger should be kept")
+
+	code := exitCode(func() { r.Fatal().Flush("fatal") })
+	assert.Equal(t, 1, code, "Fatal entries should never be remapped")
+	assert.Equal(t, Level(FatalLevel), (<-ch).Level)
+}
+
+func TestWithLevelRemap_Backends(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, WarnLevel, impl).WithLevelRemap(map[Level]Level{ErrorLevel: WarnLevel, InfoLevel: ErrorLevel})
+		l.Error().Flush("demoted")
+		assert.Contains(t, sb.String(), "demoted", "%s: Remapped entries should be written", implName(impl))
+		assert.NotContains(t, sb.String(), "error", "%s: Level should be remapped", implName(impl))
+		l.Info().Flush("filtered")
+		assert.NotContains(t, sb.String(), "filtered", "%s: Level check should apply before remapping",
+			implName(impl))
+	}
+}
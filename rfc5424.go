@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RFC5424Format is a logrus formatter that writes each entry as a syslog message in the format of RFC 5424, one
+// message per line, e.g. for collectors that read syslog messages from a file or a pipe instead of a syslog socket.
+// Use it with the logrus backend and the option WithFormatter, or with a logger passed to FromLogrus:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD-ID name="value" ...] MSG
+//
+// The priority is Facility * 8 plus the severity of the level, the levels of this package have the values of syslog
+// severities. The fields of the entry are the parameters of a single structured data element, sorted by name.
+// Characters that aren't allowed in parameter names are replaced by "_", and names are truncated to 32 characters.
+// Line breaks in parameter values and in the message are written as "\n" and "\r", so that each message stays on
+// one line. The field "time" added by the logger is the timestamp.
+type RFC5424Format struct {
+	// Facility is the syslog facility, e.g. 1 for user-level messages or 16 to 23 for local0 to local7
+	Facility int
+	// Hostname is the HOSTNAME of the messages. If it is empty, the name reported by the kernel is used.
+	Hostname string
+	// AppName is the APP-NAME of the messages. If it is empty, the name of the executable is used.
+	AppName string
+	// MsgIDKey is the key of the field that is written as MSGID instead of as parameter. If it is empty or the entry
+	// doesn't have the field, MSGID is "-".
+	MsgIDKey string
+	// SDID is the SD-ID of the structured data element. If it is empty, "fields@32473" is used, where 32473 is the
+	// enterprise number reserved for documentation.
+	SDID string
+}
+
+var _ logrus.Formatter = (*RFC5424Format)(nil)
+
+// Format renders a single log entry
+func (f *RFC5424Format) Format(e *logrus.Entry) ([]byte, error) {
+	t := e.Time
+	if ts, ok := e.Data["time"].(time.Time); ok {
+		t = ts
+	}
+	var msgID string
+	if f.MsgIDKey != "" {
+		if v, ok := e.Data[f.MsgIDKey]; ok {
+			msgID = fmt.Sprint(v)
+		}
+	}
+	host, app := f.Hostname, f.AppName
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	if app == "" {
+		app = filepath.Base(os.Args[0])
+	}
+
+	var b bytes.Buffer
+	b.WriteByte('<')
+	b.WriteString(strconv.Itoa(f.Facility*8 + int(lrtol(e.Level))))
+	b.WriteString(">1 ")
+	b.WriteString(t.Format("2006-01-02T15:04:05.000000Z07:00"))
+	b.WriteByte(' ')
+	b.WriteString(syslogHeader(host, 255))
+	b.WriteByte(' ')
+	b.WriteString(syslogHeader(app, 48))
+	b.WriteByte(' ')
+	b.WriteString(strconv.Itoa(os.Getpid()))
+	b.WriteByte(' ')
+	b.WriteString(syslogHeader(msgID, 32))
+	b.WriteByte(' ')
+
+	keys := make([]string, 0, len(e.Data))
+	for k, v := range e.Data {
+		if _, ok := v.(time.Time); ok && k == "time" || f.MsgIDKey != "" && k == f.MsgIDKey {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		b.WriteByte('-')
+	} else {
+		sort.Strings(keys)
+		sdID := f.SDID
+		if sdID == "" {
+			sdID = "fields@32473"
+		}
+		b.WriteByte('[')
+		b.WriteString(sdID)
+		for _, k := range keys {
+			b.WriteByte(' ')
+			b.WriteString(sdName(k))
+			b.WriteString(`="`)
+			sdEscape(&b, sdValue(e.Data[k]))
+			b.WriteByte('"')
+		}
+		b.WriteByte(']')
+	}
+	if e.Message != "" {
+		b.WriteByte(' ')
+		msgEscape(&b, e.Message)
+	}
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+// syslogHeader returns s as header field of at most max printable ASCII characters. Other characters are replaced
+// by "_". An empty s is the nil value "-".
+func syslogHeader(s string, max int) string {
+	if s == "" {
+		return "-"
+	}
+	b := []byte(s)
+	if len(b) > max {
+		b = b[:max]
+	}
+	for i, c := range b {
+		if c < 33 || c > 126 {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// sdName returns k as PARAM-NAME, which consists of 1 to 32 printable ASCII characters except '=', ' ', ']' and '"'
+func sdName(k string) string {
+	b := []byte(syslogHeader(k, 32))
+	for i, c := range b {
+		if c == '=' || c == ']' || c == '"' {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// sdValue returns v as string
+func sdValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	}
+	return fmt.Sprint(v)
+}
+
+// sdEscape writes s to b with '"', '\' and ']' escaped by a backslash like in a PARAM-VALUE. Line breaks are written
+// as "\n" and "\r".
+func sdEscape(b *bytes.Buffer, s string) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\\', ']':
+			b.WriteByte('\\')
+		case '\n', '\r':
+			msgEscape(b, s[i:i+1])
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+}
+
+// msgEscape writes s to b with line breaks written as "\n" and "\r"
+func msgEscape(b *bytes.Buffer, s string) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+}
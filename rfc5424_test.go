@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRFC5424Format(t *testing.T) {
+	var sb strings.Builder
+	now := time.Date(2019, 3, 1, 12, 0, 0, 500000000, time.UTC)
+	f := &RFC5424Format{Facility: 16, Hostname: "web 1", AppName: "billing", MsgIDKey: "event"}
+	l := New(&sb, DebugLevel, LogrusBackend, WithFormatter(f), WithClock(func() time.Time { return now }))
+	l.Error().AddStr("event", "charge").AddStr("quote", `a "b" c\d]`).AddErr(errors.New("declined")).
+		AddInt("a=b", 1).Flush("charge failed")
+	pid := strconv.Itoa(os.Getpid())
+	assert.Equal(t, `<131>1 2019-03-01T12:00:00.500000Z web_1 billing `+pid+` charge `+
+		`[fields@32473 a_b="1" err="declined" err_stack="declined" quote="a \"b\" c\\d\]"] charge failed`+"\n",
+		sb.String())
+
+	sb.Reset()
+	f = &RFC5424Format{Facility: 1, SDID: "app@12345"}
+	l = New(&sb, DebugLevel, LogrusBackend, WithFormatter(f))
+	l.Debug().NoTime().Flush("")
+	host, _ := os.Hostname()
+	assert.Regexp(t, `^<15>1 \S+ `+regexp.QuoteMeta(host)+` `+regexp.QuoteMeta(filepath.Base(os.Args[0]))+` \d+ - -\n$`,
+		sb.String(), "Missing values should be written as nil values")
+
+	sb.Reset()
+	l.Warn().AddStr("key", "val").Flush("msg")
+	assert.Contains(t, sb.String(), `- [app@12345 key="val"] msg`, "SD-ID should be configurable")
+}
+
+func TestRFC5424Format_LineBreaks(t *testing.T) {
+	var sb strings.Builder
+	f := &RFC5424Format{Hostname: "host", AppName: "app"}
+	l := New(&sb, DebugLevel, LogrusBackend, WithFormatter(f))
+	l.Info().AddStr("stack", "line1\r\nline2").Flush("multi\nline msg")
+	s := sb.String()
+	assert.Equal(t, 1, strings.Count(s, "\n"), "Each message should be a single line")
+	assert.Contains(t, s, `stack="line1\r\nline2"`, "Line breaks in values should be escaped")
+	assert.True(t, strings.HasSuffix(s, ` multi\nline msg`+"\n"), "Line breaks in the message should be escaped")
+}
@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+)
+
+// ScrubReplacement replaces the matches of the patterns of ScrubPatterns
+const ScrubReplacement = "***"
+
+// Patterns of common personally identifiable information for ScrubPatterns. They favor false positives over leaks,
+// e.g. CardNumberPattern also matches other numbers of 13 to 19 digits.
+var (
+	// CardNumberPattern matches payment card numbers of 13 to 19 digits, which may be grouped by spaces or dashes
+	CardNumberPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	// EmailPattern matches email addresses
+	EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	// SSNPattern matches US social security numbers like 123-45-6789
+	SSNPattern = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+)
+
+// PIIPatterns returns CardNumberPattern, EmailPattern and SSNPattern, e.g. ScrubPatterns(PIIPatterns()...)
+func PIIPatterns() []*regexp.Regexp {
+	return []*regexp.Regexp{CardNumberPattern, EmailPattern, SSNPattern}
+}
+
+// scrub returns s with the matches of patterns replaced with ScrubReplacement. It returns false if nothing matched.
+func scrub(s string, patterns []*regexp.Regexp) (string, bool) {
+	changed := false
+	for _, p := range patterns {
+		if p.MatchString(s) {
+			s = p.ReplaceAllLiteralString(s, ScrubReplacement)
+			changed = true
+		}
+	}
+	return s, changed
+}
+
+// scrubFields scrubs the string values of fs in place
+func scrubFields(fs map[string]interface{}, patterns []*regexp.Regexp) {
+	for k, v := range fs {
+		if s, ok := v.(string); ok {
+			if r, ok := scrub(s, patterns); ok {
+				fs[k] = r
+			}
+		}
+	}
+}
+
+// scrubWriter scrubs the string values of each JSON entry written to it, see ScrubPatterns
+type scrubWriter struct {
+	w        io.Writer
+	patterns []*regexp.Regexp
+}
+
+// Write writes the entry in p with the string values scrubbed. Entries that aren't JSON objects are written unchanged.
+func (w *scrubWriter) Write(p []byte) (int, error) {
+	b, ok := scrubJSON(p, w.patterns)
+	if !ok {
+		return w.w.Write(p)
+	}
+	if _, err := w.w.Write(b); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sync syncs the underlying writer if it supports it
+func (w *scrubWriter) Sync() error {
+	return syncWriter(w.w)
+}
+
+// scrubJSON returns the JSON object in line with the string values scrubbed, at all depths. Member names aren't
+// scrubbed. It returns false if line isn't a JSON object or no value was changed.
+func scrubJSON(line []byte, patterns []*regexp.Regexp) ([]byte, bool) {
+	if len(line) == 0 || line[0] != '{' {
+		return nil, false
+	}
+	var out []byte
+	// last is the end of the part of line that has been copied to out
+	last := 0
+	for i := 0; i < len(line); i++ {
+		if line[i] != '"' {
+			continue
+		}
+		end := stringEnd(line, i)
+		if end < 0 {
+			return nil, false
+		}
+		next := end
+		for next < len(line) && (line[next] == ' ' || line[next] == '\t') {
+			next++
+		}
+		if next < len(line) && line[next] == ':' {
+			// member name
+			i = end - 1
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(line[i:end], &s); err == nil {
+			if r, ok := scrub(s, patterns); ok {
+				out = append(out, line[last:i]...)
+				out = appendJSONString(out, r)
+				last = end
+			}
+		}
+		i = end - 1
+	}
+	if out == nil {
+		return nil, false
+	}
+	return append(out, line[last:]...), true
+}
+
+// stringEnd returns the index after the closing quote of the JSON string that starts at line[start], or -1 if the
+// string isn't terminated
+func stringEnd(line []byte, start int) int {
+	for i := start + 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// appendJSONString appends s encoded as JSON string to b without escaping HTML characters, like zerolog does
+func appendJSONString(b []byte, s string) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return append(b, bytes.TrimRight(buf.Bytes(), "\n")...)
+}
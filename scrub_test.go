@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrub(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"card 4111 1111 1111 1111 declined", "card *** declined"},
+		{"card 4111-1111-1111-1111", "card ***"},
+		{"card 4111111111111111", "card ***"},
+		{"order 12345 of 2019-03-01", "order 12345 of 2019-03-01"},
+		{"mail jane.doe+tag@mail.example.com now", "mail *** now"},
+		{"ssn 123-45-6789", "ssn ***"},
+		{"nothing to see", "nothing to see"},
+	}
+	for _, tt := range tests {
+		got, changed := scrub(tt.in, PIIPatterns())
+		assert.Equal(t, tt.want, got, tt.in)
+		assert.Equal(t, tt.in != tt.want, changed, tt.in)
+	}
+}
+
+func TestScrubJSON(t *testing.T) {
+	p := []*regexp.Regexp{regexp.MustCompile(`secret`)}
+	line := []byte(`{"secret":"a secret","n":1,"nested":{"k":["secret", "x\"secret"]},"ok":"<b>"}` + "\n")
+	b, ok := scrubJSON(line, p)
+	assert.True(t, ok)
+	assert.Equal(t, `{"secret":"a ***","n":1,"nested":{"k":["***", "x\"***"]},"ok":"<b>"}`+"\n", string(b),
+		"String values should be scrubbed at all depths, member names should be kept")
+
+	_, ok = scrubJSON([]byte(`{"key":"val"}`), p)
+	assert.False(t, ok, "Unchanged entries should be reported")
+	_, ok = scrubJSON([]byte(`time=now msg=secret`), p)
+	assert.False(t, ok, "Entries that aren't JSON should be ignored")
+}
+
+func TestScrubPatterns(t *testing.T) {
+	err := errors.New("charge of card 4111 1111 1111 1111 failed")
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl, ScrubPatterns(PIIPatterns()...)).WithField("user", "jane@example.com")
+		l.Error().AddErr(err).AddInt("amount", 4111111111111111).Flush("notify jane@example.com")
+		s := sb.String()
+		assert.NotContains(t, s, "4111 1111", implName(impl))
+		assert.NotContains(t, s, "jane@example.com", implName(impl))
+		assert.Contains(t, s, "charge of card *** failed", implName(impl))
+		assert.Contains(t, s, "4111111111111111", "Values that aren't strings should not be scrubbed")
+		if impl != LogrusBackend {
+			assert.NoError(t, json.Unmarshal([]byte(s), &map[string]interface{}{}), "Entry should stay valid JSON")
+		}
+	}
+
+	l, ch := NewChannel(DebugLevel, 1, ScrubPatterns(SSNPattern))
+	l.Info().AddStr("ssn", "123-45-6789").Flush("ssn 123-45-6789")
+	e := <-ch
+	assert.Equal(t, "***", e.Fields["ssn"])
+	assert.Equal(t, "ssn ***", e.Message)
+}
@@ -0,0 +1,37 @@
+package logger
+
+import "time"
+
+// Timer measures the phases of an operation, e.g. the steps of a request pipeline, and adds their durations to an
+// entry. A Timer is created by Logger.NewTimer. It isn't safe for concurrent use.
+type Timer struct {
+	c      *config
+	last   time.Time
+	phases map[string]time.Duration
+}
+
+// newTimer returns a Timer that starts now according to the clock of c
+func newTimer(c *config) *Timer {
+	return &Timer{c: c, last: timeNow(c), phases: make(map[string]time.Duration)}
+}
+
+// Mark ends the phase name. Its duration is the time elapsed since the previous call to Mark, or since the Timer was
+// created for the first phase. If a phase is marked more than once, its durations are summed up.
+func (t *Timer) Mark(name string) {
+	now := timeNow(t.c)
+	t.phases[name] += now.Sub(t.last)
+	t.last = now
+}
+
+// AddTo adds the phases to e as nested object under key that maps each phase to its duration in milliseconds, e.g.
+// {"parse": 1.5, "validate": 0.25}, and returns e. If no phase has been marked, nothing is added.
+func (t *Timer) AddTo(e Entry, key string) Entry {
+	if len(t.phases) == 0 {
+		return e
+	}
+	ms := make(map[string]float64, len(t.phases))
+	for name, d := range t.phases {
+		ms[name] = float64(d) / float64(time.Millisecond)
+	}
+	return e.AddAny(key, ms)
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimer(t *testing.T) {
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl, WithClock(clock))
+		tm := l.NewTimer()
+		now = now.Add(1500 * time.Microsecond)
+		tm.Mark("parse")
+		now = now.Add(250 * time.Microsecond)
+		tm.Mark("validate")
+		now = now.Add(time.Millisecond)
+		tm.Mark("parse")
+		tm.AddTo(l.Info(), "phases").Flush("")
+		s := sb.String()
+		assert.Contains(t, s, "parse", "%s: Phases should be added", implName(impl))
+		assert.Contains(t, s, "2.5", "%s: Durations of the same phase should be summed up", implName(impl))
+		assert.Contains(t, s, "0.25", "%s: Durations should be elapsed since the previous mark", implName(impl))
+	}
+
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.NewTimer().AddTo(l.Info(), "phases").Flush("")
+	assert.NotContains(t, sb.String(), "phases", "Timers without phases should add nothing")
+}
+
+func TestTimer_Decorators(t *testing.T) {
+	l, ch := NewChannel(DebugLevel, 1, GuardReuse(), BurstSummary(time.Second))
+	tm := l.WithMessagePrefix("tag").NewTimer()
+	tm.Mark("step")
+	tm.AddTo(l.Info(), "phases").Flush("")
+	e := <-ch
+	assert.Contains(t, e.Fields["phases"], "step", "Decorated loggers should return a timer")
+}
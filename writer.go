@@ -0,0 +1,245 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// lineWriter is an io.WriteCloser that logs every line written to it as a separate entry
+type lineWriter struct {
+	mu     sync.Mutex
+	l      Logger
+	lvl    Level
+	stream string
+	buf    []byte
+}
+
+func newLineWriter(l Logger, lvl Level, stream string) *lineWriter {
+	return &lineWriter{l: l, lvl: lvl, stream: stream}
+}
+
+// Write logs each complete line in p. An incomplete line at the end of p is kept until the next call to Write or
+// Close.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.log(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Close logs the remaining incomplete line, if any
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.log(w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+func (w *lineWriter) log(line []byte) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	w.l.Level(w.lvl).AddStr("stream", w.stream).Flush(string(line))
+}
+
+// syncWriter flushes w if it supports it, e.g. if it is an *os.File
+func syncWriter(w io.Writer) error {
+	if s, ok := w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// crlfWriter buffers output until the end of a line and converts line endings to CRLF
+type crlfWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	buf    []byte
+	lastCR bool
+}
+
+func newCRLFWriter(w io.Writer) *crlfWriter {
+	return &crlfWriter{w: w}
+}
+
+// Write converts the line endings in p to CRLF and writes all complete lines to the underlying writer at once.
+func (w *crlfWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, b := range p {
+		if b == '\n' && !w.lastCR {
+			w.buf = append(w.buf, '\r')
+		}
+		w.buf = append(w.buf, b)
+		w.lastCR = b == '\r'
+	}
+	i := bytes.LastIndexByte(w.buf, '\n')
+	if i < 0 {
+		return len(p), nil
+	}
+	_, err := w.w.Write(w.buf[:i+1])
+	w.buf = append(w.buf[:0], w.buf[i+1:]...)
+	return len(p), err
+}
+
+// Sync writes an incomplete line and syncs the underlying writer if it supports it.
+func (w *crlfWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		if _, err := w.w.Write(w.buf); err != nil {
+			return err
+		}
+		w.buf = w.buf[:0]
+	}
+	return syncWriter(w.w)
+}
+
+// lockedWriter serializes the writes to a writer that isn't safe for concurrent use, so that entries written by
+// concurrent goroutines don't interleave
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// lockWriter returns w wrapped in a lockedWriter, unless w is known to be safe for concurrent use. Files are, as each
+// entry is written with a single call to Write.
+func lockWriter(w io.Writer) io.Writer {
+	switch w.(type) {
+	case *os.File, *lockedWriter, *crlfWriter, *auditWriter:
+		return w
+	}
+	return &lockedWriter{w: w}
+}
+
+// Write writes p to the underlying writer with a single call to Write
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// Sync syncs the underlying writer if it supports it
+func (w *lockedWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return syncWriter(w.w)
+}
+
+// Close closes the underlying writer if it supports it
+func (w *lockedWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if c, ok := w.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// nestedTopLevel are the members of a JSON entry that nestWriter keeps at the top level. If a member occurs more than
+// once because a field has the same name, zerolog writes the level first and the time and the message last.
+var nestedTopLevel = map[string]bool{"time": true, "level": true, "msg": true, "message": true}
+
+// nestWriter moves the fields of each JSON entry written to it into a nested object, see NestFields
+type nestWriter struct {
+	w   io.Writer
+	key string
+}
+
+// Write writes the entry in p with all members except "time", "level", "msg" and "message" moved into an object
+// under w.key, in their original order. Entries that aren't JSON objects are written unchanged.
+func (w *nestWriter) Write(p []byte) (int, error) {
+	line := bytes.TrimRight(p, "\r\n")
+	b, ok := nestJSON(line, w.key)
+	if !ok {
+		return w.w.Write(p)
+	}
+	b = append(b, p[len(line):]...)
+	if _, err := w.w.Write(b); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sync syncs the underlying writer if it supports it
+func (w *nestWriter) Sync() error {
+	return syncWriter(w.w)
+}
+
+// nestJSON returns the JSON object in line with the members that aren't in nestedTopLevel moved into an object
+// under key. It returns false if line isn't a JSON object.
+func nestJSON(line []byte, key string) ([]byte, bool) {
+	if len(line) == 0 || line[0] != '{' {
+		return nil, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if _, err := dec.Token(); err != nil {
+		return nil, false
+	}
+	type member struct {
+		name string
+		raw  json.RawMessage
+	}
+	var members []member
+	// real is the index of the member of each name in nestedTopLevel that stays at the top level
+	real := make(map[string]int)
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		name, ok := t.(string)
+		if !ok {
+			return nil, false
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, false
+		}
+		if _, seen := real[name]; nestedTopLevel[name] && (name != "level" || !seen) {
+			real[name] = len(members)
+		}
+		members = append(members, member{name, raw})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, false
+	}
+	var top, nested bytes.Buffer
+	for i, m := range members {
+		buf := &nested
+		if j, ok := real[m.name]; ok && i == j {
+			buf = &top
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(m.name)
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(m.raw)
+	}
+	b := append([]byte{'{'}, top.Bytes()...)
+	if nested.Len() > 0 {
+		if top.Len() > 0 {
+			b = append(b, ',')
+		}
+		k, _ := json.Marshal(key)
+		b = append(b, k...)
+		b = append(b, ":{"...)
+		b = append(b, nested.Bytes()...)
+		b = append(b, '}')
+	}
+	return append(b, '}'), true
+}
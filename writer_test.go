@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineWriter(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		w := New(&sb, DebugLevel, impl).PipeWriter(WarnLevel, "child.stdout")
+		fmt.Fprint(w, "first li")
+		assert.Empty(t, sb.String(), "Incomplete lines should not be logged")
+		fmt.Fprint(w, "ne\r\nsecond line\nthird")
+		s := sb.String()
+		assert.Contains(t, s, "first line", "Complete lines should be logged")
+		assert.NotContains(t, s, "first line\r", "Carriage returns should be trimmed")
+		assert.Contains(t, s, "second line", "Complete lines should be logged")
+		assert.NotContains(t, s, "third", "Incomplete lines should not be logged")
+		assert.NoError(t, w.Close())
+		s = sb.String()
+		assert.Contains(t, s, "third", "Remaining line should be logged on close")
+		assert.Equal(t, 3, strings.Count(s, "child.stdout"), "Each line should contain the stream")
+	}
+}
+
+func TestCRLFWriter(t *testing.T) {
+	var sb syncBuffer
+	w := newCRLFWriter(&sb)
+	fmt.Fprint(w, "first li")
+	assert.Empty(t, sb.String(), "Incomplete lines should be buffered")
+	fmt.Fprint(w, "ne\nsecond line\r\nthi")
+	assert.Equal(t, "first line\r\nsecond line\r\n", sb.String(), "Line endings should be converted once")
+	assert.NoError(t, w.Sync())
+	assert.Equal(t, "first line\r\nsecond line\r\nthi", sb.String(), "Sync should write the incomplete line")
+	assert.Equal(t, 1, sb.syncs, "Sync should sync the underlying writer")
+}
+
+func TestConsoleWriter(t *testing.T) {
+	var sb strings.Builder
+	New(&sb, DebugLevel, ZeroLogBackend, ConsoleWriter()).Info().Flush("message")
+	s := sb.String()
+	assert.Contains(t, s, "message", "Entry should be written")
+	if runtime.GOOS == "windows" {
+		assert.True(t, strings.HasSuffix(s, "\r\n"), "Line endings should be CRLF on Windows")
+	} else {
+		assert.False(t, strings.HasSuffix(s, "\r\n"), "Line endings should not change on other platforms")
+	}
+}
+
+func TestNew_ConcurrentWrites(t *testing.T) {
+	for _, impl := range backends() {
+		var sb strings.Builder
+		l := New(&sb, DebugLevel, impl)
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					l.Info().AddInt("goroutine", i).AddStr("payload", strings.Repeat("x", 100)).Flush("message")
+				}
+			}(i)
+		}
+		wg.Wait()
+		lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+		assert.Len(t, lines, 5000, "Each entry should be written on its own line")
+		for _, line := range lines {
+			if !assert.Contains(t, line, strings.Repeat("x", 100), "Lines should not be split") {
+				break
+			}
+			if impl != LogrusBackend && !assert.True(t, json.Valid([]byte(line)), "Line should be a single entry") {
+				break
+			}
+		}
+	}
+}
@@ -1,9 +1,23 @@
 package logger
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/juju/errors"
 	"github.com/rs/zerolog"
@@ -27,20 +41,162 @@ func ltoz(level Level) zerolog.Level {
 	panic(fmt.Sprintf("Can't map level %d to zerolog level", level))
 }
 
-func newZeroLog(w io.Writer, lvl Level) Logger {
-	zerolog.TimeFieldFormat = ""
-	l := zerolog.New(w).Level(ltoz(lvl)).With().Timestamp().Logger()
-	return &zLog{&l}
+// rawField is a field of an event with its encoded value
+type rawField struct {
+	key string
+	val json.RawMessage
+}
+
+// rawFields returns the fields of the event e in their order. zerolog doesn't expose the fields of an event, so e is
+// written to a buffer and split into its fields again, it can't be used afterwards.
+func rawFields(e *zerolog.Event) []rawField {
+	buf := bytes.NewBuffer(make([]byte, 0, 512))
+	l := zerolog.New(buf)
+	l.Log().Dict("e", e).Msg("")
+	// skip the start of the outer object, the key "e" and the start of e
+	b := buf.Bytes()
+	i := len(`{"e":{`)
+	fs := make([]rawField, 0, 8)
+	for i < len(b) && b[i] == '"' {
+		end := skipJSONString(b, i)
+		if end >= len(b) || b[end] != ':' {
+			break
+		}
+		f := rawField{key: string(b[i+1 : end-1])}
+		if bytes.IndexByte(b[i:end], '\\') >= 0 {
+			if err := json.Unmarshal(b[i:end], &f.key); err != nil {
+				break
+			}
+		}
+		i = skipJSONValue(b, end+1)
+		f.val = b[end+1 : i]
+		fs = append(fs, f)
+		if i < len(b) && b[i] == ',' {
+			i++
+		}
+	}
+	return fs
+}
+
+// skipJSONString returns the index after the JSON string that starts at b[i]
+func skipJSONString(b []byte, i int) int {
+	for i++; i < len(b); i++ {
+		switch b[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return i
+}
+
+// skipJSONValue returns the index after the JSON value that starts at b[i]
+func skipJSONValue(b []byte, i int) int {
+	depth := 0
+	for i < len(b) {
+		switch b[i] {
+		case '"':
+			i = skipJSONString(b, i)
+			continue
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return i
+			}
+		}
+		i++
+	}
+	return i
+}
+
+// addRawFields adds the fields fs to e
+func addRawFields(e *zerolog.Event, fs []rawField) {
+	for _, f := range fs {
+		e.RawJSON(f.key, f.val)
+	}
+}
+
+// contextKeys returns the number of fields in the context of l, e.g. of a logger passed to FromZerolog
+func contextKeys(l *zerolog.Logger) int {
+	c := l.Output(nil).Sample(nil).Level(zerolog.DebugLevel)
+	return len(rawFields(c.Log()))
+}
+
+// timeFormatOnce sets the global time format of zerolog only once, so that creating a logger doesn't race with
+// loggers writing entries
+var timeFormatOnce sync.Once
+
+func newZeroLog(w io.Writer, lvl Level, c *config) Logger {
+	timeFormatOnce.Do(func() { zerolog.TimeFieldFormat = "" })
+	l := zerolog.New(w).Level(ltoz(lvl))
+	return &zLog{writer: &l, cfg: c, timestamp: true, out: w}
 }
 
 type zLog struct {
 	writer *zerolog.Logger
+	cfg    *config
+	// timestamp adds the field "time" to each entry. It is only set for loggers created by New, loggers passed to
+	// FromZerolog have their own timestamp configuration.
+	timestamp bool
+	// out is the writer passed to New, it is synced by Sync
+	out io.Writer
+	// keys is the number of fields in the context of writer, see zEntry.fields
+	keys int
 }
 
+var _ Logger = (*zLog)(nil)
+
 // WithField returns a new Logger that always logs the specified field
 func (z *zLog) WithField(key, value string) Logger {
 	writer := z.writer.With().Str(key, value).Logger()
-	return &zLog{writer: &writer}
+	return &zLog{writer: &writer, cfg: z.cfg, timestamp: z.timestamp, out: z.out, keys: z.keys + 1}
+}
+
+// WithAny returns a new Logger that always logs the specified field. Unlike WithField, the value can be of any type
+func (z *zLog) WithAny(key string, value interface{}) Logger {
+	writer := z.writer.With().Interface(key, value).Logger()
+	return &zLog{writer: &writer, cfg: z.cfg, timestamp: z.timestamp, out: z.out, keys: z.keys + 1}
+}
+
+// WithMessagePrefix returns a new Logger that prefixes the message of each entry with prefix and ": ", e.g. for
+// consumers that grep for a tag instead of parsing fields. Empty messages are prefixed, too.
+func (z *zLog) WithMessagePrefix(prefix string) Logger {
+	return newPrefix(z, prefix)
+}
+
+// WithLevelRemap returns a new Logger that changes the level of its entries according to remap on Flush, e.g.
+// {ErrorLevel: WarnLevel} to keep the errors of a noisy dependency from triggering alerts. The level check of the
+// logger applies to the level before remapping: an entry is only written if its original level is enabled, and
+// then it's written at the new level. An entry remapped to a level below the level of the logger is dropped by the
+// logger, though. Entries at fatal and panic level are never remapped, and no level is remapped to them.
+func (z *zLog) WithLevelRemap(remap map[Level]Level) Logger {
+	return newRemap(z, remap)
+}
+
+// WithLabels returns a new Logger that adds labels to each entry like AddLabels. Labels of nested calls and of
+// AddLabels are merged into one object, later labels take precedence.
+func (z *zLog) WithLabels(labels map[string]string) Logger {
+	return newLabels(z, labels)
+}
+
+// WithEnvironment returns a new Logger that always logs the deployment environment env like "prod" under the key
+// "env", e.g. the result of DetectEnvironment. An empty env is logged as "unknown".
+func (z *zLog) WithEnvironment(env string) Logger {
+	return z.WithField("env", environment(env))
+}
+
+// WithCorrelation returns a new Logger that logs the correlation id under the key "correlation_id", e.g. the result
+// of ExtractCorrelation for an incoming request. InjectCorrelation sets the id in the headers of outgoing requests.
+// An empty id returns the logger unchanged.
+func (z *zLog) WithCorrelation(id string) Logger {
+	return newCorrelation(z, id)
 }
 
 // Level creates a new Entry with the specified Level
@@ -65,57 +221,279 @@ func (z *zLog) Level(lvl Level) Entry {
 
 // Debug creates a new Entry with level Debug
 func (z *zLog) Debug() Entry {
-	return &zEntry{z.writer.Debug()}
+	if !z.cfg.sample(DebugLevel) {
+		return nopEntry{}
+	}
+	return z.cfg.sampled(z.entry(DebugLevel), DebugLevel)
 }
 
 // Info creates a new Entry with level Info
 func (z *zLog) Info() Entry {
-	return &zEntry{z.writer.Info()}
+	if !z.cfg.sample(InfoLevel) {
+		return nopEntry{}
+	}
+	return z.cfg.sampled(z.entry(InfoLevel), InfoLevel)
 }
 
 // Warn creates a new Entry with level Warn
 func (z *zLog) Warn() Entry {
-	return &zEntry{z.writer.Warn()}
+	if !z.cfg.sample(WarnLevel) {
+		return nopEntry{}
+	}
+	return z.cfg.sampled(z.entry(WarnLevel), WarnLevel)
 }
 
 // Error creates a new Entry with level Error
 func (z *zLog) Error() Entry {
-	return &zEntry{z.writer.Error()}
+	if !z.cfg.sample(ErrorLevel) {
+		return nopEntry{}
+	}
+	return z.cfg.sampled(z.entry(ErrorLevel), ErrorLevel)
 }
 
-// Fatal creates a new Entry with level Fatal. Executing a log at fatal level exits the application with exit code 1.
+// Fatal creates a new Entry with level Fatal. Executing a log at fatal level exits the application with exit code 1,
+// or the code set with FatalExitCode.
 func (z *zLog) Fatal() Entry {
-	return &zEntry{z.writer.Fatal()}
+	return z.entry(FatalLevel)
 }
 
 // Panic creates a new Entry with level Panic. Executing a log at panic level will call panic().
 func (z *zLog) Panic() Entry {
-	return &zEntry{z.writer.Panic()}
+	return z.entry(PanicLevel)
+}
+
+// entry creates a new Entry with level lvl
+func (z *zLog) entry(lvl Level) *zEntry {
+	e := &zEntry{writer: z.writer, keys: z.keys, lvl: lvl, cfg: z.cfg, time: z.timestamp, retention: z.cfg.retention,
+		created: timeNow(z.cfg)}
+	e.open()
+	return e
+}
+
+// PipeWriter returns a writer that logs every line written to it as an entry at the specified level. The entries
+// have the field "stream" set to stream. The last line is logged on Close even if it doesn't end with a newline.
+func (z *zLog) PipeWriter(lvl Level, stream string) io.WriteCloser {
+	return newLineWriter(z, lvl, stream)
+}
+
+// Operation logs the start of an operation at debug level and returns a function that logs its end together with
+// the duration of the operation. The end is logged at info level, or at error level if a non-nil error is passed.
+// Use it as "defer done(err)". Both entries have the same id under the key "operation_id", see WithIDGenerator.
+func (z *zLog) Operation(name string) func(err error) {
+	return operation(z, name)
+}
+
+// NewTimer returns a Timer that starts now, e.g. "t := l.NewTimer(); parse(); t.Mark("parse")". Add the phases to an
+// entry with Timer.AddTo.
+func (z *zLog) NewTimer() *Timer {
+	return newTimer(z.cfg)
+}
+
+// StartHeartbeat logs msg at info level every interval with the time since StartHeartbeat was called under the key
+// "uptime" and the number of goroutines under the key "goroutines", e.g. to show that a service is alive. It returns
+// a function that stops the heartbeat and waits until an entry that is being written is done, calling it more than
+// once is safe. Each call starts an independent heartbeat. If interval isn't greater than zero, nothing is logged
+// and stop does nothing.
+func (z *zLog) StartHeartbeat(interval time.Duration, msg string) (stop func()) {
+	return heartbeat(z, interval, msg)
+}
+
+// Go runs fn in a new goroutine that recovers from a panic of fn and logs it at error level with the panic value
+// under the key "panic" and the stack under the key "stack", e.g. for background workers, whose panics would crash the
+// process without a log. By default, the panic is swallowed and the goroutine ends. With the option RepanicInGo, the
+// goroutine panics again after logging, which crashes the process.
+func (z *zLog) Go(fn func()) {
+	goSafe(z, fn)
+}
+
+// Sync flushes buffered entries to the destination without closing the logger. For loggers created by New with the
+// zerolog or gelf backend, the writer is synced if it has a method "Sync() error", like *os.File. Sync does nothing
+// for the logrus backend, for channel loggers and for loggers passed to FromZerolog.
+func (z *zLog) Sync() error {
+	return syncWriter(z.out)
+}
+
+// config returns the options of the logger
+func (z *zLog) config() *config {
+	return z.cfg
 }
 
+// zEntry writes its fields directly to a zerolog event for the level it was created with. If the level of the logger
+// doesn't write that level, the event is detached from the logger, so that At can still raise the entry. Fields are
+// only moved to a new event if the level changes, see settle.
 type zEntry struct {
-	entry *zerolog.Event
+	ev *zerolog.Event
+	// evLvl is the level ev was created for, ev is detached from the logger if parked is set
+	evLvl  Level
+	parked bool
+	writer *zerolog.Logger
+	// keys is the number of fields in the context of writer
+	keys int
+	lvl  Level
+	cfg  *config
+	time bool
+	// retention is added under the key "retention" on Flush, see Retention
+	retention string
+	// created is the time the entry was created, see WithTimeCapture
+	created time.Time
+}
+
+var _ Entry = (*zEntry)(nil)
+
+// open creates the event of the entry for its level, or a detached event if the level isn't written
+func (z *zEntry) open() {
+	z.ev, z.evLvl = z.event(z.writer), z.lvl
+	z.parked = z.ev == nil
+	if z.parked {
+		z.ev = zerolog.Dict()
+	}
+}
+
+// event creates an event of l for the level of the entry. It's nil if l doesn't write the level.
+func (z *zEntry) event(l *zerolog.Logger) *zerolog.Event {
+	if z.cfg.ecs {
+		// ECS writes the level as a field on Flush
+		return l.Log()
+	}
+	return l.WithLevel(ltoz(z.lvl))
+}
+
+// settle returns the event for the current level of the entry with all fields, or nil if the level isn't written.
+// The fields are only moved if they were added to an event for another level or to a detached event.
+func (z *zEntry) settle() *zerolog.Event {
+	if !z.parked && (z.cfg.ecs || z.evLvl == z.lvl) {
+		return z.ev
+	}
+	e := z.event(z.writer)
+	if e == nil {
+		z.release()
+		return nil
+	}
+	addRawFields(e, z.fields())
+	z.ev, z.evLvl, z.parked = e, z.lvl, false
+	return e
+}
+
+// fields removes the fields of the entry from its event and returns them. The event can't be used afterwards.
+func (z *zEntry) fields() []rawField {
+	fs := rawFields(z.ev)
+	if z.parked {
+		return fs
+	}
+	// an event of the logger starts with the level and the context of the logger
+	n := z.keys
+	if !z.cfg.ecs {
+		n++
+	}
+	if n > len(fs) {
+		return nil
+	}
+	return fs[n:]
+}
+
+// release returns a detached event to the pool of zerolog. Events of the logger are left to the garbage collector,
+// because writing them would run the hooks of the logger.
+func (z *zEntry) release() {
+	if z.parked {
+		z.ev.Msg("")
+	}
+	z.ev = nil
 }
 
 // Flush writes the entry as a single log statement. Optionally, a message can be added which will
 // be included in the final log entry
 func (z *zEntry) Flush(msg string) {
-	z.entry.Msg(msg)
+	if z.cfg.reportCaller {
+		z.ev.Str("caller", caller(z.cfg.callerSkip))
+	}
+	if z.cfg.goroutineID {
+		z.ev.Uint64("goroutine", goroutineID())
+	}
+	var b []byte
+	if z.lvl == PanicLevel && z.cfg.onPanic != nil {
+		b, _ = z.Bytes()
+	}
+	if e := z.settle(); e != nil {
+		if !z.cfg.ecs || z.lvl <= z.cfg.ecsLevel {
+			z.write(e, msg)
+		}
+	}
+	if z.lvl == PanicLevel {
+		z.cfg.panicked(func() map[string]interface{} {
+			return jsonFields(b, "")
+		})
+		panic(msg)
+	} else if z.lvl == FatalLevel {
+		exitFunc(z.cfg.exitCode)
+	}
+}
+
+// write adds the fields that are set on Flush to e and writes it with the message msg
+func (z *zEntry) write(e *zerolog.Event, msg string) {
+	if z.retention != "" {
+		e.Str("retention", z.retention)
+	}
+	if z.cfg.ecs {
+		ecsWrite(e, z.cfg, z.lvl, msg, z.time, z.created)
+		return
+	}
+	if z.time {
+		t := z.cfg.stamp(z.created)
+		if ts, ok := z.cfg.epoch(t); ok {
+			e.Int64(zerolog.TimestampFieldName, ts)
+		} else {
+			e.Time(zerolog.TimestampFieldName, t)
+		}
+	}
+	e.Msg(msg)
+}
+
+// Bytes returns the entry as it would be written by Flush without a message, but doesn't write it. The level of the
+// logger isn't applied. The entry can still be flushed afterwards.
+func (z *zEntry) Bytes() ([]byte, error) {
+	fs := z.fields()
+	// keep the fields in a detached event, Flush moves them to an event of the logger again
+	z.ev, z.parked = zerolog.Dict(), true
+	addRawFields(z.ev, fs)
+	var buf bytes.Buffer
+	l := z.writer.Output(&buf).Level(zerolog.DebugLevel)
+	e := z.event(&l)
+	addRawFields(e, fs)
+	z.write(e, "")
+	return buf.Bytes(), nil
+}
+
+// Discard abandons the entry without writing it. The entry must not be used afterwards.
+func (z *zEntry) Discard() {
+	z.release()
+}
+
+// At changes the level of the entry. The level is evaluated when the entry is flushed.
+func (z *zEntry) At(lvl Level) Entry {
+	z.lvl = validLevel(lvl)
+	return z
+}
+
+// NoTime removes the time field that is added to each entry automatically, e.g. to add the time of an event instead.
+func (z *zEntry) NoTime() Entry {
+	z.time = false
+	return z
 }
 
 // AddFields adds a range of fields to the log statement
 func (z *zEntry) AddFields(fs map[string]interface{}) Entry {
-	z.entry = z.entry.Fields(fs)
+	fs = z.cfg.fields(fs)
+	z.ev.Fields(fs)
 	return z
 }
 
 // AddErr adds an error to the log statement. The error will have the key "err". An error stack will be included
-// under the key "err_stack"
+// under the key "err_stack". Use the option ErrorKeys to change the keys.
 func (z *zEntry) AddErr(err error) Entry {
 	msg := err.Error()
 	st := errors.ErrorStack(err)
-	z.entry = z.entry.Str("err", msg)
-	z.entry = z.entry.Str("err_stack", st)
+	z.ev.Str(z.cfg.errKey, msg)
+	z.ev.Str(z.cfg.errStackKey, st)
 	return z
 }
 
@@ -123,44 +501,473 @@ func (z *zEntry) AddErr(err error) Entry {
 func (z *zEntry) AddError(key string, val error) Entry {
 	msg := val.Error()
 	st := errors.ErrorStack(val)
-	z.entry = z.entry.Str(key, msg)
-	z.entry = z.entry.Str(key+"_stack", st)
-	z.entry = z.entry.AnErr(key, val)
+	z.ev.Str(key, msg)
+	z.ev.Str(key+"_stack", st)
+	z.ev.AnErr(key, val)
 	return z
 }
 
 // AddBool adds a bool value to the log statement.
 func (z *zEntry) AddBool(key string, val bool) Entry {
-	z.entry = z.entry.Bool(key, val)
+	if z.cfg.omitEmpty && !val {
+		return z
+	}
+	z.ev.Bool(key, val)
 	return z
 }
 
 // AddInt adds an integer value to the log statement.
 func (z *zEntry) AddInt(key string, val int) Entry {
-	z.entry = z.entry.Int(key, val)
+	if z.cfg.omitEmpty && val == 0 {
+		return z
+	}
+	if z.cfg.maxInt != 0 {
+		if s, ok := largeInt(val, z.cfg.maxInt); ok {
+			z.ev.Str(key, s)
+			return z
+		}
+	}
+	z.ev.Int(key, val)
 	return z
 }
 
 // AddStr adds a string value to the log statement.
 func (z *zEntry) AddStr(key string, val string) Entry {
-	z.entry = z.entry.Str(key, val)
+	if z.cfg.omitEmpty && val == "" {
+		return z
+	}
+	z.ev.Str(key, val)
 	return z
 }
 
 // AddTime adds a time value to the log statement.
 func (z *zEntry) AddTime(key string, val time.Time) Entry {
-	z.entry = z.entry.Time(key, val)
+	if z.cfg.omitEmpty && val.IsZero() {
+		return z
+	}
+	z.ev.Time(key, val)
 	return z
 }
 
 // AddDur adds a duration value to the log statement.
 func (z *zEntry) AddDur(key string, val time.Duration) Entry {
-	z.entry = z.entry.Dur(key, val)
+	if z.cfg.omitEmpty && val == 0 {
+		return z
+	}
+	z.ev.Dur(key, val)
 	return z
 }
 
 // AddAny adds any value to the log statement.
 func (z *zEntry) AddAny(key string, val interface{}) Entry {
-	z.entry = z.entry.Interface(key, val)
+	z.ev.Interface(key, z.cfg.quote(val))
+	return z
+}
+
+// AddJSONRaw adds pre-serialized JSON to the log statement without encoding it again. If raw is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (z *zEntry) AddJSONRaw(key string, raw []byte) Entry {
+	if !json.Valid(raw) {
+		z.ev.Str(key+"_invalid", string(raw))
+		return z
+	}
+	z.ev.RawJSON(key, raw)
+	return z
+}
+
+// AddCount adds a count to the log statement. The number is stored under key, a human readable form like "3 items"
+// is stored under the key "${key}_human"
+func (z *zEntry) AddCount(key string, n int, singular, plural string) Entry {
+	z.ev.Int(key, n)
+	z.ev.Str(key+"_human", pluralize(n, singular, plural))
+	return z
+}
+
+// AddQuery adds a database query to the log statement. The statement, its arguments as JSON array, the number of
+// rows and the duration are stored under the keys "db.sql", "db.args", "db.rows" and "db.duration"
+func (z *zEntry) AddQuery(sql string, args []interface{}, rows int, dur time.Duration) Entry {
+	z.ev.Str("db.sql", sql)
+	z.ev.Interface("db.args", args)
+	z.ev.Int("db.rows", rows)
+	z.ev.Dur("db.duration", dur)
+	return z
+}
+
+// AddTraceparent adds the trace id and span id of the W3C traceparent header in headers under the keys
+// "${key}_trace_id" and "${key}_span_id". A missing or malformed header adds nothing.
+func (z *zEntry) AddTraceparent(key string, headers http.Header) Entry {
+	if traceID, spanID, ok := traceparent(headers); ok {
+		z.ev.Str(key+"_trace_id", traceID)
+		z.ev.Str(key+"_span_id", spanID)
+	}
+	return z
+}
+
+// AddMetric adds a metric to the log statement. Name, value and tags are stored as a nested object under the key
+// "metric", so that log collectors can route the statement to a metrics sink.
+func (z *zEntry) AddMetric(name string, value float64, tags map[string]string) Entry {
+	z.ev.Interface("metric", metric{name, value, tags})
 	return z
 }
+
+// AddHex adds a byte slice to the log statement, encoded as hex string.
+func (z *zEntry) AddHex(key string, val []byte) Entry {
+	z.ev.Str(key, hex.EncodeToString(val))
+	return z
+}
+
+// AddBase64 adds a byte slice to the log statement, encoded as base64 string with standard encoding.
+func (z *zEntry) AddBase64(key string, val []byte) Entry {
+	z.ev.Str(key, base64.StdEncoding.EncodeToString(val))
+	return z
+}
+
+// AddElapsed adds the duration since the specified time to the log statement.
+func (z *zEntry) AddElapsed(key string, since time.Time) Entry {
+	return z.AddDur(key, timeNow(z.cfg).Sub(since))
+}
+
+// AddDurHuman adds a duration to the log statement as human readable string like "1.2s" or "3m04s". Use AddDur for
+// values that should be queryable.
+func (z *zEntry) AddDurHuman(key string, d time.Duration) Entry {
+	return z.AddStr(key, humanDur(d))
+}
+
+// AddErrN adds an error to the log statement like AddErr, but the error stack under the key "err_stack" is
+// truncated to the first maxFrames frames. The error message is kept intact.
+func (z *zEntry) AddErrN(err error, maxFrames int) Entry {
+	z.ev.Str(z.cfg.errKey, err.Error())
+	z.ev.Str(z.cfg.errStackKey, truncateStack(errors.ErrorStack(err), maxFrames))
+	return z
+}
+
+// AddSensitive adds the hex encoded SHA-256 hash of val under the key "${key}_hash" to the log statement. The raw
+// value is never logged. Use the options SensitiveSalt and SensitiveHashLen to configure the hash.
+func (z *zEntry) AddSensitive(key string, val string) Entry {
+	return z.AddStr(key+"_hash", z.cfg.hash(val))
+}
+
+// AddBytesLen adds the length of a byte slice to the log statement instead of its content. A nil slice has length 0.
+func (z *zEntry) AddBytesLen(key string, val []byte) Entry {
+	z.ev.Int(key, len(val))
+	return z
+}
+
+// AddStrLen adds the number of runes of a string to the log statement instead of the string itself.
+func (z *zEntry) AddStrLen(key string, val string) Entry {
+	z.ev.Int(key, utf8.RuneCountInString(val))
+	return z
+}
+
+// AddJSONString adds a JSON encoded string to the log statement without encoding it again. If s is not valid JSON,
+// it is added as a string under the key "${key}_invalid"
+func (z *zEntry) AddJSONString(key, s string) Entry {
+	return z.AddJSONRaw(key, []byte(s))
+}
+
+// AddRetry adds the state of a retry loop to the log statement. The attempt, the maximum number of attempts and the
+// backoff before the next attempt are stored under the keys "retry.attempt", "retry.max" and "retry.backoff"
+func (z *zEntry) AddRetry(attempt, max int, backoff time.Duration) Entry {
+	z.ev.Int("retry.attempt", attempt)
+	z.ev.Int("retry.max", max)
+	z.ev.Dur("retry.backoff", backoff)
+	return z
+}
+
+// AddDeadline adds the time remaining until the deadline of ctx to the log statement. It is negative if the deadline
+// has passed. If ctx has no deadline, nothing is added.
+func (z *zEntry) AddDeadline(key string, ctx context.Context) Entry {
+	if d, ok := ctx.Deadline(); ok {
+		return z.AddDur(key, d.Sub(timeNow(z.cfg)))
+	}
+	return z
+}
+
+// AddRequest adds the method, path, remote address, user agent and host of r to the log statement under the keys
+// "http.method", "http.path", "http.remote_addr", "http.user_agent" and "http.host". Other headers are never logged.
+func (z *zEntry) AddRequest(r *http.Request) Entry {
+	return addRequest(z, r, z.cfg.forwardedFor)
+}
+
+// AddResponse adds the status code, the size of the body and the duration of an HTTP response to the log statement
+// under the keys "http.status", "http.bytes" and "http.duration". The class of the status like "5xx" is stored under
+// the key "http.status_class".
+func (z *zEntry) AddResponse(status, bytes int, dur time.Duration) Entry {
+	return addResponse(z, status, bytes, dur)
+}
+
+// AddSlice adds a slice or an array of any element type to the log statement as JSON array. Other values, or slices
+// that can't be encoded as JSON, are added like with AddAny.
+func (z *zEntry) AddSlice(key string, vals interface{}) Entry {
+	if b, ok := sliceJSON(vals); ok {
+		return z.AddJSONRaw(key, b)
+	}
+	return z.AddAny(key, vals)
+}
+
+// AddBoolPtr adds the value of a bool pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (z *zEntry) AddBoolPtr(key string, val *bool) Entry {
+	if val == nil {
+		if z.cfg.nilNull {
+			return z.AddAny(key, nil)
+		}
+		return z
+	}
+	return z.AddBool(key, *val)
+}
+
+// AddIntPtr adds the value of an integer pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (z *zEntry) AddIntPtr(key string, val *int) Entry {
+	if val == nil {
+		if z.cfg.nilNull {
+			return z.AddAny(key, nil)
+		}
+		return z
+	}
+	return z.AddInt(key, *val)
+}
+
+// AddStrPtr adds the value of a string pointer to the log statement. A nil pointer omits the field, or adds null
+// with the option NilAsNull.
+func (z *zEntry) AddStrPtr(key string, val *string) Entry {
+	if val == nil {
+		if z.cfg.nilNull {
+			return z.AddAny(key, nil)
+		}
+		return z
+	}
+	return z.AddStr(key, *val)
+}
+
+// AddCaller adds the location that called it as "file:line" under the key "caller" and the calling function under
+// the key "caller_func" to the log statement. Unlike the option ReportCaller, it only applies to this entry.
+func (z *zEntry) AddCaller() Entry {
+	return addCaller(z, 0)
+}
+
+// AddCallerSkip adds the caller like AddCaller, but skips n additional frames, e.g. in helpers that wrap an Entry.
+func (z *zEntry) AddCallerSkip(n int) Entry {
+	return addCaller(z, n)
+}
+
+// AddIntThreshold adds a value to the log statement like AddInt and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (z *zEntry) AddIntThreshold(key string, val, threshold int, flagKey string) Entry {
+	z.AddInt(key, val)
+	return thresholdFlag(z, flagKey, val > threshold, z.cfg.flagBelow)
+}
+
+// AddDurThreshold adds a value to the log statement like AddDur and additionally flagKey as true if the value exceeds
+// threshold, e.g. to mark slow requests. Below the threshold, the flag is omitted, or false with the option
+// FlagBelowThreshold.
+func (z *zEntry) AddDurThreshold(key string, val, threshold time.Duration, flagKey string) Entry {
+	z.AddDur(key, val)
+	return thresholdFlag(z, flagKey, val > threshold, z.cfg.flagBelow)
+}
+
+// AddMoney adds an amount of money in minor units of an ISO 4217 currency, e.g. cents for "USD", to the log statement.
+// The amount and the currency are stored under the keys "${key}.amount_minor" and "${key}.currency", the formatted
+// amount like "$12.99" under the key "${key}.display". Unknown currencies, including an empty code, are formatted
+// with two decimal places and flagged with true under the key "${key}.currency_unknown".
+func (z *zEntry) AddMoney(key string, minorUnits int64, currency string) Entry {
+	currency = strings.ToUpper(currency)
+	z.ev.Int64(key+".amount_minor", minorUnits)
+	z.ev.Str(key+".currency", currency)
+	display, known := formatMoney(minorUnits, currency)
+	z.ev.Str(key+".display", display)
+	if !known {
+		z.ev.Bool(key+".currency_unknown", true)
+	}
+	return z
+}
+
+// AddErrChain adds the message of an error under key, the number of errors it wraps under the key "${key}_depth" and
+// the type names of the errors in its chain, starting with err, under the key "${key}_types" to the log statement.
+// Both the standard library's wrapping and juju/errors are followed. A nil error adds nothing.
+func (z *zEntry) AddErrChain(key string, err error) Entry {
+	if err == nil {
+		return z
+	}
+	depth, types := errChain(err)
+	z.ev.Str(key, err.Error())
+	z.ev.Int(key+"_depth", depth)
+	z.ev.Strs(key+"_types", types)
+	return z
+}
+
+// AddInterval adds a time window to the log statement. Start and end are stored in UTC under the keys "${key}.start"
+// and "${key}.end", the duration between them under the key "${key}.duration". If end is before start, the duration
+// is negative.
+func (z *zEntry) AddInterval(key string, start, end time.Time) Entry {
+	z.ev.Time(key+".start", start.UTC())
+	z.ev.Time(key+".end", end.UTC())
+	z.ev.Dur(key+".duration", end.Sub(start))
+	return z
+}
+
+// AddValidationErrors adds the results of a failed validation to the log statement as nested object that maps each
+// invalid field to its error message, e.g. {"email": "must not be empty"}. A nil or empty map adds nothing.
+func (z *zEntry) AddValidationErrors(key string, errs map[string]string) Entry {
+	if len(errs) == 0 {
+		return z
+	}
+	z.ev.Interface(key, errs)
+	return z
+}
+
+// AddGeo adds coordinates to the log statement as nested object with the keys "lat" and "lon", which Elasticsearch
+// maps as geo_point. If the latitude isn't within [-90, 90] or the longitude isn't within [-180, 180], the
+// coordinates are still added and "${key}_invalid" is set to true.
+func (z *zEntry) AddGeo(key string, lat, lon float64) Entry {
+	p := geoPoint{lat, lon}
+	z.ev.Interface(key, p)
+	if !p.valid() {
+		z.ev.Bool(key+"_invalid", true)
+	}
+	return z
+}
+
+// Retention sets the retention class of the entry under the key "retention", e.g. "short", "long" or "audit", so
+// that the log pipeline can route it to a storage tier. It overrides the class set with DefaultRetention.
+func (z *zEntry) Retention(class string) Entry {
+	z.retention = class
+	return z
+}
+
+// AddDiff adds the old and the new value of a change to the log statement as nested object with the keys "old" and
+// "new". If both values are equal according to reflect.DeepEqual, nothing is added.
+func (z *zEntry) AddDiff(key string, oldVal, newVal interface{}) Entry {
+	if reflect.DeepEqual(oldVal, newVal) {
+		return z
+	}
+	z.ev.Interface(key, diff{oldVal, newVal})
+	return z
+}
+
+// AddRuntime adds diagnostics of the Go runtime to the log statement: the Go version, the operating system and the
+// architecture under the keys "go.version", "go.os" and "go.arch", the number of goroutines under the key
+// "go.goroutines" and the allocated heap in MiB under the key "go.heap_mb". Reading the heap stops the world, so use
+// it for rare entries like crash reports, not in hot paths.
+func (z *zEntry) AddRuntime() Entry {
+	z.ev.Str("go.version", runtime.Version())
+	z.ev.Str("go.os", runtime.GOOS)
+	z.ev.Str("go.arch", runtime.GOARCH)
+	z.ev.Int("go.goroutines", runtime.NumGoroutine())
+	z.ev.Float64("go.heap_mb", heapMB())
+	return z
+}
+
+// AddForm adds the values of the listed form fields to the log statement under the keys "form.${name}". Multiple
+// values of a field are joined with commas. Fields that aren't listed are never added, e.g. passwords, and listed
+// fields missing from values are skipped.
+func (z *zEntry) AddForm(values url.Values, keys ...string) Entry {
+	for _, k := range keys {
+		if vs, ok := values[k]; ok {
+			z.AddStr("form."+k, strings.Join(vs, ","))
+		}
+	}
+	return z
+}
+
+// AddBackoff computes the wait before the next attempt of a retry loop and adds it to the log statement together with
+// the attempt under the keys "backoff.wait" and "backoff.attempt". The wait doubles with each attempt, starting at
+// base for attempt 1, and is capped at max. Half of it is random jitter, so that clients don't retry in lockstep.
+// The wait is returned so that the caller can sleep on it.
+func (z *zEntry) AddBackoff(attempt int, base, max time.Duration) (Entry, time.Duration) {
+	d := backoff(attempt, base, max)
+	z.ev.Int("backoff.attempt", attempt)
+	z.ev.Dur("backoff.wait", d)
+	return z, d
+}
+
+// AddLabels adds a set of labels like environment, team or service to the log statement as nested object under the
+// key "labels". Label names that don't match the Prometheus label name syntax [a-zA-Z_][a-zA-Z0-9_]* are still added
+// and listed under the key "labels_invalid". Labels of a logger created by WithLabels are merged with labels, with
+// labels taking precedence. A nil or empty map adds nothing.
+func (z *zEntry) AddLabels(labels map[string]string) Entry {
+	return addLabels(z, labels)
+}
+
+// AddPercent adds a fraction like a success ratio to the log statement under key and the same value as percentage
+// string with one decimal under "${key}_pct", e.g. 0.873 as "87.3%", so that dashboards don't mix fractions and
+// percentages. A fraction outside [0, 1] is clamped and "${key}_invalid" is set to true, NaN is clamped to 0.
+func (z *zEntry) AddPercent(key string, fraction float64) Entry {
+	f, pct, ok := percent(fraction)
+	z.ev.Float64(key, f).Str(key+"_pct", pct)
+	if !ok {
+		z.ev.Bool(key+"_invalid", true)
+	}
+	return z
+}
+
+// AddNullString adds the value of a sql.NullString to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (z *zEntry) AddNullString(key string, val sql.NullString) Entry {
+	if !val.Valid {
+		if z.cfg.nilNull {
+			return z.AddAny(key, nil)
+		}
+		return z
+	}
+	return z.AddStr(key, val.String)
+}
+
+// AddNullInt64 adds the value of a sql.NullInt64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (z *zEntry) AddNullInt64(key string, val sql.NullInt64) Entry {
+	if !val.Valid {
+		if z.cfg.nilNull {
+			return z.AddAny(key, nil)
+		}
+		return z
+	}
+	return z.AddAny(key, val.Int64)
+}
+
+// AddNullFloat64 adds the value of a sql.NullFloat64 to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (z *zEntry) AddNullFloat64(key string, val sql.NullFloat64) Entry {
+	if !val.Valid {
+		if z.cfg.nilNull {
+			return z.AddAny(key, nil)
+		}
+		return z
+	}
+	return z.AddAny(key, val.Float64)
+}
+
+// AddNullBool adds the value of a sql.NullBool to the log statement, e.g. a column read
+// from a database. A NULL value omits the field, or adds null with the option NilAsNull.
+func (z *zEntry) AddNullBool(key string, val sql.NullBool) Entry {
+	if !val.Valid {
+		if z.cfg.nilNull {
+			return z.AddAny(key, nil)
+		}
+		return z
+	}
+	return z.AddBool(key, val.Bool)
+}
+
+// AddKV adds alternating keys and values to the log statement, e.g. AddKV("user", id, "ok", true), like AddAny for
+// each pair. It trades the type safety of the typed methods for brevity. Keys that aren't strings are formatted with
+// fmt.Sprint. If the number of arguments is odd, the last key is added under "kv_warning" as missing its value.
+func (z *zEntry) AddKV(pairs ...interface{}) Entry {
+	return addKV(z, pairs)
+}
+
+// AddBigRat adds an arbitrary-precision rational number to the log statement as string, so that exact values like
+// amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default. nil
+// is added as "<nil>".
+func (z *zEntry) AddBigRat(key string, val *big.Rat) Entry {
+	return z.AddStr(key, formatRat(val, z.cfg.bigPrec))
+}
+
+// AddBigFloat adds an arbitrary-precision floating-point number to the log statement as string, so that exact values
+// like amounts of money aren't rounded to float64. The precision is set with the option BigPrecision, exact by default.
+// nil is added as "<nil>".
+func (z *zEntry) AddBigFloat(key string, val *big.Float) Entry {
+	return z.AddStr(key, formatBigFloat(val, z.cfg.bigPrec))
+}
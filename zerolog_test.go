@@ -1,15 +1,34 @@
 package logger
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestZLog_WithAny(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend).WithAny("tenant_id", 42)
+	l.Debug().Flush("first")
+	l.Debug().Flush("second")
+	s := sb.String()
+	assert.Equal(t, 2, strings.Count(s, `"tenant_id":42`), "Every log message should contain the field")
+}
+
 func TestZLog_Level(t *testing.T) {
 	for _, test := range tests() {
 		var sb strings.Builder
@@ -107,6 +126,25 @@ func TestZLog_Error(t *testing.T) {
 	}
 }
 
+func TestZLog_Fatal(t *testing.T) {
+	for _, test := range tests() {
+		var sb strings.Builder
+		l := New(&sb, test.lvl, ZeroLogBackend)
+		code := exitCode(func() { l.Fatal().AddAny(test.key, test.val).Flush("Additional Message") })
+		assert.Equal(t, 1, code, "Call to Fatal level should exit with code 1")
+		s := sb.String()
+		if test.lvl >= FatalLevel {
+			msg := fmt.Sprintf("Logger with level %d should print Fatal messages", test.lvl)
+			assert.Contains(t, s, test.key, msg)
+		} else {
+			msg := fmt.Sprintf("Logger with level %d should not print Fatal messages", test.lvl)
+			assert.NotContains(t, s, test.key, msg)
+		}
+	}
+	code := exitCode(func() { New(&strings.Builder{}, DebugLevel, ZeroLogBackend).Error().Flush("") })
+	assert.Equal(t, -1, code, "Call to Error level should not exit")
+}
+
 func TestZLog_Panic(t *testing.T) {
 	for _, test := range tests() {
 		var sb strings.Builder
@@ -229,3 +267,492 @@ func TestZEntry_AddTime(t *testing.T) {
 	s := sb.String()
 	assert.Contains(t, s, key, "Message should contain key")
 }
+
+func TestZEntry_AddJSONRaw(t *testing.T) {
+	key := "jsonkey"
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddJSONRaw(key, []byte(`{"inner":[1,2]}`)).AddJSONRaw("other", []byte("{no json")).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"jsonkey":{"inner":[1,2]}`, "Message should contain embedded JSON")
+	assert.Contains(t, s, `"other_invalid":"{no json"`, "Message should contain invalid JSON as string")
+}
+
+func TestZEntry_At(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, InfoLevel, ZeroLogBackend)
+	l.Debug().AddStr("raised", "val").At(WarnLevel).Flush("")
+	l.Warn().AddStr("lowered", "val").At(DebugLevel).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "raised", "Entry raised above the logger level should be printed")
+	assert.Contains(t, s, `"level":"warn"`, "Entry should be printed at the new level")
+	assert.NotContains(t, s, "lowered", "Entry lowered below the logger level should not be printed")
+}
+
+func TestZEntry_AtMovesFields(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, InfoLevel, ZeroLogBackend).WithField("service", "api")
+	l.Info().AddStr("key", "val").At(ErrorLevel).Flush("message")
+	s := sb.String()
+	assert.Contains(t, s, `"level":"error"`, "Entry should be printed at the new level")
+	assert.Contains(t, s, `"key":"val"`, "Fields should be kept when the level changes")
+	assert.Equal(t, 1, strings.Count(s, `"level"`), "Level should be printed once")
+	assert.Equal(t, 1, strings.Count(s, `"service"`), "Fields of the logger should be printed once")
+
+	sb.Reset()
+	zl := zerolog.New(&sb).With().Str("app", "test").Logger()
+	FromZerolog(&zl).Warn().AddStr("key", "val").At(ErrorLevel).Flush("")
+	s = sb.String()
+	assert.Contains(t, s, `"level":"error"`, "Entry should be printed at the new level")
+	assert.Contains(t, s, `"key":"val"`, "Fields should be kept when the level changes")
+	assert.Equal(t, 1, strings.Count(s, `"app"`), "Context of the zerolog logger should be printed once")
+}
+
+func BenchmarkZLog_Info(b *testing.B) {
+	l := New(ioutil.Discard, InfoLevel, ZeroLogBackend)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info().AddStr("str", "value").AddInt("int", i).AddBool("bool", true).AddDur("dur", time.Second).Flush("message")
+	}
+}
+
+func BenchmarkZLog_Disabled(b *testing.B) {
+	l := New(ioutil.Discard, InfoLevel, ZeroLogBackend)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Debug().AddStr("str", "value").AddInt("int", i).AddBool("bool", true).AddDur("dur", time.Second).Flush("message")
+	}
+}
+
+func TestZEntry_AddCount(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddCount("items", 3, "item", "items").AddCount("files", 1, "file", "files").Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"items":3`, "Message should contain count")
+	assert.Contains(t, s, "3 items", "Message should contain plural form")
+	assert.Contains(t, s, "1 file\"", "Message should contain singular form")
+}
+
+func TestZEntry_AddQuery(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddQuery("SELECT * FROM users WHERE id = ?", []interface{}{42, "a"}, 7, time.Second).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"db.sql":"SELECT * FROM users WHERE id = ?"`, "Message should contain statement")
+	assert.Contains(t, s, `"db.args":[42,"a"]`, "Message should contain arguments as array")
+	assert.Contains(t, s, `"db.rows":7`, "Message should contain rows")
+	assert.Contains(t, s, "db.duration", "Message should contain duration")
+}
+
+func TestZEntry_AddTraceparent(t *testing.T) {
+	h := http.Header{}
+	h.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddTraceparent("parent", h).AddTraceparent("missing", http.Header{}).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "parent_trace_id", "Message should contain trace id key")
+	assert.Contains(t, s, "4bf92f3577b34da6a3ce929d0e0e4736", "Message should contain trace id")
+	assert.Contains(t, s, "parent_span_id", "Message should contain span id key")
+	assert.Contains(t, s, "00f067aa0ba902b7", "Message should contain span id")
+	assert.NotContains(t, s, "missing", "Message should not contain missing header")
+}
+
+func TestZEntry_AddMetric(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddMetric("payment.amount", 1299, map[string]string{"currency": "USD"}).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"metric":{"name":"payment.amount","value":1299,"tags":{"currency":"USD"}}`, "Message should contain metric")
+}
+
+func TestZEntry_AddHex(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddHex("hexkey", []byte{0x00, 0xff, 0x10}).AddHex("empty", nil).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"hexkey":"00ff10"`, "Message should contain hex value")
+	assert.Contains(t, s, `"empty":""`, "Message should contain empty value")
+}
+
+func TestZEntry_AddBase64(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddBase64("b64key", []byte("hi??>>")).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"b64key":"aGk/Pz4+"`, "Message should contain base64 value")
+}
+
+func TestZEntry_AddElapsed(t *testing.T) {
+	key := "elapsedkey"
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddElapsed(key, time.Now().Add(-time.Minute)).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"elapsedkey":6000`, "Message should contain key")
+}
+
+func TestZEntry_NoTime(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().Flush("")
+	assert.Contains(t, sb.String(), `"time"`, "Message should contain time by default")
+	sb.Reset()
+	l.Info().NoTime().Flush("")
+	assert.NotContains(t, sb.String(), `"time"`, "Message should not contain time")
+}
+
+func TestZEntry_AddDurHuman(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddDurHuman("secs", 1200*time.Millisecond).AddDurHuman("mins", 3*time.Minute+4*time.Second).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"secs":"1.2s"`, "Message should contain seconds")
+	assert.Contains(t, s, `"mins":"3m04s"`, "Message should contain minutes")
+}
+
+func TestZEntry_AddErrN(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	err := errors.Annotate(errors.Annotate(errors.New("asd"), "other err"), "third err")
+	l.Info().AddErrN(err, 1).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, "err_stack", "Message should contain error stack")
+	assert.Contains(t, s, "...2 more", "Error stack should be truncated")
+	assert.Contains(t, s, "third err: other err: asd", "Message should contain full error mesage")
+}
+
+func TestZEntry_AddSensitive(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddSensitive("email", "a@b.c").Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"email_hash":"d648b243a3e817eaa3309e00e183483f2867baadf522099f0c2121770536b25a"`, "Message should contain hash")
+	assert.NotContains(t, s, "a@b.c", "Message should not contain raw value")
+}
+
+func TestZEntry_AddBytesLen(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddBytesLen("size", []byte("secret")).AddBytesLen("nil", nil).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"size":6`, "Message should contain length")
+	assert.Contains(t, s, `"nil":0`, "Message should contain length of nil slice")
+	assert.NotContains(t, s, "secret", "Message should not contain content")
+}
+
+func TestZEntry_AddStrLen(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddStrLen("len", "héllo").Flush("")
+	assert.Contains(t, sb.String(), `"len":5`, "Message should contain rune count")
+}
+
+func TestZEntry_AddJSONString(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddJSONString("jsonkey", `{"inner":[1,2]}`).AddJSONString("other", "{no json").Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"jsonkey":{"inner":[1,2]}`, "Message should contain embedded JSON")
+	assert.Contains(t, s, `"other_invalid":"{no json"`, "Message should contain invalid JSON as string")
+}
+
+func TestZEntry_AddRetry(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Warn().AddRetry(2, 5, 1500*time.Millisecond).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"retry.attempt":2`, "Message should contain attempt")
+	assert.Contains(t, s, `"retry.max":5`, "Message should contain max")
+	assert.Contains(t, s, `"retry.backoff":1500`, "Message should contain backoff")
+}
+
+func TestZEntry_AddDeadline(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	l.Info().AddDeadline("deadline", ctx).AddDeadline("none", context.Background()).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"deadline":`, "Message should contain remaining time")
+	assert.NotContains(t, s, "none", "Context without deadline should add nothing")
+}
+
+func TestZEntry_AddRequest(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	r := httptest.NewRequest("POST", "http://example.com/users?id=1", nil)
+	r.Header.Set("User-Agent", "curl/7.64")
+	r.Header.Set("Authorization", "Bearer secret")
+	l.Info().AddRequest(r).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"http.method":"POST"`, "Message should contain method")
+	assert.Contains(t, s, `"http.path":"/users"`, "Message should contain path")
+	assert.Contains(t, s, `"http.remote_addr":"192.0.2.1:1234"`, "Message should contain remote address")
+	assert.Contains(t, s, `"http.user_agent":"curl/7.64"`, "Message should contain user agent")
+	assert.Contains(t, s, `"http.host":"example.com"`, "Message should contain host")
+	assert.NotContains(t, s, "secret", "Message should not contain authorization")
+}
+
+func TestZEntry_AddResponse(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddResponse(503, 42, 1500*time.Millisecond).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"http.status":503`, "Message should contain status")
+	assert.Contains(t, s, `"http.status_class":"5xx"`, "Message should contain status class")
+	assert.Contains(t, s, `"http.bytes":42`, "Message should contain size")
+	assert.Contains(t, s, `"http.duration":1500`, "Message should contain duration")
+}
+
+func TestZEntry_AddSlice(t *testing.T) {
+	type item struct {
+		ID int `json:"id"`
+	}
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddSlice("items", []item{{1}, {2}}).AddSlice("empty", []item(nil)).AddSlice("array", [2]int{3, 4}).AddSlice("other", 5).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"items":[{"id":1},{"id":2}]`, "Message should contain JSON array")
+	assert.Contains(t, s, `"empty":[]`, "Message should contain empty array")
+	assert.Contains(t, s, `"array":[3,4]`, "Message should contain array")
+	assert.Contains(t, s, `"other":5`, "Message should contain other values")
+}
+
+func TestZEntry_AddPtr(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	b, i, s := true, 42, "val"
+	l.Info().AddBoolPtr("bool", &b).AddIntPtr("int", &i).AddStrPtr("str", &s).Flush("")
+	l.Info().AddBoolPtr("nilbool", nil).AddIntPtr("nilint", nil).AddStrPtr("nilstr", nil).Flush("")
+	out := sb.String()
+	assert.Contains(t, out, `"bool":true`, "Message should contain bool")
+	assert.Contains(t, out, `"int":42`, "Message should contain int")
+	assert.Contains(t, out, `"str":"val"`, "Message should contain string")
+	assert.NotContains(t, out, "nil", "Nil pointers should be omitted")
+}
+
+func TestZEntry_AddThreshold(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddIntThreshold("rows", 5000, 1000, "large").AddDurThreshold("dur", time.Millisecond, time.Second, "slow").Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"rows":5000`, "Message should contain value")
+	assert.Contains(t, s, `"large":true`, "Message should contain flag above threshold")
+	assert.Contains(t, s, "dur", "Message should contain value")
+	assert.NotContains(t, s, "slow", "Message should not contain flag below threshold")
+}
+
+func TestZEntry_Discard(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddStr("key", "val").Discard()
+	assert.Empty(t, sb.String(), "Discarded entry should not be written")
+}
+
+func TestZEntry_AddMoney(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddMoney("price", 1299, "usd").Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"price.amount_minor":1299`, "Amount should be stored in minor units")
+	assert.Contains(t, s, `"price.currency":"USD"`, "Currency should be stored in upper case")
+	assert.Contains(t, s, `"price.display":"$12.99"`, "Formatted amount should be stored")
+	assert.NotContains(t, s, "currency_unknown", "Known currencies should not be flagged")
+
+	sb.Reset()
+	l.Info().AddMoney("price", 1299, "").Flush("")
+	s = sb.String()
+	assert.Contains(t, s, `"price.display":"12.99"`, "An empty currency should not add spaces")
+	assert.Contains(t, s, `"price.currency_unknown":true`, "An empty currency should be flagged")
+	sb.Reset()
+	l.Info().AddMoney("price", 1299, "xyz").Flush("")
+	assert.Contains(t, sb.String(), `"price.currency_unknown":true`, "Unlisted currencies should be flagged")
+}
+
+func TestZEntry_AddErrChain(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	err := &wrapErr{"outer", errors.Trace(fmt.Errorf("root"))}
+	l.Info().AddErrChain("chain", err).AddErrChain("nil", nil).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"chain":"outer: root"`, "Entry should contain the message")
+	assert.Contains(t, s, `"chain_depth":2`, "Entry should contain the number of wrapped errors")
+	assert.Contains(t, s, `"chain_types":["*logger.wrapErr","*errors.Err","*errors.errorString"]`,
+		"Entry should contain the types of the chain")
+	assert.NotContains(t, s, "nil", "Nil errors should add nothing")
+}
+
+func TestZEntry_AddInterval(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	start := time.Date(2019, 3, 1, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	l.Info().AddInterval("window", start, start.Add(-90*time.Minute)).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"window.start":1551427200`, "Entry should contain the start")
+	assert.Contains(t, s, `"window.end":1551421800`, "Entry should contain the end")
+	assert.Contains(t, s, `"window.duration":-5400000`, "Duration should be negative if end is before start")
+}
+
+func TestZEntry_AddValidationErrors(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	errs := map[string]string{"email": "must not be empty", "name": "too long"}
+	l.Info().AddValidationErrors("validation", errs).AddValidationErrors("none", nil).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"validation":{"email":"must not be empty","name":"too long"}`,
+		"Errors should be added as nested object")
+	assert.NotContains(t, s, "none", "Empty maps should add nothing")
+}
+
+func TestZEntry_Bytes(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, WarnLevel, ZeroLogBackend)
+	e := l.Info().AddStr("key", "val")
+	b, err := e.Bytes()
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "val", "Bytes should contain the fields")
+	assert.True(t, strings.HasSuffix(string(b), "\n"), "Bytes should be formatted like a written entry")
+	assert.Empty(t, sb.String(), "Bytes should not write the entry")
+	e.At(ErrorLevel).Flush("message")
+	assert.Contains(t, sb.String(), "val", "Entry should be flushed after Bytes")
+}
+
+func TestZEntry_AddGeo(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddGeo("location", 52.52, 13.405).AddGeo("off", 91, 0).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"location":{"lat":52.52,"lon":13.405}`, "Entry should contain the coordinates")
+	assert.NotContains(t, s, "location_invalid", "Valid coordinates should not be flagged")
+	assert.Contains(t, s, `"off":{"lat":91,"lon":0}`, "Invalid coordinates should be kept")
+	assert.Contains(t, s, `"off_invalid":true`, "Invalid coordinates should be flagged")
+}
+
+func TestZEntry_Retention(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().Retention("audit").Flush("")
+	assert.Contains(t, sb.String(), `"retention":"audit"`, "Entry should contain the retention class")
+
+	sb.Reset()
+	l = New(&sb, DebugLevel, ZeroLogBackend, DefaultRetention("hot"))
+	l.Info().Flush("")
+	assert.Contains(t, sb.String(), `"retention":"hot"`, "Entry should contain the default retention class")
+	sb.Reset()
+	l.Info().Retention("audit").Flush("")
+	assert.Contains(t, sb.String(), `"retention":"audit"`, "Retention should override the default")
+	assert.NotContains(t, sb.String(), "hot", "Default should not be added if the entry sets a class")
+}
+
+func TestZEntry_AddDiff(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddDiff("timeout", 10, 30).AddDiff("hosts", []string{"a"}, nil).
+		AddDiff("unchanged", []string{"a"}, []string{"a"}).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"timeout":{"old":10,"new":30}`, "Entry should contain the old and the new value")
+	assert.Contains(t, s, `"hosts":{"old":["a"],"new":null}`, "Entry should contain removed values")
+	assert.NotContains(t, s, "unchanged", "Equal values should add nothing")
+}
+
+func TestZEntry_AddRuntime(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddRuntime().Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"go.version":"`+runtime.Version()+`"`, "Entry should contain the Go version")
+	assert.Contains(t, s, `"go.os":"`+runtime.GOOS+`"`, "Entry should contain the operating system")
+	assert.Contains(t, s, `"go.arch":"`+runtime.GOARCH+`"`, "Entry should contain the architecture")
+	assert.Contains(t, s, `"go.goroutines":`, "Entry should contain the number of goroutines")
+	assert.Contains(t, s, `"go.heap_mb":`, "Entry should contain the heap")
+}
+
+func TestZEntry_AddForm(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	form := url.Values{"user": {"alice"}, "tags": {"a", "b"}, "password": {"secret"}}
+	l.Info().AddForm(form, "user", "tags", "missing").Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"form.user":"alice"`, "Listed fields should be added")
+	assert.Contains(t, s, `"form.tags":"a,b"`, "Multiple values should be joined with commas")
+	assert.NotContains(t, s, "secret", "Fields that aren't listed should never be added")
+	assert.NotContains(t, s, "missing", "Missing fields should be skipped")
+}
+
+func TestZEntry_AddBackoff(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	e, d := l.Warn().AddBackoff(3, time.Second, time.Minute)
+	e.Flush("")
+	s := sb.String()
+	assert.True(t, d >= 2*time.Second && d <= 4*time.Second, "Wait should be 4s with jitter, got %s", d)
+	assert.Contains(t, s, `"backoff.attempt":3`, "Message should contain attempt")
+	assert.Contains(t, s, `"backoff.wait":`, "Message should contain wait")
+}
+
+func TestZEntry_AddLabels(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddLabels(map[string]string{"team": "core", "app-name": "api"}).AddLabels(nil).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"labels":{"app-name":"api","team":"core"}`, "Labels should be added as nested object")
+	assert.Contains(t, s, `"labels_invalid":["app-name"]`, "Invalid label names should be flagged")
+}
+
+func TestZEntry_AddPercent(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddPercent("ratio", 0.8734).AddPercent("over", 1.2).AddPercent("nan", math.NaN()).Flush("")
+	s := sb.String()
+	assert.Contains(t, s, `"ratio":0.8734,"ratio_pct":"87.3%"`, "Entry should contain the fraction and the percentage")
+	assert.NotContains(t, s, "ratio_invalid", "Valid fractions should not be flagged")
+	assert.Contains(t, s, `"over":1,"over_pct":"100.0%","over_invalid":true`, "Fractions above 1 should be clamped")
+	assert.Contains(t, s, `"nan":0,"nan_pct":"0.0%","nan_invalid":true`, "NaN should be clamped to 0")
+}
+
+func TestZEntry_AddNull(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().
+		AddNullString("str", sql.NullString{String: "val", Valid: true}).
+		AddNullInt64("int", sql.NullInt64{Int64: 42, Valid: true}).
+		AddNullFloat64("float", sql.NullFloat64{Float64: 1.5, Valid: true}).
+		AddNullBool("bool", sql.NullBool{Bool: true, Valid: true}).
+		Flush("")
+	l.Info().AddNullString("nullstr", sql.NullString{String: "ignored"}).AddNullInt64("nullint", sql.NullInt64{}).Flush("")
+	out := sb.String()
+	assert.Contains(t, out, `"str":"val","int":42,"float":1.5,"bool":true`, "Valid values should be added")
+	assert.NotContains(t, out, "null", "NULL values should be omitted")
+	assert.NotContains(t, out, "ignored", "NULL values should be omitted")
+
+	sb.Reset()
+	l = New(&sb, DebugLevel, ZeroLogBackend, NilAsNull())
+	l.Info().AddNullFloat64("float", sql.NullFloat64{}).AddNullBool("bool", sql.NullBool{}).Flush("")
+	assert.Contains(t, sb.String(), `"float":null,"bool":null`, "NULL values should be null with NilAsNull")
+}
+
+func TestZEntry_AddKV(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddKV("user", 42, "action", "login", "ok", true, 7, "seven").Flush("")
+	l.Info().AddKV("user", 42, "dangling").Flush("")
+	out := sb.String()
+	assert.Contains(t, out, `"user":42,"action":"login","ok":true,"7":"seven"`, "Pairs should be added in order")
+	assert.Contains(t, out, `"kv_warning":"missing value for key dangling"`, "An odd count should be flagged")
+}
+
+func TestZEntry_AddBig(t *testing.T) {
+	var sb strings.Builder
+	l := New(&sb, DebugLevel, ZeroLogBackend)
+	l.Info().AddBigRat("rat", big.NewRat(1, 3)).AddBigFloat("float", big.NewFloat(0.25)).AddBigRat("nil", nil).Flush("")
+	assert.Contains(t, sb.String(), `"rat":"1/3","float":"0.25","nil":"<nil>"`, "Values should be added exactly")
+
+	sb.Reset()
+	l = New(&sb, DebugLevel, ZeroLogBackend, BigPrecision(3))
+	l.Info().AddBigRat("rat", big.NewRat(2, 3)).AddBigFloat("float", big.NewFloat(0.25)).Flush("")
+	assert.Contains(t, sb.String(), `"rat":"0.667","float":"0.250"`, "Values should be written with the precision")
+}